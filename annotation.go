@@ -0,0 +1,294 @@
+package swago
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"github.com/javiercbk/swago/encoding/swagger"
+	swagoErrors "github.com/javiercbk/swago/errors"
+	"github.com/javiercbk/swago/pkg"
+)
+
+const (
+	noSecurity        = "none"
+	requiredParameter = "required"
+)
+
+var (
+	// annotatedTypeRegExp matches the go types of the annotations as in []*pkg.Struct
+	annotatedTypeRegExp = regexp.MustCompile(`^(\*|\[\])*(\w+\.)?\w+$`)
+	// exportedTypeRegExp matches the qualified exported types as in pkg.Struct, a word
+	// as in e.g. is not one
+	exportedTypeRegExp = regexp.MustCompile(`^(\*|\[\])*\w+\.[A-Z]\w*$`)
+	// parameterLocations are the values of the in of a parameter
+	parameterLocations = []string{"query", "header", "path", "formData", "body"}
+)
+
+// ignoredAnnotation is returned for an annotation that is ignored, it explains why
+type ignoredAnnotation string
+
+func (e ignoredAnnotation) Error() string {
+	return string(e)
+}
+
+// applyAnnotations overrides or supplements an operation with the swago
+// annotations found in the doc comment of its handler, an annotation that is unknown,
+// invalid or whose type is not found is reported and ignored
+func (s *SwaggerGenerator) applyAnnotations(r *pkg.Route, operation *openapi2.Operation, projectCriterias criteria.Criteria, swaggerDoc *openapi2.Swagger) error {
+	var security *openapi2.SecurityRequirements
+	for _, a := range r.Handler.Annotations {
+		var err error
+		switch a.Name {
+		case pkg.AnnotationTags:
+			operation.Tags = a.Args
+		case pkg.AnnotationOperationID:
+			if len(a.Args) > 0 {
+				operation.OperationID = a.Args[0]
+			}
+		case pkg.AnnotationDeprecated:
+			s.deprecateOperation(operation)
+		case pkg.AnnotationResponse:
+			err = s.annotatedResponse(a, operation, projectCriterias, swaggerDoc)
+		case pkg.AnnotationParam:
+			err = s.annotatedParameter(a, operation, projectCriterias, swaggerDoc)
+		case pkg.AnnotationSecurity:
+			if security == nil {
+				// annotated security replaces the inferred one
+				security = &openapi2.SecurityRequirements{}
+			}
+			if len(a.Args) == 0 || a.Args[0] == noSecurity {
+				continue
+			}
+			// every annotation must be satisfied, alternatives are separated by "|"
+			*security = combineSecurity(*security, annotatedSecurityAlternatives(a.Args))
+		case pkg.AnnotationIgnore:
+		default:
+			err = ignoredAnnotation(a.Name + " is not a swago annotation")
+		}
+		if err == swagoErrors.ErrNotFound {
+			err = ignoredAnnotation("its type is not found")
+		}
+		if reason, ok := err.(ignoredAnnotation); ok {
+			s.report(diagnostic.Warning, r, "", r.Handler.PositionOf(a.Pos), "annotation %s is ignored, %s", annotationText(a), reason)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if security != nil {
+		operation.Security = security
+	}
+	return nil
+}
+
+// deprecateOperation marks an operation as deprecated
+func (s *SwaggerGenerator) deprecateOperation(operation *openapi2.Operation) {
+	s.Extensions.SetOperationProp(operation, swagger.DeprecatedProp, true)
+}
+
+// annotatedResponse adds a response declared as "swago:response code [type] [description]"
+func (s *SwaggerGenerator) annotatedResponse(a pkg.Annotation, operation *openapi2.Operation, projectCriterias criteria.Criteria, swaggerDoc *openapi2.Swagger) error {
+	if len(a.Args) == 0 {
+		return ignoredAnnotation("it has no status code")
+	}
+	code := a.Args[0]
+	resp := &openapi2.Response{}
+	if code != criteria.DefaultResponseCode {
+		httpStatusCode, _ := parseCode(code)
+		if httpStatusCode == 0 {
+			return ignoredAnnotation("its status code " + code + " is unknown")
+		}
+		code = strconv.Itoa(httpStatusCode)
+		resp.Description = http.StatusText(httpStatusCode)
+	}
+	descriptionArgs := a.Args[1:]
+	if len(descriptionArgs) > 0 && !s.isAnnotatedType(descriptionArgs[0], projectCriterias) && exportedTypeRegExp.MatchString(descriptionArgs[0]) {
+		pkgName, _ := pkg.TypeParts(strings.TrimLeft(descriptionArgs[0], "*[]"))
+		return ignoredAnnotation("package " + pkgName + " of its type is not part of the project")
+	}
+	if len(descriptionArgs) > 0 && s.isAnnotatedType(descriptionArgs[0], projectCriterias) {
+		schemaRef, err := s.typeSchemaRef(descriptionArgs[0], true, projectCriterias, swaggerDoc)
+		if err != nil {
+			return err
+		}
+		resp.Schema = schemaRef
+		descriptionArgs = descriptionArgs[1:]
+	}
+	if len(descriptionArgs) > 0 {
+		resp.Description = strings.Join(descriptionArgs, " ")
+	}
+	if operation.Responses == nil {
+		operation.Responses = make(map[string]*openapi2.Response)
+	}
+	operation.Responses[code] = resp
+	return nil
+}
+
+// annotatedParameter adds a parameter declared as "swago:param in name type [required] [description]"
+func (s *SwaggerGenerator) annotatedParameter(a pkg.Annotation, operation *openapi2.Operation, projectCriterias criteria.Criteria, swaggerDoc *openapi2.Swagger) error {
+	if len(a.Args) < 3 {
+		return ignoredAnnotation("it needs the in, the name and the type of the parameter")
+	}
+	if !isParameterLocation(a.Args[0]) {
+		return ignoredAnnotation("in " + a.Args[0] + " is not one of " + strings.Join(parameterLocations, ", "))
+	}
+	parameter := &openapi2.Parameter{
+		In:   a.Args[0],
+		Name: a.Args[1],
+	}
+	descriptionArgs := a.Args[3:]
+	if len(descriptionArgs) > 0 && descriptionArgs[0] == requiredParameter {
+		parameter.Required = true
+		descriptionArgs = descriptionArgs[1:]
+	}
+	parameter.Description = strings.Join(descriptionArgs, " ")
+	if parameter.In == "path" {
+		parameter.Required = true
+	}
+	if parameter.In == "body" {
+		schemaRef, err := s.typeSchemaRef(a.Args[2], false, projectCriterias, swaggerDoc)
+		if err != nil {
+			return err
+		}
+		parameter.Schema = schemaRef
+	} else {
+		t, format := pkg.SwaggerType(a.Args[2])
+		if len(t) == 0 {
+			// the type is already a swagger type
+			t = a.Args[2]
+		}
+		parameter.Type = t
+		if t == "array" {
+			parameter.Items = &openapi3.SchemaRef{
+				Value: &openapi3.Schema{
+					Type: format,
+				},
+			}
+		} else {
+			parameter.Format = format
+		}
+	}
+	for i, p := range operation.Parameters {
+		if p.In == parameter.In && p.Name == parameter.Name {
+			operation.Parameters[i] = parameter
+			return nil
+		}
+	}
+	operation.Parameters = append(operation.Parameters, parameter)
+	return nil
+}
+
+// isParameterLocation returns true if a value is the in of a parameter
+func isParameterLocation(in string) bool {
+	for _, l := range parameterLocations {
+		if l == in {
+			return true
+		}
+	}
+	return false
+}
+
+// annotationText returns an annotation as it is written in a doc comment
+func annotationText(a pkg.Annotation) string {
+	return strings.TrimSpace(pkg.AnnotationPrefix + a.Name + " " + strings.Join(a.Args, " "))
+}
+
+// isAnnotatedType returns true if an annotation argument is a type and not a word, a
+// qualified type is only a type when its package is part of the project
+func (s *SwaggerGenerator) isAnnotatedType(arg string, projectCriterias criteria.Criteria) bool {
+	if _, ok := projectCriterias.StaticModels[arg]; ok {
+		return true
+	}
+	if !annotatedTypeRegExp.MatchString(arg) {
+		return false
+	}
+	goType := strings.TrimLeft(arg, "*[]")
+	if _, ok := projectCriterias.StaticModels[goType]; ok {
+		return true
+	}
+	if t, _ := pkg.SwaggerType(goType); len(t) > 0 {
+		return true
+	}
+	pkgName, _ := pkg.TypeParts(goType)
+	return len(pkgName) > 0 && s.getPkg(pkgName) != nil
+}
+
+// typeSchemaRef returns the schema of a go type or a static model, response is true
+// when the type is sent in a response. Structs are definitions when there is a definition prefix
+func (s *SwaggerGenerator) typeSchemaRef(goType string, response bool, projectCriterias criteria.Criteria, swaggerDoc *openapi2.Swagger) (*openapi3.SchemaRef, error) {
+	return s.modelSchemaRef(goType, response, len(projectCriterias.DefinitionPrefix) > 0, projectCriterias, swaggerDoc)
+}
+
+// modelSchemaRef returns the schema of a go type or a static model, structs are
+// referenced from the definitions when definition is true and inlined otherwise
+func (s *SwaggerGenerator) modelSchemaRef(goType string, response, definition bool, projectCriterias criteria.Criteria, swaggerDoc *openapi2.Swagger) (*openapi3.SchemaRef, error) {
+	goType = strings.TrimPrefix(goType, "*")
+	if strings.HasPrefix(goType, "[]") {
+		items, err := s.modelSchemaRef(goType[2:], response, definition, projectCriterias, swaggerDoc)
+		if err != nil {
+			return nil, err
+		}
+		return &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				Type:  "array",
+				Items: items,
+			},
+		}, nil
+	}
+	t, format := pkg.SwaggerType(goType)
+	if len(t) > 0 {
+		return &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				Type:   t,
+				Format: format,
+			},
+		}, nil
+	}
+	staticModel, ok := projectCriterias.StaticModels[goType]
+	if ok {
+		definitionName := projectCriterias.DefinitionPrefix + goType
+		swaggerDoc.Definitions[definitionName] = &openapi3.SchemaRef{
+			Value: staticModel,
+		}
+		return &openapi3.SchemaRef{
+			Ref: "#/definitions/" + definitionName,
+		}, nil
+	}
+	pkgName, structName := pkg.TypeParts(goType)
+	model := pkg.Struct{
+		PkgName: pkgName,
+		Name:    structName,
+	}
+	pkgFound := s.getPkg(pkgName)
+	if pkgFound == nil {
+		return nil, swagoErrors.ErrNotFound
+	}
+	err := pkgFound.FindStruct(&model)
+	if err != nil {
+		return nil, err
+	}
+	model.Options = s.schemaOptions(projectCriterias, response)
+	err = documentModel(&model, projectCriterias, swaggerDoc)
+	if err != nil {
+		return nil, err
+	}
+	if !definition {
+		return &openapi3.SchemaRef{
+			Value: model.Schema,
+		}, nil
+	}
+	definitionName := projectCriterias.DefinitionPrefix + model.DefinitionName()
+	swaggerDoc.Definitions[definitionName] = &openapi3.SchemaRef{
+		Value: model.Schema,
+	}
+	return &openapi3.SchemaRef{
+		Ref: "#/definitions/" + definitionName,
+	}, nil
+}
@@ -0,0 +1,199 @@
+package swago
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"github.com/javiercbk/swago/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyAnnotations(t *testing.T) {
+	projectCriterias := criteria.Criteria{
+		StaticModels: map[string]*openapi3.Schema{
+			"ErrorBody": {Type: "object"},
+		},
+		DefinitionPrefix: "api.",
+	}
+	type params struct {
+		doc string
+	}
+	type expected struct {
+		operation   openapi2.Operation
+		deprecated  bool
+		definitions []string
+		diagnostics []string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:   "should replace the tags",
+			params: params{doc: "swago:tags users admin"},
+			expected: expected{
+				operation: openapi2.Operation{Tags: []string{"users", "admin"}},
+			},
+		},
+		{
+			name:   "should replace the operation id",
+			params: params{doc: "swago:operationId listUsers"},
+			expected: expected{
+				operation: openapi2.Operation{OperationID: "listUsers"},
+			},
+		},
+		{
+			name:   "should deprecate the operation",
+			params: params{doc: "swago:deprecated"},
+			expected: expected{
+				deprecated: true,
+			},
+		},
+		{
+			name:   "should add a response with a static model",
+			params: params{doc: "swago:response 404 ErrorBody the user does not exist"},
+			expected: expected{
+				operation: openapi2.Operation{Responses: map[string]*openapi2.Response{
+					"404": {Description: "the user does not exist", Schema: &openapi3.SchemaRef{Ref: "#/definitions/api.ErrorBody"}},
+				}},
+				definitions: []string{"api.ErrorBody"},
+			},
+		},
+		{
+			name:   "should not take a word of the description as a type",
+			params: params{doc: "swago:response 404 e.g. when the user does not exist"},
+			expected: expected{
+				operation: openapi2.Operation{Responses: map[string]*openapi2.Response{
+					"404": {Description: "e.g. when the user does not exist"},
+				}},
+			},
+		},
+		{
+			name:   "should not take a word of an unknown package as a type",
+			params: params{doc: "swago:response 400 docs.example has the details"},
+			expected: expected{
+				operation: openapi2.Operation{Responses: map[string]*openapi2.Response{
+					"400": {Description: "docs.example has the details"},
+				}},
+			},
+		},
+		{
+			name:   "should report and ignore a response whose type is not found",
+			params: params{doc: "swago:response 404 handler.Missing not found\nswago:tags users"},
+			expected: expected{
+				operation:   openapi2.Operation{Tags: []string{"users"}},
+				diagnostics: []string{"warning: annotation swago:response 404 handler.Missing not found is ignored, its type is not found (route GET /users)"},
+			},
+		},
+		{
+			name:   "should report and ignore a response whose type is of a package that is not part of the project",
+			params: params{doc: "swago:response 404 other.ErrorBody not found"},
+			expected: expected{
+				diagnostics: []string{"warning: annotation swago:response 404 other.ErrorBody not found is ignored, package other of its type is not part of the project (route GET /users)"},
+			},
+		},
+		{
+			name:   "should report and ignore a response with an unknown status code",
+			params: params{doc: "swago:response NotFound ErrorBody"},
+			expected: expected{
+				diagnostics: []string{"warning: annotation swago:response NotFound ErrorBody is ignored, its status code NotFound is unknown (route GET /users)"},
+			},
+		},
+		{
+			name:   "should report and ignore an unknown annotation",
+			params: params{doc: "swago:handler\nswago:tags users"},
+			expected: expected{
+				operation:   openapi2.Operation{Tags: []string{"users"}},
+				diagnostics: []string{"warning: annotation swago:handler is ignored, handler is not a swago annotation (route GET /users)"},
+			},
+		},
+		{
+			name:   "should add a parameter",
+			params: params{doc: "swago:param query page int required the page"},
+			expected: expected{
+				operation: openapi2.Operation{Parameters: openapi2.Parameters{
+					{In: "query", Name: "page", Type: "integer", Format: "int32", Required: true, Description: "the page"},
+				}},
+			},
+		},
+		{
+			name:   "should report and ignore a body parameter whose type is not found",
+			params: params{doc: "swago:param body user handler.Missing"},
+			expected: expected{
+				diagnostics: []string{"warning: annotation swago:param body user handler.Missing is ignored, its type is not found (route GET /users)"},
+			},
+		},
+		{
+			name:   "should report and ignore a parameter without a type",
+			params: params{doc: "swago:param query page"},
+			expected: expected{
+				diagnostics: []string{"warning: annotation swago:param query page is ignored, it needs the in, the name and the type of the parameter (route GET /users)"},
+			},
+		},
+		{
+			name:   "should report and ignore a parameter with an invalid in",
+			params: params{doc: "swago:param qurey page int"},
+			expected: expected{
+				diagnostics: []string{"warning: annotation swago:param qurey page int is ignored, in qurey is not one of query, header, path, formData, body (route GET /users)"},
+			},
+		},
+		{
+			name:   "should replace the security",
+			params: params{doc: "swago:security jwt users:read | apiKey"},
+			expected: expected{
+				operation: openapi2.Operation{Security: &openapi2.SecurityRequirements{
+					{"jwt": {"users:read"}},
+					{"apiKey": {}},
+				}},
+			},
+		},
+		{
+			name:   "should remove the security",
+			params: params{doc: "swago:security none"},
+			expected: expected{
+				operation: openapi2.Operation{Security: &openapi2.SecurityRequirements{}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &SwaggerGenerator{
+				Pkgs:        []*pkg.Pkg{{Name: "handler"}},
+				Diagnostics: &diagnostic.List{},
+			}
+			annotations, _ := pkg.ParseAnnotations(test.params.doc)
+			r := &pkg.Route{
+				Path:       "/users",
+				HTTPMethod: "GET",
+				Handler:    &pkg.Function{Name: "ListUsers", Annotations: annotations},
+			}
+			swaggerDoc := &openapi2.Swagger{Definitions: make(map[string]*openapi3.SchemaRef)}
+			operation := &openapi2.Operation{}
+			err := s.applyAnnotations(r, operation, projectCriterias, swaggerDoc)
+			assert.NoError(t, err)
+			deprecated := s.Extensions.IsDeprecated(operation)
+			assert.Equal(t, test.expected.deprecated, deprecated)
+			assert.Equal(t, test.expected.operation, *operation)
+			definitions := make([]string, 0)
+			for name := range swaggerDoc.Definitions {
+				definitions = append(definitions, name)
+			}
+			if test.expected.definitions == nil {
+				test.expected.definitions = []string{}
+			}
+			assert.Equal(t, test.expected.definitions, definitions)
+			messages := make([]string, 0)
+			for _, d := range s.Diagnostics.Diagnostics() {
+				messages = append(messages, d.String())
+			}
+			if test.expected.diagnostics == nil {
+				test.expected.diagnostics = []string{}
+			}
+			assert.Equal(t, test.expected.diagnostics, messages)
+		})
+	}
+}
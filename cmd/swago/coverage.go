@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago"
+)
+
+// runCoverage reports the routes that are not fully documented and the handlers that are not
+// attached to any route, it fails when the percentage documented is below a minimum
+func runCoverage(args []string) int {
+	var dir, conf, format string
+	var min float64
+	var verbose bool
+	fs := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	fs.StringVar(&dir, "dir", "./", "Project's directory")
+	fs.StringVar(&conf, "conf", "./swago.yaml", "Swago's config file")
+	fs.StringVar(&format, "format", "text", "Format of the report, text or json")
+	fs.Float64Var(&min, "min", 0, "Minimum percentage of documented routes")
+	fs.BoolVar(&verbose, "v", false, "Print the analysis progress")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if format != "text" && format != "json" {
+		return fail(exitUsage, "unknown format %s, use text or json", format)
+	}
+	if err := absPaths(&dir, &conf); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	logger := newLogger(verbose)
+	projectCriteria, err := readCriteria(conf, logger)
+	if err != nil {
+		return fail(exitConfig, "%v", err)
+	}
+	sg, err := swago.NewSwaggerGenerator(dir, dir, projectCriteria.VendorFolders, logger)
+	if err != nil {
+		return fail(exitAnalysis, "error creating a swagger generator: %v", err)
+	}
+	swaggerDoc := openapi2.Swagger{}
+	err = sg.GenerateSwaggerDoc(projectCriteria, &swaggerDoc)
+	if err != nil {
+		return fail(exitAnalysis, "error generating swagger doc: %v", err)
+	}
+	coverage := sg.Coverage(&swaggerDoc)
+	if format == "json" {
+		err = writeCoverageJSON(os.Stdout, coverage, dir)
+	} else {
+		err = writeCoverageText(os.Stdout, coverage, dir)
+	}
+	if err != nil {
+		return fail(exitOutput, "error writing the coverage report: %v", err)
+	}
+	if coverage.Percentage < min {
+		return fail(exitCoverage, "coverage %.1f%% is below the minimum %.1f%%", coverage.Percentage, min)
+	}
+	return exitOK
+}
+
+func writeCoverageText(w io.Writer, coverage swago.Coverage, dir string) error {
+	fmt.Fprintf(w, "coverage: %.1f%% (%d of %d routes fully documented, %d unattached handlers)\n", coverage.Percentage, coverage.DocumentedRoutes, coverage.Routes, len(coverage.UnattachedHandlers))
+	if len(coverage.PartialRoutes) > 0 {
+		fmt.Fprintln(w, "\npartially documented routes:")
+		for _, r := range coverage.PartialRoutes {
+			fmt.Fprintf(w, "  %s: %s %s: %s\n", relativePosition(dir, r.Position.Filename, r.Position.Line, r.Position.Column), r.Route, orNoModel(r.Handler), strings.Join(r.Missing, ", "))
+		}
+	}
+	if len(coverage.UnattachedHandlers) > 0 {
+		fmt.Fprintln(w, "\nhandlers not attached to any route:")
+		for _, h := range coverage.UnattachedHandlers {
+			fmt.Fprintf(w, "  %s: %s\n", relativePosition(dir, h.Position.Filename, h.Position.Line, h.Position.Column), h.Handler)
+		}
+	}
+	return nil
+}
+
+type coverageLocation struct {
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+func writeCoverageJSON(w io.Writer, coverage swago.Coverage, dir string) error {
+	type routeJSON struct {
+		swago.RouteCoverage
+		coverageLocation
+	}
+	type handlerJSON struct {
+		swago.HandlerCoverage
+		coverageLocation
+	}
+	report := struct {
+		Percentage         float64       `json:"percentage"`
+		Routes             int           `json:"routes"`
+		DocumentedRoutes   int           `json:"documentedRoutes"`
+		PartialRoutes      []routeJSON   `json:"partialRoutes"`
+		UnattachedHandlers []handlerJSON `json:"unattachedHandlers"`
+	}{
+		Percentage:         coverage.Percentage,
+		Routes:             coverage.Routes,
+		DocumentedRoutes:   coverage.DocumentedRoutes,
+		PartialRoutes:      make([]routeJSON, 0, len(coverage.PartialRoutes)),
+		UnattachedHandlers: make([]handlerJSON, 0, len(coverage.UnattachedHandlers)),
+	}
+	for _, r := range coverage.PartialRoutes {
+		report.PartialRoutes = append(report.PartialRoutes, routeJSON{r, location(dir, r.Position.Filename, r.Position.Line)})
+	}
+	for _, h := range coverage.UnattachedHandlers {
+		report.UnattachedHandlers = append(report.UnattachedHandlers, handlerJSON{h, location(dir, h.Position.Filename, h.Position.Line)})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func location(dir, filename string, line int) coverageLocation {
+	return coverageLocation{File: relativeFile(dir, filename), Line: line}
+}
+
+// relativePosition formats a position as file:line:col with the file relative to the project's directory
+func relativePosition(dir, filename string, line, column int) string {
+	return fmt.Sprintf("%s:%d:%d", relativeFile(dir, filename), line, column)
+}
+
+func relativeFile(dir, filename string) string {
+	if rel, err := filepath.Rel(dir, filename); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return filename
+}
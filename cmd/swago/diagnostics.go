@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/javiercbk/swago/diagnostic"
+)
+
+const (
+	textDiagnostics = "text"
+	jsonDiagnostics = "json"
+)
+
+// printDiagnostics prints the diagnostics with the file names relative to the project's directory
+func printDiagnostics(w io.Writer, format, dir string, diagnostics *diagnostic.List) error {
+	sorted := diagnostic.Relative(diagnostics.Diagnostics(), dir)
+	switch format {
+	case jsonDiagnostics:
+		return diagnostic.WriteJSON(w, sorted)
+	case textDiagnostics:
+		return diagnostic.WriteText(w, sorted)
+	}
+	return fmt.Errorf("unknown diagnostics format %s, use %s or %s", format, textDiagnostics, jsonDiagnostics)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago"
+)
+
+// runExplain prints every step of the evaluation of the criteria for the routes that match
+// a route as in "POST /auth", a route without a method matches every method
+func runExplain(args []string) int {
+	var dir, conf, route string
+	var verbose bool
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.StringVar(&dir, "dir", "./", "Project's directory")
+	fs.StringVar(&conf, "conf", "./swago.yaml", "Swago's config file")
+	fs.StringVar(&route, "route", "", `Route to explain as in "POST /auth"`)
+	fs.BoolVar(&verbose, "v", false, "Print the analysis progress")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if len(route) == 0 {
+		route = strings.Join(fs.Args(), " ")
+	}
+	if len(route) == 0 {
+		return fail(exitUsage, `a route is required as in swago explain -route "POST /auth"`)
+	}
+	if err := absPaths(&dir, &conf); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	logger := newLogger(verbose)
+	projectCriteria, err := readCriteria(conf, logger)
+	if err != nil {
+		return fail(exitConfig, "%v", err)
+	}
+	sg, err := swago.NewSwaggerGenerator(dir, dir, projectCriteria.VendorFolders, logger)
+	if err != nil {
+		return fail(exitAnalysis, "error creating a swagger generator: %v", err)
+	}
+	general := make([]swago.TraceStep, 0)
+	endpoints := make([]string, 0)
+	steps := make(map[string][]swago.TraceStep)
+	sg.Tracer = func(step swago.TraceStep) {
+		if len(step.Route) == 0 {
+			general = append(general, step)
+			return
+		}
+		if _, ok := steps[step.Route]; !ok {
+			endpoints = append(endpoints, step.Route)
+		}
+		steps[step.Route] = append(steps[step.Route], step)
+	}
+	// the whole document is generated so the problems found while documenting the
+	// models of a route are explained too
+	err = sg.GenerateSwaggerDoc(projectCriteria, &openapi2.Swagger{})
+	for _, step := range general {
+		printStep(os.Stdout, dir, step)
+	}
+	explained := 0
+	for _, endpoint := range endpoints {
+		method, path := splitEndpoint(endpoint)
+		if !matchesRoute(route, method, path) {
+			continue
+		}
+		explained++
+		fmt.Printf("\n%s\n", endpoint)
+		for _, step := range steps[endpoint] {
+			printStep(os.Stdout, dir, step)
+		}
+	}
+	if err != nil {
+		return fail(exitAnalysis, "error documenting the routes: %v", err)
+	}
+	if explained == 0 {
+		found := make([]string, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			found = append(found, "  "+endpoint)
+		}
+		return fail(exitAnalysis, "no route matches %s, the routes found are:\n%s", route, strings.Join(found, "\n"))
+	}
+	return exitOK
+}
+
+// matchesRoute returns true when a route as in "POST /auth" or "/auth" is the method and path
+func matchesRoute(route, method, path string) bool {
+	parts := strings.Fields(route)
+	switch len(parts) {
+	case 1:
+		return parts[0] == path
+	case 2:
+		return strings.EqualFold(parts[0], method) && parts[1] == path
+	}
+	return false
+}
+
+// splitEndpoint returns the method and the path of an endpoint as in POST /auth
+func splitEndpoint(endpoint string) (string, string) {
+	parts := strings.SplitN(endpoint, " ", 2)
+	if len(parts) < 2 {
+		return "", endpoint
+	}
+	return parts[0], parts[1]
+}
+
+// printStep prints a trace step with its position relative to the project's directory
+func printStep(w io.Writer, dir string, step swago.TraceStep) {
+	if !step.Position.IsValid() {
+		fmt.Fprintf(w, "  %s\n", step.Message)
+		return
+	}
+	fmt.Fprintf(w, "  %s: %s\n", relativePosition(dir, step.Position.Filename, step.Position.Line, step.Position.Column), step.Message)
+}
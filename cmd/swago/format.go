@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/encoding/html"
+	"github.com/javiercbk/swago/encoding/markdown"
+	"github.com/javiercbk/swago/encoding/postman"
+	"github.com/javiercbk/swago/encoding/swagger"
+	"github.com/javiercbk/swago/encoding/typescript"
+)
+
+const (
+	yamlFormat     = "yaml"
+	jsonFormat     = "json"
+	markdownFormat = "markdown"
+	htmlFormat     = "html"
+	postmanFormat  = "postman"
+	tsFormat       = "typescript"
+)
+
+// marshalers write a swagger document in a format
+var marshalers = map[string]func(openapi2.Swagger, swagger.Extensions, io.Writer) error{
+	yamlFormat:     swagger.MarshalYAMLWithExtensions,
+	jsonFormat:     swagger.MarshalJSONWithExtensions,
+	markdownFormat: markdown.Marshal,
+	htmlFormat:     html.Marshal,
+	postmanFormat:  postman.Marshal,
+	tsFormat:       typescript.Marshal,
+}
+
+func checkFormat(format string) error {
+	if _, ok := marshalers[format]; !ok {
+		return fmt.Errorf("unknown format %s, use %s, %s, %s, %s, %s or %s", format, yamlFormat, jsonFormat, markdownFormat, htmlFormat, postmanFormat, tsFormat)
+	}
+	return nil
+}
+
+func marshal(format string, doc openapi2.Swagger, extensions swagger.Extensions, w io.Writer) error {
+	return marshalers[format](doc, extensions, w)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-git/go-git/v5"
+	"github.com/javiercbk/swago"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"gopkg.in/yaml.v2"
+)
+
+type generateFlags struct {
+	dir            string
+	conf           string
+	outfile        string
+	format         string
+	omitDeprecated bool
+	trace          bool
+	strict         bool
+	diagnostics    string
+	watch          bool
+	debounce       time.Duration
+}
+
+func (g *generateFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.outfile, "outfile", "./swagger.yaml", "Swagger's file output")
+	fs.StringVar(&g.format, "format", yamlFormat, "Format of the output, yaml, json, markdown, html, postman or typescript")
+	g.registerAnalysis(fs)
+}
+
+// registerAnalysis registers the flags of the analysis, every flag but the outfile
+func (g *generateFlags) registerAnalysis(fs *flag.FlagSet) {
+	fs.StringVar(&g.dir, "dir", "./", "Project's directory")
+	fs.StringVar(&g.conf, "conf", "./swago.yaml", "Swago's config file")
+	fs.BoolVar(&g.omitDeprecated, "omit-deprecated", false, "Omit the deprecated operations")
+	fs.BoolVar(&g.trace, "trace", false, "Print every step of the evaluation of the criteria to stderr")
+	fs.BoolVar(&g.strict, "strict", false, "Fail when a warning or an error is found")
+	fs.StringVar(&g.diagnostics, "diagnostics", textDiagnostics, "Format of the warnings and errors printed to stderr, text or json")
+}
+
+func (g *generateFlags) registerWatch(fs *flag.FlagSet) {
+	fs.BoolVar(&g.watch, "watch", false, "Regenerate the swagger file when a go file or the config file changes")
+	fs.DurationVar(&g.debounce, "debounce", 300*time.Millisecond, "Time without changes to wait before regenerating in watch mode")
+}
+
+func runGenerate(args []string) int {
+	g := generateFlags{}
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	g.register(fs)
+	g.registerWatch(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if err := checkFormat(g.format); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	if err := absPaths(&g.dir, &g.conf, &g.outfile); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	if g.watch {
+		return g.watchProject(newLogger(false))
+	}
+	logger := newLogger(true)
+	buf := &bytes.Buffer{}
+	if code := g.generate(logger, buf, ""); code != exitOK {
+		return code
+	}
+	err := ioutil.WriteFile(g.outfile, buf.Bytes(), 0666)
+	if err != nil {
+		return fail(exitOutput, "error writing swagger yaml to path %s: %v", g.outfile, err)
+	}
+	return exitOK
+}
+
+// runCheck generates the swagger file in memory and compares it with the existing one, when
+// the version is taken from git the version of the existing file is kept
+func runCheck(args []string) int {
+	g := generateFlags{}
+	var verbose bool
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	g.register(fs)
+	fs.BoolVar(&verbose, "v", false, "Print the analysis progress")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if err := checkFormat(g.format); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	if err := absPaths(&g.dir, &g.conf, &g.outfile); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	existing, err := ioutil.ReadFile(g.outfile)
+	if err != nil {
+		return fail(exitOutput, "error reading swagger file %s: %v", g.outfile, err)
+	}
+	existingDoc := struct {
+		Info struct {
+			Version string `yaml:"version"`
+		} `yaml:"info"`
+	}{}
+	// the version is only read back from the spec formats, json being yaml too
+	if g.format == yamlFormat || g.format == jsonFormat {
+		if err := yaml.Unmarshal(existing, &existingDoc); err != nil {
+			return fail(exitOutput, "error parsing swagger file %s: %v", g.outfile, err)
+		}
+	}
+	buf := &bytes.Buffer{}
+	if code := g.generate(newLogger(verbose), buf, existingDoc.Info.Version); code != exitOK {
+		return code
+	}
+	if !bytes.Equal(existing, buf.Bytes()) {
+		return fail(exitOutdated, "%s is not up to date, run swago generate", g.outfile)
+	}
+	fmt.Printf("%s is up to date\n", g.outfile)
+	return exitOK
+}
+
+// generate writes the swagger yaml of a project, fallbackVersion replaces the git version
+func (g generateFlags) generate(logger *log.Logger, w io.Writer, fallbackVersion string) int {
+	projectCriteria, err := readCriteria(g.conf, logger)
+	if err != nil {
+		return fail(exitConfig, "%v", err)
+	}
+	version, err := g.version(projectCriteria, fallbackVersion)
+	if err != nil {
+		return fail(exitAnalysis, "%v", err)
+	}
+	sg, err := g.newGenerator(projectCriteria, logger)
+	if err != nil {
+		return fail(exitAnalysis, "error creating a swagger generator: %v", err)
+	}
+	code := g.write(sg, projectCriteria, version, w)
+	if err := printDiagnostics(os.Stderr, g.diagnostics, g.dir, sg.Diagnostics); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	if code != exitOK {
+		return code
+	}
+	if g.strict && sg.Diagnostics.Len() > 0 {
+		return fail(exitAnalysis, "%d errors and %d warnings found in strict mode", sg.Diagnostics.Count(diagnostic.Error), sg.Diagnostics.Count(diagnostic.Warning))
+	}
+	return exitOK
+}
+
+// version returns the version of the criteria, fallbackVersion or the hash of the last commit
+func (g generateFlags) version(projectCriteria criteria.Criteria, fallbackVersion string) (string, error) {
+	if len(projectCriteria.Info.Version) > 0 {
+		return projectCriteria.Info.Version, nil
+	}
+	if len(fallbackVersion) > 0 {
+		return fallbackVersion, nil
+	}
+	return gitVersion(g.dir)
+}
+
+func (g generateFlags) newGenerator(projectCriteria criteria.Criteria, logger *log.Logger) (*swago.SwaggerGenerator, error) {
+	sg, err := swago.NewSwaggerGenerator(g.dir, g.dir, projectCriteria.VendorFolders, logger)
+	if err != nil {
+		return sg, err
+	}
+	if g.trace {
+		sg.Tracer = func(step swago.TraceStep) {
+			printStep(os.Stderr, g.dir, step)
+		}
+	}
+	return sg, nil
+}
+
+// document generates the swagger document with a generator that analyzed the project
+func (g generateFlags) document(sg *swago.SwaggerGenerator, projectCriteria criteria.Criteria, version string) (openapi2.Swagger, int) {
+	swaggerDoc := openapi2.Swagger{
+		Info: openapi3.Info{
+			Title:       projectCriteria.Info.Title,
+			Description: projectCriteria.Info.Description,
+			Version:     version,
+		},
+	}
+	err := sg.GenerateSwaggerDoc(projectCriteria, &swaggerDoc)
+	if err != nil {
+		return swaggerDoc, fail(exitAnalysis, "error generating swagger doc: %v", err)
+	}
+	if g.omitDeprecated {
+		sg.Extensions.RemoveDeprecated(&swaggerDoc)
+	}
+	return swaggerDoc, exitOK
+}
+
+// write writes the swagger yaml with a generator that analyzed the project
+func (g generateFlags) write(sg *swago.SwaggerGenerator, projectCriteria criteria.Criteria, version string, w io.Writer) int {
+	swaggerDoc, code := g.document(sg, projectCriteria, version)
+	if code != exitOK {
+		return code
+	}
+	err := marshal(g.format, swaggerDoc, sg.Extensions, w)
+	if err != nil {
+		return fail(exitOutput, "error marshaling swagger %s: %v", g.format, err)
+	}
+	return exitOK
+}
+
+// gitVersion returns the hash of the last commit of the project
+func gitVersion(projectPath string) (string, error) {
+	r, err := git.PlainOpen(projectPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading git repository at '%s': %w", projectPath, err)
+	}
+	cIter, err := r.Log(&git.LogOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error reading commit logs from repository at '%s': %w", projectPath, err)
+	}
+	defer cIter.Close()
+	commit, err := cIter.Next()
+	if err != nil {
+		return "", fmt.Errorf("error reading commit logs from repository at '%s': %w", projectPath, err)
+	}
+	return commit.Hash.String(), nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/javiercbk/swago/scaffold"
+	v2pkg "github.com/javiercbk/swago/v2/pkg"
+)
+
+func runInit(args []string) int {
+	var dir, out string
+	var force bool
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.StringVar(&dir, "dir", "./", "Project's directory")
+	fs.StringVar(&out, "out", "./swago.yaml", "Swago's config file to write")
+	fs.BoolVar(&force, "force", false, "Overwrite an existing config file")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if err := absPaths(&dir, &out); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	if _, err := os.Stat(out); err == nil && !force {
+		return fail(exitOutput, "%s already exists, use -force to overwrite it", out)
+	}
+	mod, err := v2pkg.ReadMod(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return fail(exitAnalysis, "error reading go.mod of %s: %v", dir, err)
+	}
+	framework := scaffold.DetectFramework(mod)
+	usage, err := scaffold.Scan(dir, framework)
+	if err != nil {
+		return fail(exitAnalysis, "error scanning %s: %v", dir, err)
+	}
+	buf := &bytes.Buffer{}
+	if err := scaffold.WriteCriteria(buf, usage, path.Base(mod.Name)); err != nil {
+		return fail(exitOutput, "error writing config: %v", err)
+	}
+	if err := ioutil.WriteFile(out, buf.Bytes(), 0644); err != nil {
+		return fail(exitOutput, "error writing config to path %s: %v", out, err)
+	}
+	fmt.Printf("wrote %s for %s\n", out, framework)
+	return exitOK
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/javiercbk/swago"
+	"github.com/javiercbk/swago/pkg"
+)
+
+const noModel = "-"
+
+func runRoutes(args []string) int {
+	var dir, conf string
+	var verbose bool
+	fs := flag.NewFlagSet("routes", flag.ContinueOnError)
+	fs.StringVar(&dir, "dir", "./", "Project's directory")
+	fs.StringVar(&conf, "conf", "./swago.yaml", "Swago's config file")
+	fs.BoolVar(&verbose, "v", false, "Print the analysis progress")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if err := absPaths(&dir, &conf); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	logger := newLogger(verbose)
+	projectCriteria, err := readCriteria(conf, logger)
+	if err != nil {
+		return fail(exitConfig, "%v", err)
+	}
+	sg, err := swago.NewSwaggerGenerator(dir, dir, projectCriteria.VendorFolders, logger)
+	if err != nil {
+		return fail(exitAnalysis, "error creating a swagger generator: %v", err)
+	}
+	routes, err := sg.FindRoutes(projectCriteria)
+	if err != nil {
+		return fail(exitAnalysis, "error finding routes: %v", err)
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].HTTPMethod < routes[j].HTTPMethod
+		}
+		return routes[i].Path < routes[j].Path
+	})
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tHANDLER\tREQUEST\tRESPONSES")
+	for _, r := range routes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.HTTPMethod, r.Path, orNoModel(r.HandlerType), modelName(r.RequestModel), responsesSummary(r.ServiceResponses))
+	}
+	if err := tw.Flush(); err != nil {
+		return fail(exitOutput, "error writing routes: %v", err)
+	}
+	if err := printDiagnostics(os.Stderr, textDiagnostics, dir, sg.Diagnostics); err != nil {
+		return fail(exitOutput, "error writing diagnostics: %v", err)
+	}
+	return exitOK
+}
+
+// modelName returns the type of a model, inline for anonymous models
+func modelName(model pkg.Struct) string {
+	if name := model.QualifiedName(); len(name) > 0 {
+		return name
+	}
+	if len(model.Fields) > 0 {
+		return "inline"
+	}
+	return noModel
+}
+
+// responsesSummary returns the status code and model of every response as in 200:pkg.User
+func responsesSummary(responses []pkg.ServiceResponse) string {
+	if len(responses) == 0 {
+		return noModel
+	}
+	summary := make([]string, 0, len(responses))
+	for _, sr := range responses {
+		code := sr.Code
+		if statusCode := swago.StatusCode(sr.Code); statusCode > 0 {
+			code = fmt.Sprintf("%d", statusCode)
+		}
+		summary = append(summary, code+":"+modelName(sr.Model))
+	}
+	return strings.Join(summary, " ")
+}
+
+func orNoModel(s string) string {
+	if len(s) == 0 {
+		return noModel
+	}
+	return s
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/encoding/swagger"
+)
+
+// runServe generates the swagger file of a project and serves it with a documentation ui, in
+// watch mode the documentation is regenerated and the pages are reloaded after every change
+func runServe(args []string) int {
+	g := generateFlags{}
+	var addr, ui, uiDir string
+	var verbose bool
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	g.registerAnalysis(fs)
+	g.registerWatch(fs)
+	fs.StringVar(&addr, "addr", "localhost:8080", "Address to serve the documentation on")
+	fs.StringVar(&ui, "ui", swaggerUI, "Documentation ui, swagger, redoc or builtin")
+	fs.StringVar(&uiDir, "ui-dir", "", "Directory with a swagger-ui-dist or redoc bundle served instead of the one built into swago")
+	fs.BoolVar(&verbose, "v", false, "Print the analysis progress")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	bundle, err := uiBundle(ui, uiDir)
+	if err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	if err := absPaths(&g.dir, &g.conf); err != nil {
+		return fail(exitUsage, "%v", err)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fail(exitUsage, "error listening on %s: %v", addr, err)
+	}
+	url := "http://" + listener.Addr().String()
+	g.format = yamlFormat
+	logger := newLogger(verbose)
+	w, code := g.newWatcher(logger)
+	if code != exitOK {
+		return code
+	}
+	w.location = url + "/swagger.yaml"
+	ds := newDocServer(ui, bundle)
+	w.publish = ds.publish
+	w.regenerate()
+	if w.output == nil && !g.watch {
+		return exitAnalysis
+	}
+	fmt.Printf("serving the documentation on %s\n", url)
+	if g.watch {
+		go w.watch(logger)
+	}
+	err = http.Serve(listener, ds)
+	return fail(exitOutput, "error serving the documentation: %v", err)
+}
+
+// docServer serves the last swagger file published and notifies the pages when it changes
+type docServer struct {
+	ui       string
+	files    http.Handler
+	mu       sync.RWMutex
+	specYAML []byte
+	specJSON []byte
+	// page is the builtin page rendered from the last swagger document published
+	page    []byte
+	clients map[chan struct{}]bool
+}
+
+func newDocServer(ui string, bundle fs.FS) *docServer {
+	ds := &docServer{
+		ui:      ui,
+		clients: make(map[chan struct{}]bool),
+	}
+	if bundle != nil {
+		ds.files = http.StripPrefix("/ui/", http.FileServer(http.FS(bundle)))
+	}
+	return ds
+}
+
+// publish replaces the swagger file served and notifies every page
+func (ds *docServer) publish(specYAML []byte, doc openapi2.Swagger, extensions swagger.Extensions) {
+	buf := &bytes.Buffer{}
+	if err := swagger.YAMLToJSON(specYAML, buf); err != nil {
+		fail(exitOutput, "error converting swagger yaml to json: %v", err)
+		return
+	}
+	var page []byte
+	if ds.ui == builtinUI {
+		var err error
+		page, err = renderBuiltinPage(doc, extensions)
+		if err != nil {
+			fail(exitOutput, "error rendering the documentation page: %v", err)
+			return
+		}
+	}
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.specYAML = specYAML
+	ds.specJSON = buf.Bytes()
+	ds.page = page
+	for c := range ds.clients {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (ds *docServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/":
+		ds.servePage(w)
+	case r.URL.Path == "/swagger.yaml":
+		ds.serveSpec(w, "application/x-yaml", func() []byte { return ds.specYAML })
+	case r.URL.Path == "/swagger.json":
+		ds.serveSpec(w, "application/json", func() []byte { return ds.specJSON })
+	case r.URL.Path == "/events":
+		ds.serveEvents(w, r)
+	case ds.files != nil && strings.HasPrefix(r.URL.Path, "/ui/"):
+		ds.files.ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (ds *docServer) servePage(w http.ResponseWriter) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(uiPage(ds.ui, ds.page))
+}
+
+func (ds *docServer) serveSpec(w http.ResponseWriter, contentType string, spec func() []byte) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(spec())
+}
+
+// serveEvents streams an event to a page every time the swagger file is published
+func (ds *docServer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	c := make(chan struct{}, 1)
+	ds.mu.Lock()
+	ds.clients[c] = true
+	ds.mu.Unlock()
+	defer func() {
+		ds.mu.Lock()
+		delete(ds.clients, c)
+		ds.mu.Unlock()
+	}()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+	for {
+		select {
+		case <-c:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -1,103 +1,138 @@
 package main
 
 import (
-	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/javiercbk/swago/criteria"
+)
 
-	"github.com/getkin/kin-openapi/openapi2"
-	"github.com/getkin/kin-openapi/openapi3"
-	"github.com/go-git/go-git/v5"
-	"github.com/javiercbk/swago"
-	"github.com/javiercbk/swago/encoding/swagger"
+const (
+	exitOK = iota
+	// exitUsage is returned when the arguments are invalid
+	exitUsage
+	// exitConfig is returned when the criteria file can not be read or has problems
+	exitConfig
+	// exitAnalysis is returned when the project can not be analyzed
+	exitAnalysis
+	// exitOutput is returned when the output can not be written
+	exitOutput
+	// exitOutdated is returned by check when the swagger file is not up to date
+	exitOutdated
+	// exitCoverage is returned by coverage when the percentage documented is below the minimum
+	exitCoverage
 )
 
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string) int
+}
+
+var commands = []command{
+	{name: "generate", description: "generate the swagger file of a project", run: runGenerate},
+	{name: "check", description: "fail if the swagger file is not up to date", run: runCheck},
+	{name: "serve", description: "serve the documentation of a project with a local ui", run: runServe},
+	{name: "routes", description: "print the routes, handlers and models of a project", run: runRoutes},
+	{name: "coverage", description: "report the routes and handlers that are not fully documented", run: runCoverage},
+	{name: "explain", description: "trace why a route or its models were or weren't documented", run: runExplain},
+	{name: "init", description: "write a starter swago.yaml for the project's web framework", run: runInit},
+	{name: "validate-config", description: "parse and lint a swago.yaml", run: runValidateConfig},
+	{name: "version", description: "print swago's version", run: runVersion},
+}
+
 func main() {
-	var dir, file, outfile string
-	log := &log.Logger{}
-	log.SetOutput(os.Stdout)
-	flag.StringVar(&dir, "dir", "./", "Project's directory")
-	flag.StringVar(&file, "conf", "./swago.yaml", "Swago's config file")
-	flag.StringVar(&outfile, "outfile", "./swagger.yaml", "Swagger's file output")
-	flag.Parse()
-	projectPath, err := filepath.Abs(dir)
-	if err != nil {
-		log.Printf("error getting absolute path of %s: %v", dir, err)
-		os.Exit(1)
-	}
-	filePath, err := filepath.Abs(file)
-	if err != nil {
-		log.Printf("error getting absolute path of %s: %v", file, err)
-		os.Exit(1)
+	os.Exit(run(os.Args[1:]))
+}
+
+// run dispatches a subcommand, flags without a subcommand run generate as older versions did
+func run(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+			usage(os.Stdout)
+			return exitOK
+		}
+		return runGenerate(args)
 	}
-	outfilePath, err := filepath.Abs(outfile)
-	if err != nil {
-		log.Printf("error getting absolute path of %s: %v", outfile, err)
-		os.Exit(1)
+	if args[0] == "help" {
+		usage(os.Stdout)
+		return exitOK
 	}
-	swagoFile, err := os.OpenFile(filePath, os.O_RDONLY, 0644)
-	if err != nil {
-		log.Printf("error reading project criteria from file %s: %v", filePath, err)
-		os.Exit(1)
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
 	}
-	projectCriteria := criteria.Criteria{}
-	criteriaDecorer := criteria.NewCriteriaDecoder(log)
-	err = criteriaDecorer.ParseCriteriaFromYAML(swagoFile, &projectCriteria)
-	if err != nil {
-		log.Printf("error parsing project criteria from file %s: %v", filePath, err)
-		os.Exit(1)
+	fmt.Fprintf(os.Stderr, "unknown command %s\n", args[0])
+	usage(os.Stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: swago <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-16s %s\n", c.name, c.description)
 	}
-	defer swagoFile.Close()
-	swaggerDoc := openapi2.Swagger{
-		Info: openapi3.Info{
-			Title:       projectCriteria.Info.Title,
-			Description: projectCriteria.Info.Description,
-		},
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run swago <command> -h to list the flags of a command")
+}
+
+func runVersion(args []string) int {
+	fmt.Println(version)
+	return exitOK
+}
+
+// newLogger returns a logger that prints the analysis progress to stdout when verbose
+func newLogger(verbose bool) *log.Logger {
+	logger := &log.Logger{}
+	if verbose {
+		logger.SetOutput(os.Stdout)
+	} else {
+		logger.SetOutput(ioutil.Discard)
 	}
-	if len(projectCriteria.Info.Version) == 0 {
-		r, err := git.PlainOpen(projectPath)
-		if err != nil {
-			log.Printf("error reading git repository at '%s': %v", projectPath, err)
-			os.Exit(1)
-		}
-		cIter, err := r.Log(&git.LogOptions{})
-		if err != nil {
-			log.Printf("error reading commit logs from repository at '%s': %v", projectPath, err)
-			os.Exit(1)
-		}
-		defer cIter.Close()
-		commit, err := cIter.Next()
+	return logger
+}
+
+// fail prints an error to stderr and returns its exit code
+func fail(code int, format string, args ...interface{}) int {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	return code
+}
+
+// absPaths makes every path absolute
+func absPaths(paths ...*string) error {
+	for _, p := range paths {
+		abs, err := filepath.Abs(*p)
 		if err != nil {
-			log.Printf("error reading commit logs from repository at '%s': %v", projectPath, err)
-			os.Exit(1)
+			return fmt.Errorf("error getting absolute path of %s: %w", *p, err)
 		}
-		swaggerDoc.Info.Version = commit.Hash.String()
-	} else {
-		swaggerDoc.Info.Version = projectCriteria.Info.Version
-	}
-	sg, err := swago.NewSwaggerGenerator(projectPath, projectPath, projectCriteria.VendorFolders, log)
-	if err != nil {
-		log.Printf("error creating a swagger generator: %v", err)
-		os.Exit(1)
-	}
-	err = sg.GenerateSwaggerDoc(projectCriteria, &swaggerDoc)
-	if err != nil {
-		log.Printf("error generating swagger doc: %v", err)
-		os.Exit(1)
+		*p = abs
 	}
-	outFile, err := os.OpenFile(outfilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	return nil
+}
+
+// readCriteria parses a criteria file
+func readCriteria(path string, logger *log.Logger) (criteria.Criteria, error) {
+	projectCriteria := criteria.Criteria{}
+	swagoFile, err := os.Open(path)
 	if err != nil {
-		log.Printf("error opening outfile at path %s: %v", outfilePath, err)
-		os.Exit(1)
+		return projectCriteria, fmt.Errorf("error reading project criteria from file %s: %w", path, err)
 	}
-	defer outFile.Close()
-	err = swagger.MarshalYAML(swaggerDoc, outFile)
+	defer swagoFile.Close()
+	criteriaDecoder := criteria.NewCriteriaDecoder(logger)
+	err = criteriaDecoder.ParseCriteriaFromYAML(swagoFile, &projectCriteria)
 	if err != nil {
-		log.Printf("error marshaling swagger yaml to path %s: %v", outfilePath, err)
-		os.Exit(1)
+		return projectCriteria, fmt.Errorf("error parsing project criteria from file %s: %w", path, err)
 	}
-
+	return projectCriteria, nil
 }
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var runProject = map[string]string{
+	"go.mod": "module example.com/api\n",
+	"main.go": `package main
+
+import (
+	"example.com/api/handler"
+	"example.com/api/router"
+)
+
+func main() {
+	router.GET("/users", handler.ListUsers)
+	router.GET("/missing", handler.Missing)
+}
+`,
+	"router/router.go": `package router
+
+import "net/http"
+
+func GET(path string, handler http.HandlerFunc) {}
+`,
+	"handler/handler.go": `package handler
+
+import "net/http"
+
+func ListUsers(w http.ResponseWriter, r *http.Request) {}
+
+func Unused(w http.ResponseWriter, r *http.Request) {}
+`,
+	"swago.yaml": `info:
+  title: api
+  version: 1.0.0
+routes:
+  - funcRoute:
+      pkg: router
+      funcName: GET
+      pathIndex: 0
+      handlerIndex: 1
+`,
+	"invalid.yaml": "routes: {\n",
+	"swagger.yaml": "swagger: \"2.0\"\n",
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, src := range runProject {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conf := filepath.Join(dir, "swago.yaml")
+	outfile := filepath.Join(dir, "out", "swagger.yaml")
+	type expected struct {
+		code int
+	}
+	tests := []struct {
+		name     string
+		params   []string
+		expected expected
+	}{
+		{
+			name:     "should print the version",
+			params:   []string{"version"},
+			expected: expected{code: exitOK},
+		},
+		{
+			name:     "should print the usage",
+			params:   []string{"help"},
+			expected: expected{code: exitOK},
+		},
+		{
+			name:     "should fail with an unknown command",
+			params:   []string{"publish"},
+			expected: expected{code: exitUsage},
+		},
+		{
+			name:     "should fail with an unknown flag",
+			params:   []string{"generate", "-unknown"},
+			expected: expected{code: exitUsage},
+		},
+		{
+			name:     "should fail with an unknown format",
+			params:   []string{"generate", "-dir", dir, "-conf", conf, "-format", "pdf"},
+			expected: expected{code: exitUsage},
+		},
+		{
+			name:     "should fail when the config file does not exist",
+			params:   []string{"validate-config", "-conf", filepath.Join(dir, "missing.yaml")},
+			expected: expected{code: exitConfig},
+		},
+		{
+			name:     "should fail when the config file is not valid",
+			params:   []string{"generate", "-dir", dir, "-conf", filepath.Join(dir, "invalid.yaml"), "-outfile", outfile},
+			expected: expected{code: exitConfig},
+		},
+		{
+			name:     "should fail in strict mode when a problem is found",
+			params:   []string{"generate", "-dir", dir, "-conf", conf, "-outfile", filepath.Join(dir, "strict.yaml"), "-strict"},
+			expected: expected{code: exitAnalysis},
+		},
+		{
+			name:     "should fail when the swagger file can not be written",
+			params:   []string{"generate", "-dir", dir, "-conf", conf, "-outfile", outfile},
+			expected: expected{code: exitOutput},
+		},
+		{
+			name:     "should generate the swagger file when the flags precede the command",
+			params:   []string{"-dir", dir, "-conf", conf, "-outfile", filepath.Join(dir, "generated.yaml")},
+			expected: expected{code: exitOK},
+		},
+		{
+			name:     "should fail when the swagger file is outdated",
+			params:   []string{"check", "-dir", dir, "-conf", conf, "-outfile", filepath.Join(dir, "swagger.yaml")},
+			expected: expected{code: exitOutdated},
+		},
+		{
+			name:     "should pass when the swagger file is up to date",
+			params:   []string{"check", "-dir", dir, "-conf", conf, "-outfile", filepath.Join(dir, "generated.yaml")},
+			expected: expected{code: exitOK},
+		},
+		{
+			name:     "should fail when the coverage is below the minimum",
+			params:   []string{"coverage", "-dir", dir, "-conf", conf, "-min", "100"},
+			expected: expected{code: exitCoverage},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.code, run(test.params))
+		})
+	}
+}
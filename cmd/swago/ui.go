@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/encoding/html"
+	"github.com/javiercbk/swago/encoding/swagger"
+)
+
+const (
+	builtinUI = "builtin"
+	swaggerUI = "swagger"
+	redocUI   = "redoc"
+)
+
+// uiFiles are the files of a bundle that the index page of an ui loads from the ui directory
+var uiFiles = map[string][]string{
+	swaggerUI: {"swagger-ui-bundle.js", "swagger-ui.css"},
+	redocUI:   {"redoc.standalone.js"},
+}
+
+// bundles are the swagger-ui-dist and redoc bundles pinned by ui/fetch.sh
+//
+//go:embed ui/swagger ui/redoc
+var bundles embed.FS
+
+// uiBundle returns the files of the bundle of an ui, the bundle embedded in swago unless
+// the ui directory is set, the builtin ui has no bundle. It returns an error when the ui
+// is unknown or the bundle of the ui directory is not complete
+func uiBundle(ui, uiDir string) (fs.FS, error) {
+	if ui == builtinUI {
+		return nil, nil
+	}
+	files, ok := uiFiles[ui]
+	if !ok {
+		return nil, fmt.Errorf("unknown ui %s, use %s, %s or %s", ui, swaggerUI, redocUI, builtinUI)
+	}
+	if len(uiDir) == 0 {
+		return fs.Sub(bundles, "ui/"+ui)
+	}
+	bundle := os.DirFS(uiDir)
+	for _, f := range files {
+		if _, err := fs.Stat(bundle, f); err != nil {
+			return nil, fmt.Errorf("%s not found in %s, the %s ui requires %v", f, uiDir, ui, files)
+		}
+	}
+	return bundle, nil
+}
+
+// uiPage returns the index page of an ui, the builtin page is rendered from every swagger
+// document published so it is empty for other uis
+func uiPage(ui string, builtinPage []byte) []byte {
+	switch ui {
+	case swaggerUI:
+		return []byte(swaggerUIPage)
+	case redocUI:
+		return []byte(redocPage)
+	}
+	if len(builtinPage) == 0 {
+		return []byte(waitingPage)
+	}
+	return builtinPage
+}
+
+// renderBuiltinPage renders a swagger document as the html format does and adds the live reload
+func renderBuiltinPage(doc openapi2.Swagger, extensions swagger.Extensions) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := html.Marshal(doc, extensions, buf); err != nil {
+		return nil, err
+	}
+	page := buf.String()
+	end := strings.LastIndex(page, "</body>")
+	if end == -1 {
+		return []byte(page + liveReload), nil
+	}
+	return []byte(page[:end] + liveReload + "\n" + page[end:]), nil
+}
+
+// liveReload reloads the page when the documentation is regenerated
+const liveReload = `<script>
+new EventSource("events").onmessage = function () { location.reload(); };
+</script>`
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>API documentation</title>
+<link rel="stylesheet" href="ui/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="ui/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({ url: "swagger.yaml", dom_id: "#swagger-ui", deepLinking: true });
+</script>
+` + liveReload + `
+</body>
+</html>
+`
+
+const redocPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>API documentation</title>
+</head>
+<body>
+<redoc spec-url="swagger.yaml"></redoc>
+<script src="ui/redoc.standalone.js"></script>
+` + liveReload + `
+</body>
+</html>
+`
+
+// waitingPage is the builtin page served until the documentation is generated
+const waitingPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>API documentation</title>
+</head>
+<body>
+<p>The documentation is not generated yet.</p>
+` + liveReload + `
+</body>
+</html>
+`
@@ -0,0 +1,26 @@
+#!/bin/sh
+# fetch.sh downloads the swagger-ui-dist and redoc bundles built into swago serve, run it as
+# sh fetch.sh [dir], the bundles are written to <dir>/swagger and <dir>/redoc, dir defaults to
+# the directory of the script so the bundles embedded in the binary are replaced
+set -e
+
+SWAGGER_UI_VERSION=5.17.14
+REDOC_VERSION=2.0.0-rc.59
+
+dir=${1:-$(dirname "$0")}
+mkdir -p "$dir"
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+fetch() {
+	mkdir -p "$tmp/$1"
+	curl -sSfL "https://registry.npmjs.org/$1/-/$1-$2.tgz" | tar -xz -C "$tmp/$1"
+}
+
+fetch swagger-ui-dist "$SWAGGER_UI_VERSION"
+mkdir -p "$dir/swagger"
+cp "$tmp/swagger-ui-dist/package/swagger-ui-bundle.js" "$tmp/swagger-ui-dist/package/swagger-ui.css" "$tmp/swagger-ui-dist/package/LICENSE" "$tmp/swagger-ui-dist/package/NOTICE" "$dir/swagger/"
+
+fetch redoc "$REDOC_VERSION"
+mkdir -p "$dir/redoc"
+cp "$tmp/redoc/package/bundles/redoc.standalone.js" "$tmp/redoc/package/LICENSE" "$dir/redoc/"
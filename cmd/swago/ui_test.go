@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIBundle(t *testing.T) {
+	uiDir, err := ioutil.TempDir("", "swago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(uiDir)
+	if err := ioutil.WriteFile(filepath.Join(uiDir, "redoc.standalone.js"), []byte("// redoc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	type params struct {
+		ui    string
+		uiDir string
+	}
+	type expected struct {
+		files []string
+		err   bool
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should serve the embedded swagger ui bundle",
+			params:   params{ui: swaggerUI},
+			expected: expected{files: []string{"swagger-ui-bundle.js", "swagger-ui.css"}},
+		},
+		{
+			name:     "should serve the embedded redoc bundle",
+			params:   params{ui: redocUI},
+			expected: expected{files: []string{"redoc.standalone.js"}},
+		},
+		{
+			name:     "should serve the bundle of the ui directory",
+			params:   params{ui: redocUI, uiDir: uiDir},
+			expected: expected{files: []string{"redoc.standalone.js"}},
+		},
+		{
+			name:     "should fail when the bundle of the ui directory is not complete",
+			params:   params{ui: swaggerUI, uiDir: uiDir},
+			expected: expected{err: true},
+		},
+		{
+			name:     "should fail with an unknown ui",
+			params:   params{ui: "rapidoc"},
+			expected: expected{err: true},
+		},
+		{
+			name:   "should not serve a bundle for the builtin ui",
+			params: params{ui: builtinUI},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bundle, err := uiBundle(test.params.ui, test.params.uiDir)
+			if test.expected.err {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			if len(test.expected.files) == 0 {
+				assert.Nil(t, bundle)
+				return
+			}
+			for _, f := range test.expected.files {
+				content, err := fs.ReadFile(bundle, f)
+				assert.NoError(t, err)
+				assert.NotEmpty(t, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func runValidateConfig(args []string) int {
+	var conf string
+	fs := flag.NewFlagSet("validate-config", flag.ContinueOnError)
+	fs.StringVar(&conf, "conf", "./swago.yaml", "Swago's config file")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		conf = fs.Arg(0)
+	}
+	conf, err := filepath.Abs(conf)
+	if err != nil {
+		return fail(exitUsage, "error getting absolute path of %s: %v", conf, err)
+	}
+	projectCriteria, err := readCriteria(conf, newLogger(false))
+	if err != nil {
+		return fail(exitConfig, "%v", err)
+	}
+	problems := projectCriteria.Lint()
+	for _, p := range problems {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", conf, p)
+	}
+	if len(problems) > 0 {
+		return exitConfig
+	}
+	fmt.Printf("%s is valid\n", conf)
+	return exitOK
+}
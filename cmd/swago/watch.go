@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"github.com/javiercbk/swago/encoding/swagger"
+	"github.com/javiercbk/swago/folder"
+)
+
+// watchInterval is how often the project is checked for changes
+const watchInterval = 250 * time.Millisecond
+
+// watcher regenerates the swagger file of a project when its go files or its config file change
+type watcher struct {
+	g               generateFlags
+	sg              *swago.SwaggerGenerator
+	projectCriteria criteria.Criteria
+	version         string
+	stamps          map[string]folder.Stamp
+	confStamp       folder.Stamp
+	output          []byte
+	diagnostics     map[diagnostic.Diagnostic]bool
+	// location is where the swagger file is written or published, as printed in the messages
+	location string
+	// publish receives the swagger file and its document instead of the outfile when it is set
+	publish func(swaggerYAML []byte, doc openapi2.Swagger, extensions swagger.Extensions)
+}
+
+// watchProject generates the swagger file and regenerates it after every change, only the
+// packages of the directories that changed are analyzed again
+func (g generateFlags) watchProject(logger *log.Logger) int {
+	w, code := g.newWatcher(logger)
+	if code != exitOK {
+		return code
+	}
+	w.regenerate()
+	return w.watch(logger)
+}
+
+// newWatcher reads the criteria and analyzes the project
+func (g generateFlags) newWatcher(logger *log.Logger) (*watcher, int) {
+	w := &watcher{g: g, location: g.outfile}
+	var err error
+	w.projectCriteria, err = readCriteria(g.conf, logger)
+	if err != nil {
+		return w, fail(exitConfig, "%v", err)
+	}
+	w.version, err = g.version(w.projectCriteria, "")
+	if err != nil {
+		return w, fail(exitAnalysis, "%v", err)
+	}
+	w.sg, err = g.newGenerator(w.projectCriteria, logger)
+	if err != nil {
+		return w, fail(exitAnalysis, "error creating a swagger generator: %v", err)
+	}
+	w.stamps, err = folder.StampGoFilesRecursively(g.dir, w.sg.Blacklist)
+	if err != nil {
+		return w, fail(exitAnalysis, "error listing the go files of %s: %v", g.dir, err)
+	}
+	w.confStamp = stampOf(g.conf)
+	return w, exitOK
+}
+
+// watch polls the project for changes and regenerates the swagger file after them
+func (w *watcher) watch(logger *log.Logger) int {
+	g := w.g
+	fmt.Printf("watching %s for changes\n", g.dir)
+	pending := make(map[string]bool)
+	confChanged := false
+	var lastChange time.Time
+	for range time.Tick(watchInterval) {
+		stamps, err := folder.StampGoFilesRecursively(g.dir, w.sg.Blacklist)
+		if err != nil {
+			fail(exitAnalysis, "error listing the go files of %s: %v", g.dir, err)
+			continue
+		}
+		for _, dir := range folder.ChangedDirs(w.stamps, stamps) {
+			pending[dir] = true
+			lastChange = time.Now()
+		}
+		w.stamps = stamps
+		if confStamp := stampOf(g.conf); !confStamp.Equal(w.confStamp) {
+			w.confStamp = confStamp
+			confChanged = true
+			lastChange = time.Now()
+		}
+		if (len(pending) == 0 && !confChanged) || time.Since(lastChange) < g.debounce {
+			continue
+		}
+		if confChanged {
+			confChanged = false
+			if code := w.reload(logger); code != exitOK {
+				continue
+			}
+			// the whole project was analyzed again with the new criteria
+			pending = make(map[string]bool)
+			w.regenerate()
+			continue
+		}
+		dirs := make([]string, 0, len(pending))
+		for dir := range pending {
+			dirs = append(dirs, dir)
+		}
+		pending = make(map[string]bool)
+		if err := w.sg.Reanalyze(dirs); err != nil {
+			fail(exitAnalysis, "error analyzing the project: %v", err)
+			continue
+		}
+		w.regenerate()
+	}
+	return exitOK
+}
+
+// reload reads the criteria again and creates a new generator, so the vendor folders of
+// the new criteria are analyzed
+func (w *watcher) reload(logger *log.Logger) int {
+	g := w.g
+	projectCriteria, err := readCriteria(g.conf, logger)
+	if err != nil {
+		return fail(exitConfig, "%v", err)
+	}
+	sg, err := g.newGenerator(projectCriteria, logger)
+	if err != nil {
+		return fail(exitAnalysis, "error creating a swagger generator: %v", err)
+	}
+	stamps, err := folder.StampGoFilesRecursively(g.dir, sg.Blacklist)
+	if err != nil {
+		return fail(exitAnalysis, "error listing the go files of %s: %v", g.dir, err)
+	}
+	w.projectCriteria = projectCriteria
+	w.sg = sg
+	w.stamps = stamps
+	if version, err := g.version(projectCriteria, w.version); err == nil {
+		w.version = version
+	}
+	return exitOK
+}
+
+// regenerate writes or publishes the swagger file when it changed and prints the diagnostics that changed
+func (w *watcher) regenerate() {
+	buf := &bytes.Buffer{}
+	start := time.Now()
+	swaggerDoc, code := w.g.document(w.sg, w.projectCriteria, w.version)
+	w.printDiagnostics()
+	if code != exitOK {
+		return
+	}
+	if err := marshal(w.g.format, swaggerDoc, w.sg.Extensions, buf); err != nil {
+		fail(exitOutput, "error marshaling swagger %s: %v", w.g.format, err)
+		return
+	}
+	if bytes.Equal(buf.Bytes(), w.output) {
+		fmt.Printf("%s is up to date\n", w.location)
+		return
+	}
+	if w.publish != nil {
+		w.output = buf.Bytes()
+		w.publish(w.output, swaggerDoc, w.sg.Extensions)
+		fmt.Printf("regenerated %s in %v\n", w.location, time.Since(start).Round(time.Millisecond))
+		return
+	}
+	if err := ioutil.WriteFile(w.g.outfile, buf.Bytes(), 0666); err != nil {
+		fail(exitOutput, "error writing swagger yaml to path %s: %v", w.g.outfile, err)
+		return
+	}
+	w.output = buf.Bytes()
+	fmt.Printf("wrote %s in %v\n", w.location, time.Since(start).Round(time.Millisecond))
+}
+
+// printDiagnostics prints the diagnostics that were not found by the previous generation and the
+// ones that were solved, json diagnostics are printed as a whole
+func (w *watcher) printDiagnostics() {
+	if w.g.diagnostics != textDiagnostics {
+		if err := printDiagnostics(os.Stderr, w.g.diagnostics, w.g.dir, w.sg.Diagnostics); err != nil {
+			fail(exitUsage, "%v", err)
+		}
+		return
+	}
+	current := make(map[diagnostic.Diagnostic]bool)
+	for _, d := range diagnostic.Relative(w.sg.Diagnostics.Diagnostics(), w.g.dir) {
+		current[d] = true
+		if !w.diagnostics[d] {
+			fmt.Fprintln(os.Stderr, d.String())
+		}
+	}
+	for d := range w.diagnostics {
+		if !current[d] {
+			fmt.Fprintf(os.Stderr, "solved: %s\n", d.String())
+		}
+	}
+	w.diagnostics = current
+}
+
+// stampOf returns the stamp of a file, the zero stamp when it can not be read
+func stampOf(filePath string) folder.Stamp {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return folder.Stamp{}
+	}
+	return folder.Stamp{ModTime: info.ModTime(), Size: info.Size()}
+}
@@ -0,0 +1,169 @@
+package swago
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/pkg"
+)
+
+const (
+	missingRoute         = "route not documented"
+	missingHandler       = "handler not found"
+	missingRequestModel  = "no request model"
+	missingResponseModel = "no response model"
+	dummyResponses       = "responses without a model: "
+)
+
+// bodylessMethods are the http methods whose routes are not expected to have a request model
+var bodylessMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"DELETE":  true,
+	"OPTIONS": true,
+}
+
+// Coverage is how much of the API is documented
+type Coverage struct {
+	// Percentage is the percentage of routes and unattached handlers that are fully documented
+	Percentage       float64 `json:"percentage"`
+	Routes           int     `json:"routes"`
+	DocumentedRoutes int     `json:"documentedRoutes"`
+	// PartialRoutes are the routes that are not fully documented
+	PartialRoutes []RouteCoverage `json:"partialRoutes"`
+	// UnattachedHandlers are the exported handlers that are not the handler of any route
+	UnattachedHandlers []HandlerCoverage `json:"unattachedHandlers"`
+}
+
+// RouteCoverage is a route and what is missing from its documentation
+type RouteCoverage struct {
+	Route    string         `json:"route"`
+	Handler  string         `json:"handler,omitempty"`
+	Position token.Position `json:"-"`
+	Missing  []string       `json:"missing"`
+}
+
+// HandlerCoverage is a function with the signature of a handler
+type HandlerCoverage struct {
+	Handler  string         `json:"handler"`
+	Position token.Position `json:"-"`
+}
+
+// Coverage reports the routes of a generated swagger documentation that are not fully documented
+// and the handlers that are not attached to any route
+func (s *SwaggerGenerator) Coverage(swagger *openapi2.Swagger) Coverage {
+	coverage := Coverage{
+		PartialRoutes:      make([]RouteCoverage, 0),
+		UnattachedHandlers: make([]HandlerCoverage, 0),
+	}
+	attached := make(map[token.Position]bool)
+	for _, r := range s.routes {
+		if r.Handler != nil {
+			attached[r.Handler.Position()] = true
+			if pkg.HasAnnotation(r.Handler.Annotations, pkg.AnnotationIgnore) {
+				continue
+			}
+		}
+		coverage.Routes++
+		missing := routeMissing(r, swagger)
+		if len(missing) == 0 {
+			coverage.DocumentedRoutes++
+			continue
+		}
+		coverage.PartialRoutes = append(coverage.PartialRoutes, RouteCoverage{
+			Route:    r.Endpoint(),
+			Handler:  r.HandlerType,
+			Position: r.Position,
+			Missing:  missing,
+		})
+	}
+	for _, p := range s.Pkgs {
+		for _, f := range p.Files {
+			for _, fun := range f.Functions {
+				if !ast.IsExported(fun.Name) || !fun.IsHandler() || attached[fun.Position()] {
+					continue
+				}
+				name := p.Name + "." + fun.Name
+				if len(fun.MemberOf) > 0 {
+					name = p.Name + "." + strings.TrimPrefix(fun.MemberOf, "*") + "." + fun.Name
+				}
+				coverage.UnattachedHandlers = append(coverage.UnattachedHandlers, HandlerCoverage{
+					Handler:  name,
+					Position: fun.Position(),
+				})
+			}
+		}
+	}
+	sort.SliceStable(coverage.PartialRoutes, func(i, j int) bool {
+		return coverage.PartialRoutes[i].Route < coverage.PartialRoutes[j].Route
+	})
+	sort.SliceStable(coverage.UnattachedHandlers, func(i, j int) bool {
+		return coverage.UnattachedHandlers[i].Handler < coverage.UnattachedHandlers[j].Handler
+	})
+	total := coverage.Routes + len(coverage.UnattachedHandlers)
+	if total > 0 {
+		coverage.Percentage = float64(coverage.DocumentedRoutes) * 100 / float64(total)
+	}
+	return coverage
+}
+
+// routeMissing returns what is missing from the documentation of a route
+func routeMissing(r pkg.Route, swagger *openapi2.Swagger) []string {
+	missing := make([]string, 0)
+	var operation *openapi2.Operation
+	if pathItem, ok := swagger.Paths[r.Path]; ok && criteria.MatchesHTTPMethod(r.HTTPMethod) {
+		operation = pathItem.GetOperation(strings.ToUpper(r.HTTPMethod))
+	}
+	if operation == nil {
+		return append(missing, missingRoute)
+	}
+	if r.Handler == nil {
+		return append(missing, missingHandler)
+	}
+	if !bodylessMethods[strings.ToUpper(r.HTTPMethod)] && len(r.RequestModel.Name) == 0 && len(r.RequestModel.Fields) == 0 {
+		missing = append(missing, missingRequestModel)
+	}
+	codes := make([]string, 0, len(operation.Responses))
+	for code := range operation.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	modeled := 0
+	dummies := make([]string, 0)
+	for _, code := range codes {
+		resp := operation.Responses[code]
+		if len(resp.Ref) > 0 {
+			// global responses are documented once
+			continue
+		}
+		if isDummySchema(resp.Schema, swagger.Definitions) {
+			dummies = append(dummies, code)
+		} else {
+			modeled++
+		}
+	}
+	if modeled == 0 && len(dummies) == 0 {
+		missing = append(missing, missingResponseModel)
+	}
+	if len(dummies) > 0 {
+		missing = append(missing, dummyResponses+strings.Join(dummies, ", "))
+	}
+	return missing
+}
+
+// isDummySchema returns true for a missing schema or an object without properties
+func isDummySchema(ref *openapi3.SchemaRef, definitions map[string]*openapi3.SchemaRef) bool {
+	if ref != nil && strings.HasPrefix(ref.Ref, definitionsRefPrefix) {
+		ref = definitions[strings.TrimPrefix(ref.Ref, definitionsRefPrefix)]
+	}
+	if ref == nil || ref.Value == nil {
+		return true
+	}
+	sch := ref.Value
+	return sch.Type == "object" && len(sch.Properties) == 0 && sch.AdditionalProperties == nil && sch.AdditionalPropertiesAllowed == nil
+}
@@ -0,0 +1,131 @@
+package swago
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoverage(t *testing.T) {
+	user := openapi3.NewSchemaRef("#/definitions/User", nil)
+	object := openapi3.NewSchemaRef("", &openapi3.Schema{Type: "object"})
+	swagger := &openapi2.Swagger{
+		Definitions: map[string]*openapi3.SchemaRef{
+			"User": openapi3.NewSchemaRef("", &openapi3.Schema{
+				Type:       "object",
+				Properties: map[string]*openapi3.SchemaRef{"name": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string"})},
+			}),
+		},
+		Paths: map[string]*openapi2.PathItem{
+			"/users": {
+				Get: &openapi2.Operation{Responses: map[string]*openapi2.Response{
+					"200": {Schema: user},
+					"500": {Ref: "#/responses/InternalServerError"},
+				}},
+				Post: &openapi2.Operation{Responses: map[string]*openapi2.Response{
+					"201": {Schema: user},
+					"400": {Schema: object},
+				}},
+			},
+			"/health": {
+				Get: &openapi2.Operation{Responses: map[string]*openapi2.Response{}},
+			},
+			"/orders": {
+				Get: &openapi2.Operation{Responses: map[string]*openapi2.Response{"200": {Schema: user}}},
+			},
+		},
+	}
+	handler := &pkg.Function{Name: "handle"}
+	s := &SwaggerGenerator{
+		routes: []pkg.Route{
+			{Path: "/users", HTTPMethod: "GET", HandlerType: "handler.ListUsers", Handler: handler},
+			{Path: "/users", HTTPMethod: "POST", HandlerType: "handler.CreateUser", Handler: handler},
+			{Path: "/health", HTTPMethod: "GET", HandlerType: "handler.Health", Handler: handler},
+			{Path: "/admin", HTTPMethod: "GET", HandlerType: "admin.Index"},
+			{Path: "/orders", HTTPMethod: "GET", HandlerType: "orders.List"},
+			{Path: "/ignored", HTTPMethod: "GET", HandlerType: "handler.Ignored", Handler: &pkg.Function{
+				Name:        "ignored",
+				Annotations: []pkg.Annotation{{Name: pkg.AnnotationIgnore}},
+			}},
+		},
+	}
+	coverage := s.Coverage(swagger)
+	assert.Equal(t, 5, coverage.Routes, "ignored routes are not counted")
+	assert.Equal(t, 1, coverage.DocumentedRoutes)
+	assert.Equal(t, 20.0, coverage.Percentage)
+	missing := make(map[string][]string)
+	for _, r := range coverage.PartialRoutes {
+		missing[r.Route] = r.Missing
+	}
+	assert.Equal(t, map[string][]string{
+		"POST /users": {missingRequestModel, dummyResponses + "400"},
+		"GET /health": {missingResponseModel},
+		"GET /admin":  {missingRoute},
+		"GET /orders": {missingHandler},
+	}, missing)
+}
+
+func TestIsDummySchema(t *testing.T) {
+	definitions := map[string]*openapi3.SchemaRef{
+		"Empty": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "object"}),
+		"User": openapi3.NewSchemaRef("", &openapi3.Schema{
+			Type:       "object",
+			Properties: map[string]*openapi3.SchemaRef{"name": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string"})},
+		}),
+	}
+	type params struct {
+		schema *openapi3.SchemaRef
+	}
+	type expected struct {
+		dummy bool
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "missing schema",
+			params:   params{},
+			expected: expected{dummy: true},
+		},
+		{
+			name:     "object without properties",
+			params:   params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "object"})},
+			expected: expected{dummy: true},
+		},
+		{
+			name:     "definition without properties",
+			params:   params{schema: openapi3.NewSchemaRef("#/definitions/Empty", nil)},
+			expected: expected{dummy: true},
+		},
+		{
+			name:     "missing definition",
+			params:   params{schema: openapi3.NewSchemaRef("#/definitions/Missing", nil)},
+			expected: expected{dummy: true},
+		},
+		{
+			name:     "definition with properties",
+			params:   params{schema: openapi3.NewSchemaRef("#/definitions/User", nil)},
+			expected: expected{dummy: false},
+		},
+		{
+			name:     "map",
+			params:   params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "object", AdditionalProperties: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string"})})},
+			expected: expected{dummy: false},
+		},
+		{
+			name:     "array",
+			params:   params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "array", Items: openapi3.NewSchemaRef("#/definitions/User", nil)})},
+			expected: expected{dummy: false},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.dummy, isDummySchema(test.params.schema, definitions))
+		})
+	}
+}
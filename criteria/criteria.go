@@ -5,6 +5,8 @@ import (
 	"log"
 	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi2"
@@ -48,14 +50,47 @@ const (
 	MaxLengthValidation = "maxLength"
 	// PatternValidation is the swagger pattern validation
 	PatternValidation = "pattern"
+	// FormatValidation is the swagger format validation
+	FormatValidation = "format"
+	// MultipleOfValidation is the swagger multipleOf validation
+	MultipleOfValidation = "multipleOf"
+	// MinItemsValidation is the swagger minItems validation
+	MinItemsValidation = "minItems"
+	// MaxItemsValidation is the swagger maxItems validation
+	MaxItemsValidation = "maxItems"
+	// UniqueItemsValidation is the swagger uniqueItems validation
+	UniqueItemsValidation = "uniqueItems"
+	// DefaultValidation extracts the swagger default value
+	DefaultValidation = "default"
+	// ExampleValidation extracts the swagger example value
+	ExampleValidation = "example"
+	// DefaultExampleTag is the regular expression of the default example extractor
+	DefaultExampleTag = `example:"([^"]*)"`
+	// ValidationValueGroup is the name of the capture group that holds a validation value
+	ValidationValueGroup = "value"
 	// ErrInvalidCallCriteria is returned when a Criteria contains an invalid callCriteria
 	ErrInvalidCallCriteria ParserErr = "invalid response criteria"
-	requestCallCriteria    string    = "request"
-	responseCallCriteria   string    = "response"
+	// ErrInvalidTagStrategy is returned when a Criteria contains an unknown tag strategy
+	ErrInvalidTagStrategy ParserErr = "invalid tag strategy"
+	// ErrInvalidGlobalResponse is returned when a global response code is not a status code nor default
+	ErrInvalidGlobalResponse ParserErr = "invalid global response code"
+	// DefaultResponseCode is the code of the response used for any undeclared status code
+	DefaultResponseCode = "default"
+	// TagByPackage tags an operation with the package of its handler
+	TagByPackage = "package"
+	// TagByReceiver tags an operation with the receiver type of its handler
+	TagByReceiver = "receiver"
+	// TagByPath tags an operation with the first segment of its path
+	TagByPath = "path"
+	// TagByNone does not tag operations
+	TagByNone                   = "none"
+	requestCallCriteria  string = "request"
+	responseCallCriteria string = "response"
 )
 
 var (
-	defaultURLNamedPathVarExtractor = regexp.MustCompile("\\{([a-zA-Z0-9]+)\\}")
+	// DefaultURLNamedPathVarExtractor extracts the path variables of a swagger path
+	DefaultURLNamedPathVarExtractor = regexp.MustCompile("\\{([a-zA-Z0-9]+)\\}")
 	// httpMethods
 	httpMethods = [...]string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch}
 )
@@ -73,6 +108,66 @@ type Criteria struct {
 	Response            []CallCriteria                      `yaml:"response"`
 	StaticModels        map[string]*openapi3.Schema         `yaml:"staticModels"`
 	VendorFolders       []string                            `yaml:"vendorFolders"`
+	Validations         map[string]ValidationExtractor      `yaml:"validations"`
+	Tags                TagsCriteria                        `yaml:"tags"`
+	RouteGroups         []RouteGroup                        `yaml:"routeGroups"`
+	SecurityMiddlewares map[string]MiddlewareMatcher        `yaml:"securityMiddlewares"`
+	ResponseHeaders     []ResponseHeader                    `yaml:"responseHeaders"`
+	ErrorModel          string                              `yaml:"errorModel"`
+	GlobalResponses     map[string]GlobalResponse           `yaml:"globalResponses"`
+	TypeOverrides       map[string]TypeOverride             `yaml:"typeOverrides"`
+	Nullability         Nullability                         `yaml:"nullability"`
+	Examples            Examples                            `yaml:"examples"`
+	// ValidationConflicts are the validations of the call criteria that were not merged
+	// because a different validation of the same name was declared before
+	ValidationConflicts []ValidationConflict `yaml:"-"`
+}
+
+// ValidationConflict is a validation of a call criteria that differs from the validation
+// of the same name declared before it, the validation declared first is used
+type ValidationConflict struct {
+	Name string
+	// CallCriteria describes the call criteria of the ignored validation as in response api.JSON
+	CallCriteria string
+}
+
+// Examples defines how the examples of the schemas are generated, example tags
+// are always read
+type Examples struct {
+	// Synthesize generates examples from the enums, defaults and formats of the schemas
+	Synthesize bool `yaml:"synthesize"`
+	// TestFiles reads examples from the composite literals found in test files, the values
+	// are copied verbatim to the document
+	TestFiles bool `yaml:"testFiles"`
+	// Responses adds an example of the whole model to the responses
+	Responses bool `yaml:"responses"`
+}
+
+// Nullability is the policy used to document the fields that might be null or missing
+type Nullability struct {
+	// IgnorePointers does not mark the pointer fields as nullable
+	IgnorePointers bool `yaml:"ignorePointers"`
+	// RequiredResponseFields marks the response fields that are always present as required,
+	// which are the fields that are neither pointers nor omitempty
+	RequiredResponseFields bool `yaml:"requiredResponseFields"`
+}
+
+// GlobalResponse is a response shared by every operation, it is declared once in the
+// swagger responses and referenced by the operations
+type GlobalResponse struct {
+	// Name is the name of the response in the swagger responses, it defaults to the status text
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Model is a go type as in response.ErrorBody or a static model, it defaults to the error model
+	Model string `yaml:"model"`
+	// SecuredOnly adds the response only to the operations that require security
+	SecuredOnly bool `yaml:"securedOnly"`
+}
+
+// TagsCriteria defines how operations are tagged
+type TagsCriteria struct {
+	// Strategy is one of package, receiver, path or none, it defaults to package
+	Strategy string `yaml:"strategy"`
 }
 
 // Info is the info swagger mapping
@@ -82,7 +177,8 @@ type Info struct {
 	Description string `yaml:"description"`
 }
 
-// FuncRoute matches a route that is defined as a function call
+// FuncRoute matches a route that is defined as a function call, Pkg is the name
+// or the import path of the package of the router
 type FuncRoute struct {
 	FuncName                    string         `yaml:"funcName"`
 	Pkg                         string         `yaml:"pkg"`
@@ -91,7 +187,50 @@ type FuncRoute struct {
 	NamedPathVarExtractorRegexp *regexp.Regexp `yaml:"-"`
 	PathIndex                   int            `yaml:"pathIndex"`
 	HandlerIndex                int            `yaml:"handlerIndex"`
-	ChildRoute                  *FuncRoute     `yaml:"childRoute,omitempty"`
+	// MiddlewareIndex is the index of the first middleware argument, 0 means the route has no middlewares
+	MiddlewareIndex int        `yaml:"middlewareIndex"`
+	ChildRoute      *FuncRoute `yaml:"childRoute,omitempty"`
+}
+
+// RouteGroup matches a function call that creates a group of routes sharing a
+// path prefix and middlewares, as in e.Group("/api", middleware)
+type RouteGroup struct {
+	// Pkg is the name or the import path of the package of the router
+	Pkg      string `yaml:"pkg"`
+	FuncName string `yaml:"funcName"`
+	// PathIndex is the index of the path prefix argument
+	PathIndex int `yaml:"pathIndex"`
+	// MiddlewareIndex is the index of the first middleware argument, 0 means the group has no middlewares
+	MiddlewareIndex int `yaml:"middlewareIndex"`
+	// UseFuncName is the function that adds middlewares to a group or a router, as in group.Use(middleware)
+	UseFuncName string `yaml:"useFuncName"`
+}
+
+// MiddlewareMatcher matches a middleware applied to a route. The regular expression
+// is matched against the middleware expression as in security.JWT(secret, false)
+type MiddlewareMatcher struct {
+	Matches       string         `yaml:"matches"`
+	MatchesRegExp *regexp.Regexp `yaml:"-"`
+	// Scopes matches the middlewares that require scopes as in RequireScope("users:write"),
+	// every quoted string in the captured value is a scope
+	Scopes       string         `yaml:"scopes"`
+	ScopesRegExp *regexp.Regexp `yaml:"-"`
+	// Alternatives are the security definitions that can be used instead of this one
+	Alternatives []string `yaml:"alternatives"`
+}
+
+// ResponseHeader describes a response header. Headers set by a handler are
+// detected in its code, headers set by middlewares are added to the responses of
+// the routes using a matching middleware.
+type ResponseHeader struct {
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"`
+	Description string `yaml:"description"`
+	// Middleware matches the middlewares that set the header
+	Middleware       string         `yaml:"middleware"`
+	MiddlewareRegExp *regexp.Regexp `yaml:"-"`
+	// Codes are the response codes that include the header, empty means every response
+	Codes []string `yaml:"codes"`
 }
 
 // ParameterMatcher matches parameters in a struct route
@@ -121,13 +260,53 @@ type RouteCriteria struct {
 	FuncRoute   *FuncRoute   `yaml:"funcRoute"`
 }
 
-// ValidationExtractor are slices of regular expression that matches validations
+// ValidationExtractor are slices of regular expression that matches validations.
+// The value of a validation is taken from the capture group named "value", if there
+// is no such group the first capture group is used.
 type ValidationExtractor struct {
-	Validation string `yaml:"validation"`
-	// Tag        []string         `yaml:"tag"`
+	Validation string   `yaml:"validation"`
+	Tag        []string `yaml:"tag"`
+	// Separator splits a captured value into many, it is only used by enum validations
+	Separator string           `yaml:"separator"`
 	TagRegexp []*regexp.Regexp `yaml:"-"`
 }
 
+// mergeValidations adds the validations of some call criteria to the validations of the
+// criteria, a validation that differs from the one declared before is a conflict
+func (c *Criteria) mergeValidations(kind string, callCriterias []CallCriteria) {
+	for _, cc := range callCriterias {
+		names := make([]string, 0, len(cc.Validations))
+		for k := range cc.Validations {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			declared, ok := c.Validations[k]
+			if !ok {
+				c.Validations[k] = cc.Validations[k]
+			} else if !declared.equal(cc.Validations[k]) {
+				c.ValidationConflicts = append(c.ValidationConflicts, ValidationConflict{
+					Name:         k,
+					CallCriteria: kind + " " + cc.Pkg + "." + cc.FuncName,
+				})
+			}
+		}
+	}
+}
+
+// equal returns true if two validations are declared with the same expressions
+func (v ValidationExtractor) equal(other ValidationExtractor) bool {
+	if v.Validation != other.Validation || v.Separator != other.Separator || len(v.Tag) != len(other.Tag) {
+		return false
+	}
+	for i := range v.Tag {
+		if v.Tag[i] != other.Tag[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // ModelExtractor defines where to extract a model
 type ModelExtractor struct {
 	ParamIndex int    `yaml:"paramIndex"`
@@ -136,13 +315,15 @@ type ModelExtractor struct {
 
 // CallCriteria contains all the information to match a function call with an argument
 type CallCriteria struct {
-	Pkg            string                         `yaml:"pkg"`
-	FuncName       string                         `yaml:"funcName"`
-	ModelExtractor ModelExtractor                 `yaml:"modelExtractor"`
-	CodeIndex      int                            `yaml:"codeIndex"`
-	Validations    map[string]ValidationExtractor `yaml:"validations"`
-	Consumes       string                         `yaml:"consumes"`
-	Produces       string                         `yaml:"produces"`
+	Pkg            string         `yaml:"pkg"`
+	FuncName       string         `yaml:"funcName"`
+	ModelExtractor ModelExtractor `yaml:"modelExtractor"`
+	CodeIndex      int            `yaml:"codeIndex"`
+	// Deprecated: declare the validations in Criteria.Validations, validations declared
+	// here are merged into them unless a different validation of the same name exists
+	Validations map[string]ValidationExtractor `yaml:"validations"`
+	Consumes    string                         `yaml:"consumes"`
+	Produces    string                         `yaml:"produces"`
 }
 
 // Decoder is able to decode and validate a Criteria
@@ -158,9 +339,17 @@ func (decoder Decoder) ParseCriteriaFromYAML(r io.Reader, c *Criteria) error {
 		decoder.Logger.Printf("error decoding criteria from reader: %v\n", err)
 		return err
 	}
+	switch c.Tags.Strategy {
+	case "":
+		c.Tags.Strategy = TagByPackage
+	case TagByPackage, TagByReceiver, TagByPath, TagByNone:
+	default:
+		decoder.Logger.Printf("unknown tag strategy %s\n", c.Tags.Strategy)
+		return ErrInvalidTagStrategy
+	}
 	for i := range c.Routes {
 		if c.Routes[i].StructRoute != nil {
-			namedPathVarExtractor := defaultURLNamedPathVarExtractor
+			namedPathVarExtractor := DefaultURLNamedPathVarExtractor
 			if len(c.Routes[i].StructRoute.NamedPathVarExtractor) > 0 {
 				namedPathVarExtractor, err = regexp.Compile(c.Routes[i].StructRoute.NamedPathVarExtractor)
 				if err != nil {
@@ -194,7 +383,7 @@ func (decoder Decoder) ParseCriteriaFromYAML(r io.Reader, c *Criteria) error {
 				}
 			}
 		} else if c.Routes[i].FuncRoute != nil {
-			namedPathVarExtractor := defaultURLNamedPathVarExtractor
+			namedPathVarExtractor := DefaultURLNamedPathVarExtractor
 			if len(c.Routes[i].FuncRoute.NamedPathVarExtractor) > 0 {
 				namedPathVarExtractor, err = regexp.Compile(c.Routes[i].FuncRoute.NamedPathVarExtractor)
 				if err != nil {
@@ -204,19 +393,75 @@ func (decoder Decoder) ParseCriteriaFromYAML(r io.Reader, c *Criteria) error {
 			c.Routes[i].FuncRoute.NamedPathVarExtractorRegexp = namedPathVarExtractor
 		}
 	}
-	for i := range c.Request {
-		if c.Request[i].Validations != nil {
-			for k, v := range c.Request[i].Validations {
-				if len(v.Validation) > 0 {
-					tagRegexp, err := regexp.Compile(v.Validation)
-					if err != nil {
-						decoder.Logger.Printf("error processing validation %s, could not compile regexp '%s': %v", k, v.Validation, err)
-					}
-					v.TagRegexp = []*regexp.Regexp{tagRegexp}
-					c.Request[i].Validations[k] = v
-				}
+	for k, v := range c.SecurityMiddlewares {
+		if len(v.Matches) > 0 {
+			v.MatchesRegExp, err = regexp.Compile(v.Matches)
+			if err != nil {
+				decoder.Logger.Printf("error processing security middleware %s, could not compile regexp '%s': %v", k, v.Matches, err)
+				return err
+			}
+		}
+		if len(v.Scopes) > 0 {
+			v.ScopesRegExp, err = regexp.Compile(v.Scopes)
+			if err != nil {
+				decoder.Logger.Printf("error processing security middleware %s, could not compile regexp '%s': %v", k, v.Scopes, err)
+				return err
+			}
+		}
+		c.SecurityMiddlewares[k] = v
+	}
+	for i := range c.ResponseHeaders {
+		if len(c.ResponseHeaders[i].Middleware) > 0 {
+			c.ResponseHeaders[i].MiddlewareRegExp, err = regexp.Compile(c.ResponseHeaders[i].Middleware)
+			if err != nil {
+				decoder.Logger.Printf("error processing response header %s, could not compile regexp '%s': %v", c.ResponseHeaders[i].Name, c.ResponseHeaders[i].Middleware, err)
+				return err
+			}
+		}
+	}
+	for code := range c.GlobalResponses {
+		statusCode, err := strconv.Atoi(code)
+		if code != DefaultResponseCode && (err != nil || len(http.StatusText(statusCode)) == 0) {
+			decoder.Logger.Printf("error processing global response %s: %v", code, ErrInvalidGlobalResponse)
+			return ErrInvalidGlobalResponse
+		}
+	}
+	if c.TypeOverrides == nil {
+		c.TypeOverrides = make(map[string]TypeOverride)
+	}
+	for k, v := range DefaultTypeOverrides() {
+		if _, ok := c.TypeOverrides[k]; !ok {
+			c.TypeOverrides[k] = v
+		}
+	}
+	if c.Validations == nil {
+		c.Validations = make(map[string]ValidationExtractor)
+	}
+	// validations belong to the models, not to the call that parses or writes them,
+	// so any validation declared in a call criteria applies to every model
+	c.ValidationConflicts = nil
+	c.mergeValidations(requestCallCriteria, c.Request)
+	c.mergeValidations(responseCallCriteria, c.Response)
+	if _, ok := c.Validations[ExampleValidation]; !ok {
+		c.Validations[ExampleValidation] = ValidationExtractor{
+			Tag: []string{DefaultExampleTag},
+		}
+	}
+	for k, v := range c.Validations {
+		expressions := v.Tag
+		if len(v.Validation) > 0 {
+			expressions = append([]string{v.Validation}, expressions...)
+		}
+		v.TagRegexp = make([]*regexp.Regexp, 0, len(expressions))
+		for _, e := range expressions {
+			tagRegexp, err := regexp.Compile(e)
+			if err != nil {
+				decoder.Logger.Printf("error processing validation %s, could not compile regexp '%s': %v", k, e, err)
+				return err
 			}
+			v.TagRegexp = append(v.TagRegexp, tagRegexp)
 		}
+		c.Validations[k] = v
 	}
 	return nil
 }
@@ -0,0 +1,109 @@
+package criteria
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ErrUnknownSecurityDefinition is returned when a criteria references a missing security definition
+	ErrUnknownSecurityDefinition ParserErr = "unknown security definition"
+	// ErrInvalidModel is returned when a model is neither a go type nor a static model
+	ErrInvalidModel ParserErr = "invalid model"
+)
+
+// Lint returns the problems of a parsed criteria, a criteria with problems can be
+// used but it produces an incomplete document
+func (c Criteria) Lint() []error {
+	problems := make([]error, 0)
+	if len(c.Routes) == 0 {
+		problems = append(problems, ErrMissingRoutes)
+	}
+	if len(c.Request) == 0 {
+		problems = append(problems, ErrMissingRequest)
+	}
+	if len(c.Response) == 0 {
+		problems = append(problems, ErrMissingResponse)
+	}
+	for i, r := range c.Routes {
+		if err := r.lint(); err != nil {
+			problems = append(problems, fmt.Errorf("%w: route %d %s", ErrInvalidRoute, i, err.Error()))
+		}
+	}
+	for i, cc := range c.Request {
+		if err := cc.lint(); err != nil {
+			problems = append(problems, fmt.Errorf("%w: request %d %s", ErrInvalidCallCriteria, i, err.Error()))
+		}
+	}
+	for i, cc := range c.Response {
+		if err := cc.lint(); err != nil {
+			problems = append(problems, fmt.Errorf("%w: response %d %s", ErrInvalidCallCriteria, i, err.Error()))
+		}
+	}
+	for name, m := range c.SecurityMiddlewares {
+		if _, ok := c.SecurityDefinitions[name]; !ok {
+			problems = append(problems, fmt.Errorf("%w: security middleware %s", ErrUnknownSecurityDefinition, name))
+		}
+		for _, alternative := range m.Alternatives {
+			if _, ok := c.SecurityDefinitions[alternative]; !ok {
+				problems = append(problems, fmt.Errorf("%w: alternative %s of security middleware %s", ErrUnknownSecurityDefinition, alternative, name))
+			}
+		}
+	}
+	if len(c.ErrorModel) > 0 && !c.isModel(c.ErrorModel) {
+		problems = append(problems, fmt.Errorf("%w: error model %s", ErrInvalidModel, c.ErrorModel))
+	}
+	for code, gr := range c.GlobalResponses {
+		if len(gr.Model) > 0 && !c.isModel(gr.Model) {
+			problems = append(problems, fmt.Errorf("%w: model %s of global response %s", ErrInvalidModel, gr.Model, code))
+		}
+	}
+	for i, rh := range c.ResponseHeaders {
+		if len(rh.Name) == 0 {
+			problems = append(problems, fmt.Errorf("response header %d has no name", i))
+		}
+	}
+	return problems
+}
+
+// isModel returns true if a model is a static model or a go type as in pkg.Type
+func (c Criteria) isModel(model string) bool {
+	if _, ok := c.StaticModels[model]; ok {
+		return true
+	}
+	return strings.Contains(model, ".")
+}
+
+func (r RouteCriteria) lint() error {
+	switch {
+	case r.StructRoute != nil:
+		sr := r.StructRoute
+		if len(sr.Name) == 0 || len(sr.PathField) == 0 || len(sr.HandlerField) == 0 {
+			return fmt.Errorf("needs a name, a pathField and a handlerField")
+		}
+	case r.FuncRoute != nil:
+		fr := r.FuncRoute
+		if len(fr.FuncName) == 0 {
+			return fmt.Errorf("needs a funcName")
+		}
+		if fr.PathIndex < 0 || fr.HandlerIndex < 0 || fr.PathIndex == fr.HandlerIndex {
+			return fmt.Errorf("needs a different pathIndex and handlerIndex")
+		}
+		if len(fr.HTTPMethod) > 0 && !MatchesHTTPMethod(fr.HTTPMethod) {
+			return fmt.Errorf("has an unknown httpMethod %s", fr.HTTPMethod)
+		}
+	default:
+		return fmt.Errorf("needs a structRoute or a funcRoute")
+	}
+	return nil
+}
+
+func (cc CallCriteria) lint() error {
+	if len(cc.FuncName) == 0 {
+		return fmt.Errorf("needs a funcName")
+	}
+	if cc.ModelExtractor.ParamIndex < 0 || cc.CodeIndex < 0 {
+		return fmt.Errorf("has a negative index")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package criteria
+
+// TypeOverride is the schema of a go type that is not documented from its
+// declaration, as types that implement json.Marshaler or encoding.TextMarshaler
+type TypeOverride struct {
+	Type     string `yaml:"type"`
+	Format   string `yaml:"format"`
+	Pattern  string `yaml:"pattern"`
+	Nullable bool   `yaml:"nullable"`
+}
+
+const (
+	uuidPattern = "^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$"
+)
+
+var (
+	uuidOverride      = TypeOverride{Type: "string", Format: "uuid", Pattern: uuidPattern}
+	nullString        = TypeOverride{Type: "string", Nullable: true}
+	nullInt           = TypeOverride{Type: "integer", Format: "int64", Nullable: true}
+	nullInt32         = TypeOverride{Type: "integer", Format: "int32", Nullable: true}
+	nullFloat         = TypeOverride{Type: "number", Format: "double", Nullable: true}
+	nullBool          = TypeOverride{Type: "boolean", Nullable: true}
+	nullTime          = TypeOverride{Type: "string", Format: "date-time", Nullable: true}
+	nullableLibraries = []string{"gopkg.in/guregu/null.v3", "gopkg.in/guregu/null.v4", "github.com/guregu/null/v5"}
+)
+
+// DefaultTypeOverrides returns the schema of the types of popular libraries,
+// the keys are the import path and the name of a type
+func DefaultTypeOverrides() map[string]TypeOverride {
+	overrides := map[string]TypeOverride{
+		"github.com/google/uuid.UUID":    uuidOverride,
+		"github.com/gofrs/uuid.UUID":     uuidOverride,
+		"github.com/satori/go.uuid.UUID": uuidOverride,
+		"github.com/shopspring/decimal.Decimal": {
+			Type:   "string",
+			Format: "decimal",
+		},
+		"github.com/shopspring/decimal.NullDecimal": {
+			Type:     "string",
+			Format:   "decimal",
+			Nullable: true,
+		},
+		"time.Duration": {
+			Type:   "integer",
+			Format: "int64",
+		},
+		"database/sql.NullString":  nullString,
+		"database/sql.NullInt64":   nullInt,
+		"database/sql.NullInt32":   nullInt32,
+		"database/sql.NullInt16":   nullInt32,
+		"database/sql.NullByte":    nullInt32,
+		"database/sql.NullFloat64": nullFloat,
+		"database/sql.NullBool":    nullBool,
+		"database/sql.NullTime":    nullTime,
+	}
+	for _, lib := range nullableLibraries {
+		overrides[lib+".String"] = nullString
+		overrides[lib+".Int"] = nullInt
+		overrides[lib+".Float"] = nullFloat
+		overrides[lib+".Bool"] = nullBool
+		overrides[lib+".Time"] = nullTime
+	}
+	return overrides
+}
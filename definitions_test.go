@@ -0,0 +1,166 @@
+package swago
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/stretchr/testify/assert"
+)
+
+const definitionsCriteria = `
+routes:
+  - funcRoute:
+      pkg: router
+      funcName: POST
+      pathIndex: 0
+      handlerIndex: 1
+request:
+  - pkg: api
+    funcName: Bind
+    modelExtractor:
+      paramIndex: 1
+    validations:
+      required:
+        tag: ['binding:"required"']
+response:
+  - pkg: api
+    funcName: JSON
+    modelExtractor:
+      paramIndex: 2
+    codeIndex: 1
+validations:
+  required:
+    tag: ['validate:"[^"]*required']
+  enum:
+    separator: " "
+    tag: ['validate:"[^"]*oneof=(?P<value>[^,"]+)']
+`
+
+var definitionsProject = map[string]string{
+	"go.mod": "module example.com/api\n",
+	"main.go": `package main
+
+import (
+	"example.com/api/handler"
+	"example.com/api/router"
+)
+
+func main() {
+	router.POST("/users", handler.CreateUser)
+	router.POST("/companies", handler.CreateCompany)
+}
+`,
+	"router/router.go": `package router
+
+import "net/http"
+
+func POST(path string, handler http.HandlerFunc) {}
+`,
+	"handler/handler.go": `package handler
+
+import (
+	"net/http"
+
+	"example.com/api/api"
+)
+
+type Address struct {
+	Country string ` + "`json:\"country\" validate:\"required,oneof=ar uy\"`" + `
+}
+
+type User struct {
+	Name    string  ` + "`json:\"name\"`" + `
+	Address Address ` + "`json:\"address\"`" + `
+	// Billing is where the invoices are sent
+	Billing *Address ` + "`json:\"billing\"`" + `
+}
+
+type Company struct {
+	Offices []Address ` + "`json:\"offices\"`" + `
+}
+
+func CreateUser(w http.ResponseWriter, r *http.Request) {
+	user := User{}
+	api.Bind(r, &user)
+	api.JSON(w, 201, &user)
+}
+
+func CreateCompany(w http.ResponseWriter, r *http.Request) {
+	company := Company{}
+	api.Bind(r, &company)
+	api.JSON(w, 201, &company)
+}
+`,
+	"api/api.go": `package api
+
+import "net/http"
+
+func Bind(r *http.Request, v interface{}) error { return nil }
+
+func JSON(w http.ResponseWriter, code int, v interface{}) {}
+`,
+}
+
+func TestNestedDefinitions(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	root := writeTestProject(t, definitionsProject)
+	defer os.RemoveAll(root)
+	c := criteria.Criteria{}
+	if err := criteria.NewCriteriaDecoder(logger).ParseCriteriaFromYAML(strings.NewReader(definitionsCriteria), &c); err != nil {
+		t.Fatal(err)
+	}
+	sg, err := NewSwaggerGeneratorWithBlacklist(root, root, nil, logger, []*regexp.Regexp{regexp.MustCompile(`_test\.go$`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := openapi2.Swagger{}
+	if err := sg.GenerateSwaggerDoc(c, &doc); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("should document a nested struct once in the definitions", func(t *testing.T) {
+		address := doc.Definitions["Address"]
+		if assert.NotNil(t, address) && assert.NotNil(t, address.Value) {
+			assert.Equal(t, []string{"country"}, address.Value.Required)
+			assert.Equal(t, []interface{}{"ar", "uy"}, address.Value.Properties["country"].Value.Enum)
+		}
+	})
+	users := doc.Paths["/users"].Post
+	companies := doc.Paths["/companies"].Post
+	if !assert.NotNil(t, users) || !assert.NotNil(t, companies) {
+		return
+	}
+	t.Run("should reference the definition of a nested struct", func(t *testing.T) {
+		user := users.Parameters[0].Schema.Value
+		assert.Equal(t, "#/definitions/Address", user.Properties["address"].Ref)
+		assert.Nil(t, user.Properties["address"].Value)
+		offices := companies.Responses["201"].Schema.Value.Properties["offices"].Value
+		assert.Equal(t, "array", offices.Type)
+		assert.Equal(t, "#/definitions/Address", offices.Items.Ref)
+	})
+	t.Run("should describe a field beside the reference to its struct", func(t *testing.T) {
+		billing := users.Responses["201"].Schema.Value.Properties["billing"]
+		assert.Empty(t, billing.Ref)
+		if assert.NotNil(t, billing.Value) {
+			assert.Equal(t, "Is where the invoices are sent", billing.Value.Description)
+			assert.True(t, billing.Value.Nullable)
+			if assert.Len(t, billing.Value.AllOf, 1) {
+				assert.Equal(t, "#/definitions/Address", billing.Value.AllOf[0].Ref)
+			}
+		}
+	})
+	t.Run("should report a call criteria validation that conflicts with a top level validation", func(t *testing.T) {
+		messages := make([]string, 0)
+		for _, d := range sg.Diagnostics.Diagnostics() {
+			if d.Criteria == "config request api.Bind" {
+				messages = append(messages, d.Message)
+			}
+		}
+		assert.Equal(t, []string{"validation required is ignored, a different validation required was declared before"}, messages)
+	})
+}
@@ -0,0 +1,218 @@
+// Package diagnostic collects the problems found while documenting a project
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Severity is how a problem affects the documentation
+type Severity int
+
+const (
+	// Warning is a problem that leaves a route partially documented
+	Warning Severity = iota
+	// Error is a problem that leaves a route out of the documentation
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// MarshalText encodes a severity as its name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is a problem found at a position of the project
+type Diagnostic struct {
+	Position token.Position
+	Severity Severity
+	// Route is the method and the path of the route affected as in POST /auth
+	Route string
+	// Criteria is the criteria that was evaluated as in funcRoute echo.POST
+	Criteria string
+	Message  string
+}
+
+// String formats a diagnostic as file:line:col: severity: message
+func (d Diagnostic) String() string {
+	msg := d.Severity.String() + ": " + d.Message
+	refs := make([]string, 0, 2)
+	if len(d.Route) > 0 {
+		refs = append(refs, "route "+d.Route)
+	}
+	if len(d.Criteria) > 0 {
+		refs = append(refs, d.Criteria)
+	}
+	if len(refs) > 0 {
+		msg += " (" + strings.Join(refs, ", ") + ")"
+	}
+	if !d.Position.IsValid() {
+		return msg
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", d.Position.Filename, d.Position.Line, d.Position.Column, msg)
+}
+
+// List collects diagnostics, the same diagnostic is collected once
+type List struct {
+	diagnostics []Diagnostic
+	seen        map[Diagnostic]bool
+}
+
+// Add collects a diagnostic, adding to a nil list does nothing
+func (l *List) Add(d Diagnostic) {
+	if l == nil || l.seen[d] {
+		return
+	}
+	if l.seen == nil {
+		l.seen = make(map[Diagnostic]bool)
+	}
+	l.seen[d] = true
+	l.diagnostics = append(l.diagnostics, d)
+}
+
+// Warnf collects a warning
+func (l *List) Warnf(pos token.Position, format string, args ...interface{}) {
+	l.Add(Diagnostic{Position: pos, Severity: Warning, Message: fmt.Sprintf(format, args...)})
+}
+
+// Len returns the number of diagnostics collected
+func (l *List) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.diagnostics)
+}
+
+// SetRoute sets the route and the criteria of the diagnostics collected since a length
+// that do not reference a route
+func (l *List) SetRoute(since int, route, criteria string) {
+	if l == nil || since >= len(l.diagnostics) {
+		return
+	}
+	added := l.diagnostics[since:]
+	l.diagnostics = l.diagnostics[:since:since]
+	for _, d := range added {
+		if len(d.Route) == 0 {
+			delete(l.seen, d)
+			d.Route = route
+			d.Criteria = criteria
+		}
+		if !l.seen[d] {
+			l.seen[d] = true
+			l.diagnostics = append(l.diagnostics, d)
+		}
+	}
+}
+
+// Since returns the diagnostics collected since a length in the order they were collected
+func (l *List) Since(since int) []Diagnostic {
+	if l == nil || since >= len(l.diagnostics) {
+		return nil
+	}
+	return append([]Diagnostic{}, l.diagnostics[since:]...)
+}
+
+// Reset removes every diagnostic collected
+func (l *List) Reset() {
+	if l == nil {
+		return
+	}
+	l.diagnostics = nil
+	l.seen = nil
+}
+
+// Diagnostics returns the diagnostics sorted by position
+func (l *List) Diagnostics() []Diagnostic {
+	if l == nil {
+		return nil
+	}
+	sorted := make([]Diagnostic, len(l.diagnostics))
+	copy(sorted, l.diagnostics)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Position, sorted[j].Position
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return sorted
+}
+
+// Count returns the number of diagnostics of a severity
+func (l *List) Count(severity Severity) int {
+	count := 0
+	if l == nil {
+		return count
+	}
+	for _, d := range l.diagnostics {
+		if d.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Relative makes the file names of diagnostics relative to a directory
+func Relative(diagnostics []Diagnostic, dir string) []Diagnostic {
+	relative := make([]Diagnostic, len(diagnostics))
+	for i, d := range diagnostics {
+		if rel, err := filepath.Rel(dir, d.Position.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			d.Position.Filename = rel
+		}
+		relative[i] = d
+	}
+	return relative
+}
+
+// WriteText writes a diagnostic per line as file:line:col: severity: message
+func WriteText(w io.Writer, diagnostics []Diagnostic) error {
+	for _, d := range diagnostics {
+		if _, err := fmt.Fprintln(w, d.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonDiagnostic struct {
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Severity Severity `json:"severity"`
+	Route    string   `json:"route,omitempty"`
+	Criteria string   `json:"criteria,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// WriteJSON writes the diagnostics as a json array
+func WriteJSON(w io.Writer, diagnostics []Diagnostic) error {
+	jsonDiagnostics := make([]jsonDiagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		jsonDiagnostics = append(jsonDiagnostics, jsonDiagnostic{
+			File:     d.Position.Filename,
+			Line:     d.Position.Line,
+			Column:   d.Position.Column,
+			Severity: d.Severity,
+			Route:    d.Route,
+			Criteria: d.Criteria,
+			Message:  d.Message,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonDiagnostics)
+}
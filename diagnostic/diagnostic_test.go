@@ -0,0 +1,72 @@
+package diagnostic
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnosticString(t *testing.T) {
+	type params struct {
+		diagnostic Diagnostic
+	}
+	type expected struct {
+		str string
+	}
+	pos := token.Position{Filename: "handler/user.go", Line: 12, Column: 2}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "warning with a position",
+			params:   params{diagnostic: Diagnostic{Position: pos, Message: "handler not found"}},
+			expected: expected{str: "handler/user.go:12:2: warning: handler not found"},
+		},
+		{
+			name: "error with a route and a criteria",
+			params: params{diagnostic: Diagnostic{
+				Position: pos,
+				Severity: Error,
+				Route:    "POST /auth",
+				Criteria: "funcRoute echo.POST",
+				Message:  "unknown http method",
+			}},
+			expected: expected{str: "handler/user.go:12:2: error: unknown http method (route POST /auth, funcRoute echo.POST)"},
+		},
+		{
+			name:     "without a position",
+			params:   params{diagnostic: Diagnostic{Route: "GET /users", Message: "model not found"}},
+			expected: expected{str: "warning: model not found (route GET /users)"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.str, test.params.diagnostic.String())
+		})
+	}
+}
+
+func TestListSetRoute(t *testing.T) {
+	l := &List{}
+	pos := token.Position{Filename: "handler/user.go", Line: 12, Column: 2}
+	l.Warnf(pos, "type %s not found", "handler.Unknown")
+	l.SetRoute(0, "GET /users", "funcRoute echo.GET")
+	// the same problem found documenting another route
+	since := l.Len()
+	l.Warnf(pos, "type %s not found", "handler.Unknown")
+	l.SetRoute(since, "POST /users", "funcRoute echo.POST")
+	// the same problem found documenting the same route again
+	since = l.Len()
+	l.Warnf(pos, "type %s not found", "handler.Unknown")
+	l.SetRoute(since, "POST /users", "funcRoute echo.POST")
+	routes := make([]string, 0)
+	for _, d := range l.Diagnostics() {
+		routes = append(routes, d.Route)
+	}
+	assert.Equal(t, []string{"GET /users", "POST /users"}, routes)
+	assert.Equal(t, 2, l.Count(Warning))
+	assert.Equal(t, 0, l.Count(Error))
+}
@@ -0,0 +1,70 @@
+package swago
+
+import (
+	"fmt"
+	"go/token"
+
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"github.com/javiercbk/swago/pkg"
+)
+
+// report collects a diagnostic about a route and traces it, the criteria defaults to
+// the route criteria
+func (s *SwaggerGenerator) report(severity diagnostic.Severity, r *pkg.Route, criteriaName string, pos token.Position, format string, args ...interface{}) {
+	d := diagnostic.Diagnostic{
+		Position: pos,
+		Severity: severity,
+		Criteria: criteriaName,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if r != nil {
+		d.Route = r.Endpoint()
+		if len(d.Criteria) == 0 {
+			d.Criteria = r.Criteria
+		}
+	}
+	s.Diagnostics.Add(d)
+	s.trace(r, pos, "%s", d.Message)
+}
+
+// reportValidationConflicts reports the validations of the call criteria that are ignored
+// because a different validation of the same name was declared before
+func (s *SwaggerGenerator) reportValidationConflicts(projectCriterias criteria.Criteria) {
+	for _, c := range projectCriterias.ValidationConflicts {
+		s.report(diagnostic.Warning, nil, configCriteria+" "+c.CallCriteria, token.Position{}, "validation %s is ignored, a different validation %s was declared before", c.Name, c.Name)
+	}
+}
+
+// reportUninferredRequest reports a request call whose variable type can not be inferred
+func (s *SwaggerGenerator) reportUninferredRequest(r *pkg.Route, handler pkg.Function, callCriteria criteria.CallCriteria) {
+	for _, call := range handler.TraceCalls(callCriteria) {
+		if !call.Used {
+			continue
+		}
+		if len(call.VariableType) == 0 {
+			s.report(diagnostic.Warning, r, criteriaName(requestTrace, callCriteria), call.Position, "the type of %s can not be inferred, only arguments and variables assigned a composite literal are", call.Variable)
+		}
+		return
+	}
+}
+
+// reportSchemaDiagnostics sets the route of the diagnostics collected while documenting its
+// models and traces them, as they are steps of the route too
+func (s *SwaggerGenerator) reportSchemaDiagnostics(since int, r pkg.Route) {
+	schemaDiagnostics := make([]diagnostic.Diagnostic, 0)
+	for _, d := range s.Diagnostics.Since(since) {
+		if len(d.Route) == 0 {
+			schemaDiagnostics = append(schemaDiagnostics, d)
+		}
+	}
+	s.Diagnostics.SetRoute(since, r.Endpoint(), r.Criteria)
+	for _, d := range schemaDiagnostics {
+		s.trace(&r, d.Position, "%s", d.Message)
+	}
+}
+
+// criteriaName describes a call criteria as in request c.Bind
+func criteriaName(kind string, callCriteria criteria.CallCriteria) string {
+	return kind + " " + callCriteria.Pkg + "." + callCriteria.FuncName
+}
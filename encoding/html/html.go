@@ -0,0 +1,238 @@
+// Package html renders a swagger document as a self-contained html page
+package html
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/encoding/swagger"
+)
+
+type page struct {
+	Title       string
+	Version     string
+	Description string
+	BaseURL     string
+	Tags        []tagView
+	Definitions []definitionView
+}
+
+type tagView struct {
+	Name        string
+	Anchor      string
+	Description string
+	Endpoints   []endpointView
+}
+
+type endpointView struct {
+	Anchor      string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	OperationID string
+	Deprecated  bool
+	Security    string
+	Parameters  []parameterView
+	Body        *bodyView
+	Responses   []responseView
+}
+
+type parameterView struct {
+	Name        string
+	In          string
+	Type        template.HTML
+	Required    bool
+	Description string
+	Constraints string
+}
+
+type bodyView struct {
+	Type        template.HTML
+	Required    bool
+	Description string
+	Example     string
+}
+
+type responseView struct {
+	Code        string
+	Description string
+	Type        template.HTML
+	Headers     string
+	Example     string
+}
+
+type definitionView struct {
+	Name        string
+	Anchor      string
+	Description string
+	Type        template.HTML
+	Constraints string
+	Properties  []propertyView
+	Example     string
+}
+
+type propertyView struct {
+	Name        string
+	Type        template.HTML
+	Required    bool
+	Description string
+	Constraints string
+}
+
+// Marshal writes the documentation of a swagger document as an html page without external
+// resources: a table of contents by tag, the operations with their parameters, responses and
+// examples, and the definitions
+func Marshal(doc openapi2.Swagger, extensions swagger.Extensions, w io.Writer) error {
+	p := page{
+		Title:       doc.Info.Title,
+		Version:     doc.Info.Version,
+		Description: doc.Info.Description,
+		BaseURL:     doc.Host + doc.BasePath,
+	}
+	if len(p.Title) == 0 {
+		p.Title = "API documentation"
+	}
+	for _, group := range swagger.TagGroups(doc, extensions) {
+		tag := tagView{Name: group.Name, Anchor: swagger.TagAnchor(group.Name), Description: group.Description}
+		for _, e := range group.Endpoints {
+			tag.Endpoints = append(tag.Endpoints, newEndpointView(doc, group.Name, e))
+		}
+		p.Tags = append(p.Tags, tag)
+	}
+	names := make([]string, 0, len(doc.Definitions))
+	for name := range doc.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p.Definitions = append(p.Definitions, newDefinitionView(doc, name))
+	}
+	return pageTemplate.Execute(w, p)
+}
+
+func newEndpointView(doc openapi2.Swagger, tag string, e swagger.Endpoint) endpointView {
+	op := e.Operation
+	view := endpointView{
+		Anchor:      swagger.EndpointAnchor(tag, e),
+		Method:      e.Method,
+		Path:        e.Path,
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: op.OperationID,
+		Deprecated:  e.Deprecated,
+	}
+	security := doc.Security
+	if op.Security != nil {
+		security = *op.Security
+	}
+	view.Security = swagger.SecurityText(security, func(name string) string { return name })
+	for _, param := range e.Parameters {
+		param = swagger.ResolveParameter(param, doc)
+		if param.In == "body" {
+			view.Body = &bodyView{
+				Type:        typeHTML(swagger.TypeName(param.Schema, definitionMarker)),
+				Required:    param.Required,
+				Description: param.Description,
+				Example:     example(swagger.Example(param.Schema, doc.Definitions)),
+			}
+			continue
+		}
+		view.Parameters = append(view.Parameters, parameterView{
+			Name:        param.Name,
+			In:          param.In,
+			Type:        typeHTML(swagger.ParameterTypeName(param, definitionMarker)),
+			Required:    param.Required,
+			Description: param.Description,
+			Constraints: strings.Join(swagger.ParameterConstraints(param), ", "),
+		})
+	}
+	for _, code := range swagger.SortedResponseCodes(op.Responses) {
+		r := swagger.ResolveResponse(op.Responses[code], doc)
+		headers := make([]string, 0, len(r.Headers))
+		for name, header := range r.Headers {
+			headers = append(headers, name+" "+header.Type)
+		}
+		sort.Strings(headers)
+		response := responseView{
+			Code:        code,
+			Description: r.Description,
+			Type:        typeHTML(swagger.TypeName(r.Schema, definitionMarker)),
+			Headers:     strings.Join(headers, ", "),
+		}
+		if r.Schema != nil {
+			value := swagger.Example(r.Schema, doc.Definitions)
+			if jsonExample, ok := r.Examples["application/json"]; ok {
+				value = jsonExample
+			}
+			response.Example = example(value)
+		}
+		view.Responses = append(view.Responses, response)
+	}
+	return view
+}
+
+func newDefinitionView(doc openapi2.Swagger, name string) definitionView {
+	def := doc.Definitions[name]
+	view := definitionView{Name: name, Anchor: swagger.DefinitionAnchor(name)}
+	if def == nil || def.Value == nil {
+		return view
+	}
+	view.Description = def.Value.Description
+	view.Type = typeHTML(swagger.TypeName(def, definitionMarker))
+	view.Constraints = strings.Join(swagger.Constraints(def.Value), ", ")
+	for _, p := range swagger.Properties(def.Value) {
+		property := propertyView{
+			Name:     p.Name,
+			Type:     typeHTML(swagger.TypeName(p.Schema, definitionMarker)),
+			Required: p.Required,
+		}
+		if p.Schema != nil && p.Schema.Value != nil {
+			property.Description = p.Schema.Value.Description
+			property.Constraints = strings.Join(swagger.Constraints(p.Schema.Value), ", ")
+		}
+		view.Properties = append(view.Properties, property)
+	}
+	if len(view.Properties) > 0 {
+		view.Example = example(swagger.Example(def, doc.Definitions))
+	}
+	return view
+}
+
+func example(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// definitionMarker delimits the definitions of a type name to link them once it is escaped
+func definitionMarker(name string) string {
+	return "\x00" + name + "\x00"
+}
+
+// typeHTML escapes a type name and links its definitions
+func typeHTML(typeName string) template.HTML {
+	parts := strings.Split(typeName, "\x00")
+	buf := &strings.Builder{}
+	for i, part := range parts {
+		if i%2 == 0 {
+			buf.WriteString(template.HTMLEscapeString(part))
+			continue
+		}
+		buf.WriteString(`<a href="#` + swagger.DefinitionAnchor(part) + `">` + template.HTMLEscapeString(part) + `</a>`)
+	}
+	return template.HTML(buf.String())
+}
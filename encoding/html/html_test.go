@@ -0,0 +1,49 @@
+package html
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/encoding/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	doc := openapi2.Swagger{
+		Info: openapi3.Info{Title: "Users <v1>"},
+		Paths: map[string]*openapi2.PathItem{
+			"/users": {
+				Get: &openapi2.Operation{
+					Tags: []string{"users"},
+					Responses: map[string]*openapi2.Response{
+						"200": {Description: "OK", Schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "array", Items: openapi3.NewSchemaRef("#/definitions/User", nil)})},
+					},
+				},
+			},
+		},
+		Definitions: map[string]*openapi3.SchemaRef{
+			"User": openapi3.NewSchemaRef("", &openapi3.Schema{
+				Type: "object",
+				Properties: map[string]*openapi3.SchemaRef{
+					"name": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string", Pattern: "^<.*>$"}),
+				},
+			}),
+		},
+	}
+	buf := &bytes.Buffer{}
+	err := Marshal(doc, swagger.Extensions{}, buf)
+	assert.Nil(t, err)
+	out := buf.String()
+	expected := []string{
+		"<title>Users &lt;v1&gt;</title>",
+		`<a href="#users-get-users"><span class="method GET">GET</span>/users</a>`,
+		`<td><a href="#definition-user">User</a>[]</td>`,
+		`<section id="definition-user">`,
+		"<td>pattern: ^&lt;.*&gt;$</td>",
+	}
+	for _, e := range expected {
+		assert.Contains(t, out, e)
+	}
+}
@@ -0,0 +1,144 @@
+package html
+
+import "html/template"
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; font: 14px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #222; }
+nav { position: fixed; top: 0; bottom: 0; left: 0; width: 280px; overflow-y: auto; background: #f6f7f9; border-right: 1px solid #ddd; padding: 16px; box-sizing: border-box; }
+nav h3 { margin: 16px 0 4px; font-size: 13px; text-transform: uppercase; color: #555; }
+nav a { display: block; color: #222; text-decoration: none; padding: 2px 0; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+main { margin-left: 280px; padding: 16px 32px; max-width: 1100px; }
+section { border: 1px solid #ddd; border-radius: 4px; margin: 16px 0; padding: 8px 16px; }
+section.deprecated .path { text-decoration: line-through; }
+.text { white-space: pre-line; }
+.method { display: inline-block; min-width: 60px; text-align: center; border-radius: 3px; color: #fff; font-weight: bold; font-size: 12px; padding: 2px 6px; margin-right: 8px; }
+.GET { background: #61affe; } .POST { background: #49cc90; } .PUT { background: #fca130; } .PATCH { background: #50e3c2; } .DELETE { background: #f93e3e; } .HEAD, .OPTIONS { background: #9012fe; }
+.path { font-family: monospace; font-size: 15px; }
+.badge { background: #f93e3e; color: #fff; border-radius: 3px; font-size: 12px; padding: 2px 6px; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0; }
+th, td { border-bottom: 1px solid #eee; text-align: left; padding: 4px 8px; vertical-align: top; }
+th { font-size: 12px; color: #555; }
+code, pre { font-family: monospace; background: #f6f7f9; }
+pre { padding: 8px; overflow-x: auto; }
+.muted { color: #777; }
+@media print { nav { display: none; } main { margin: 0; } }
+</style>
+</head>
+<body>
+<nav>
+<strong>{{.Title}}</strong>
+{{- range .Tags}}
+<h3><a href="#{{.Anchor}}">{{.Name}}</a></h3>
+{{- range .Endpoints}}
+<a href="#{{.Anchor}}"><span class="method {{.Method}}">{{.Method}}</span>{{.Path}}</a>
+{{- end}}
+{{- end}}
+{{- if .Definitions}}
+<h3><a href="#definitions">Definitions</a></h3>
+{{- range .Definitions}}
+<a href="#{{.Anchor}}">{{.Name}}</a>
+{{- end}}
+{{- end}}
+</nav>
+<main>
+<h1>{{.Title}}{{if .Version}} <small class="muted">{{.Version}}</small>{{end}}</h1>
+{{- if .Description}}
+<p class="text">{{.Description}}</p>
+{{- end}}
+{{- if .BaseURL}}
+<p>Base URL: <code>{{.BaseURL}}</code></p>
+{{- end}}
+{{- range .Tags}}
+<h2 id="{{.Anchor}}">{{.Name}}</h2>
+{{- if .Description}}
+<p class="text">{{.Description}}</p>
+{{- end}}
+{{- range .Endpoints}}
+<section id="{{.Anchor}}"{{if .Deprecated}} class="deprecated"{{end}}>
+<h3><span class="method {{.Method}}">{{.Method}}</span><span class="path">{{.Path}}</span>{{if .Deprecated}} <span class="badge">deprecated</span>{{end}}</h3>
+{{- if .Summary}}
+<p><strong>{{.Summary}}</strong></p>
+{{- end}}
+{{- if .Description}}
+<p class="text">{{.Description}}</p>
+{{- end}}
+{{- if .OperationID}}
+<p>Operation ID: <code>{{.OperationID}}</code></p>
+{{- end}}
+{{- if .Security}}
+<p>Security: {{.Security}}</p>
+{{- end}}
+{{- if .Parameters}}
+<h4>Parameters</h4>
+<table>
+<tr><th>Name</th><th>In</th><th>Type</th><th>Required</th><th>Description</th><th>Constraints</th></tr>
+{{- range .Parameters}}
+<tr><td><code>{{.Name}}</code></td><td>{{.In}}</td><td>{{.Type}}</td><td>{{if .Required}}yes{{else}}no{{end}}</td><td class="text">{{.Description}}</td><td>{{.Constraints}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with .Body}}
+<h4>Request body</h4>
+<p>Type: {{.Type}}{{if .Required}}, required{{end}}</p>
+{{- if .Description}}
+<p class="text">{{.Description}}</p>
+{{- end}}
+{{- if .Example}}
+<pre>{{.Example}}</pre>
+{{- end}}
+{{- end}}
+{{- if .Responses}}
+<h4>Responses</h4>
+<table>
+<tr><th>Code</th><th>Description</th><th>Type</th><th>Headers</th></tr>
+{{- range .Responses}}
+<tr><td>{{.Code}}</td><td class="text">{{.Description}}</td><td>{{.Type}}</td><td>{{.Headers}}</td></tr>
+{{- end}}
+</table>
+{{- range .Responses}}
+{{- if .Example}}
+<p>Example <code>{{.Code}}</code> response:</p>
+<pre>{{.Example}}</pre>
+{{- end}}
+{{- end}}
+{{- end}}
+</section>
+{{- end}}
+{{- end}}
+{{- if .Definitions}}
+<h2 id="definitions">Definitions</h2>
+{{- range .Definitions}}
+<section id="{{.Anchor}}">
+<h3>{{.Name}}</h3>
+{{- if .Description}}
+<p class="text">{{.Description}}</p>
+{{- end}}
+{{- if .Properties}}
+<table>
+<tr><th>Property</th><th>Type</th><th>Required</th><th>Description</th><th>Constraints</th></tr>
+{{- range .Properties}}
+<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{if .Required}}yes{{else}}no{{end}}</td><td class="text">{{.Description}}</td><td>{{.Constraints}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>Type: {{.Type}}</p>
+{{- if .Constraints}}
+<p>Constraints: {{.Constraints}}</p>
+{{- end}}
+{{- end}}
+{{- if .Example}}
+<pre>{{.Example}}</pre>
+{{- end}}
+</section>
+{{- end}}
+{{- end}}
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,265 @@
+// Package markdown renders a swagger document as a self-contained markdown documentation
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/encoding/swagger"
+)
+
+type writer struct {
+	w       io.Writer
+	err     error
+	swagger openapi2.Swagger
+}
+
+func (mw *writer) printf(format string, args ...interface{}) {
+	if mw.err == nil {
+		_, mw.err = fmt.Fprintf(mw.w, format, args...)
+	}
+}
+
+// Marshal writes the documentation of a swagger document: a table of contents by tag, the
+// operations with their parameters, responses and examples, and the definitions
+func Marshal(doc openapi2.Swagger, extensions swagger.Extensions, w io.Writer) error {
+	mw := &writer{w: w, swagger: doc}
+	groups := swagger.TagGroups(doc, extensions)
+	title := doc.Info.Title
+	if len(title) == 0 {
+		title = "API documentation"
+	}
+	mw.printf("# %s\n\n", title)
+	if len(doc.Info.Version) > 0 {
+		mw.printf("Version: `%s`\n\n", doc.Info.Version)
+	}
+	if len(doc.Info.Description) > 0 {
+		mw.printf("%s\n\n", doc.Info.Description)
+	}
+	if len(doc.Host) > 0 || len(doc.BasePath) > 0 {
+		mw.printf("Base URL: `%s%s`\n\n", doc.Host, doc.BasePath)
+	}
+	mw.printf("## Table of contents\n\n")
+	for _, group := range groups {
+		mw.printf("- [%s](#%s)\n", escapeText(group.Name), swagger.TagAnchor(group.Name))
+		for _, e := range group.Endpoints {
+			mw.printf("  - [`%s %s`](#%s)%s\n", e.Method, e.Path, swagger.EndpointAnchor(group.Name, e), summarySuffix(e.Operation.Summary))
+		}
+	}
+	if len(doc.Definitions) > 0 {
+		mw.printf("- [Definitions](#definitions)\n")
+	}
+	mw.printf("\n")
+	for _, group := range groups {
+		mw.printf("<a id=\"%s\"></a>\n\n## %s\n\n", swagger.TagAnchor(group.Name), escapeText(group.Name))
+		if len(group.Description) > 0 {
+			mw.printf("%s\n\n", group.Description)
+		}
+		for _, e := range group.Endpoints {
+			mw.marshalEndpoint(group.Name, e)
+		}
+	}
+	mw.marshalDefinitions()
+	return mw.err
+}
+
+func (mw *writer) marshalEndpoint(tag string, e swagger.Endpoint) {
+	op := e.Operation
+	mw.printf("<a id=\"%s\"></a>\n\n### `%s %s`\n\n", swagger.EndpointAnchor(tag, e), e.Method, e.Path)
+	if e.Deprecated {
+		mw.printf("> **Deprecated**\n\n")
+	}
+	if len(op.Summary) > 0 {
+		mw.printf("**%s**\n\n", escapeText(op.Summary))
+	}
+	if len(op.Description) > 0 {
+		mw.printf("%s\n\n", op.Description)
+	}
+	if len(op.OperationID) > 0 {
+		mw.printf("Operation ID: `%s`\n\n", op.OperationID)
+	}
+	security := mw.swagger.Security
+	if op.Security != nil {
+		security = *op.Security
+	}
+	if len(security) > 0 {
+		mw.printf("Security: %s\n\n", swagger.SecurityText(security, codeSpan))
+	}
+	var body *openapi2.Parameter
+	rows := make([][]string, 0)
+	for _, p := range e.Parameters {
+		p = swagger.ResolveParameter(p, mw.swagger)
+		if p.In == "body" {
+			body = p
+			continue
+		}
+		rows = append(rows, []string{"`" + p.Name + "`", p.In, swagger.ParameterTypeName(p, definitionLink), yesNo(p.Required), p.Description, codeList(swagger.ParameterConstraints(p))})
+	}
+	if len(rows) > 0 {
+		mw.printf("#### Parameters\n\n")
+		mw.table([]string{"Name", "In", "Type", "Required", "Description", "Constraints"}, rows)
+	}
+	if body != nil {
+		mw.printf("#### Request body\n\n")
+		mw.printf("Type: %s%s\n\n", swagger.TypeName(body.Schema, definitionLink), requiredSuffix(body.Required))
+		if len(body.Description) > 0 {
+			mw.printf("%s\n\n", body.Description)
+		}
+		mw.example(swagger.Example(body.Schema, mw.swagger.Definitions))
+	}
+	mw.marshalResponses(op.Responses)
+}
+
+func (mw *writer) marshalResponses(responses map[string]*openapi2.Response) {
+	if len(responses) == 0 {
+		return
+	}
+	mw.printf("#### Responses\n\n")
+	rows := make([][]string, 0, len(responses))
+	codes := swagger.SortedResponseCodes(responses)
+	for _, code := range codes {
+		r := swagger.ResolveResponse(responses[code], mw.swagger)
+		headers := make([]string, 0, len(r.Headers))
+		for name, header := range r.Headers {
+			headers = append(headers, "`"+name+"` "+header.Type)
+		}
+		sort.Strings(headers)
+		rows = append(rows, []string{code, r.Description, swagger.TypeName(r.Schema, definitionLink), strings.Join(headers, ", ")})
+	}
+	mw.table([]string{"Code", "Description", "Type", "Headers"}, rows)
+	for _, code := range codes {
+		r := swagger.ResolveResponse(responses[code], mw.swagger)
+		if r.Schema == nil {
+			continue
+		}
+		example := swagger.Example(r.Schema, mw.swagger.Definitions)
+		if jsonExample, ok := r.Examples["application/json"]; ok {
+			example = jsonExample
+		}
+		mw.printf("Example `%s` response:\n\n", code)
+		mw.example(example)
+	}
+}
+
+func (mw *writer) marshalDefinitions() {
+	if len(mw.swagger.Definitions) == 0 {
+		return
+	}
+	mw.printf("<a id=\"definitions\"></a>\n\n## Definitions\n\n")
+	names := make([]string, 0, len(mw.swagger.Definitions))
+	for name := range mw.swagger.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := mw.swagger.Definitions[name]
+		mw.printf("<a id=\"%s\"></a>\n\n### %s\n\n", swagger.DefinitionAnchor(name), escapeText(name))
+		schema := def.Value
+		if schema == nil {
+			continue
+		}
+		if len(schema.Description) > 0 {
+			mw.printf("%s\n\n", schema.Description)
+		}
+		properties := swagger.Properties(schema)
+		if len(properties) == 0 {
+			mw.printf("Type: %s\n\n", swagger.TypeName(def, definitionLink))
+			if constraints := swagger.Constraints(schema); len(constraints) > 0 {
+				mw.printf("Constraints: %s\n\n", codeList(constraints))
+			}
+			continue
+		}
+		rows := make([][]string, 0, len(properties))
+		for _, p := range properties {
+			var description string
+			var constraints []string
+			if p.Schema != nil && p.Schema.Value != nil {
+				description = p.Schema.Value.Description
+				constraints = swagger.Constraints(p.Schema.Value)
+			}
+			rows = append(rows, []string{"`" + p.Name + "`", swagger.TypeName(p.Schema, definitionLink), yesNo(p.Required), description, codeList(constraints)})
+		}
+		mw.table([]string{"Property", "Type", "Required", "Description", "Constraints"}, rows)
+		mw.example(swagger.Example(def, mw.swagger.Definitions))
+	}
+}
+
+func (mw *writer) table(header []string, rows [][]string) {
+	mw.printf("| %s |\n", strings.Join(header, " | "))
+	mw.printf("|%s\n", strings.Repeat("---|", len(header)))
+	for _, row := range rows {
+		cells := make([]string, 0, len(row))
+		for _, cell := range row {
+			cells = append(cells, escapeCell(cell))
+		}
+		mw.printf("| %s |\n", strings.Join(cells, " | "))
+	}
+	mw.printf("\n")
+}
+
+func (mw *writer) example(value interface{}) {
+	if value == nil {
+		return
+	}
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return
+	}
+	mw.printf("```json\n%s```\n\n", buf.String())
+}
+
+// codeList formats values as code spans so patterns are not formatted
+func codeList(values []string) string {
+	codes := make([]string, 0, len(values))
+	for _, v := range values {
+		codes = append(codes, "`"+strings.Replace(v, "`", "'", -1)+"`")
+	}
+	return strings.Join(codes, ", ")
+}
+
+func codeSpan(name string) string {
+	return "`" + name + "`"
+}
+
+func definitionLink(name string) string {
+	return fmt.Sprintf("[%s](#%s)", escapeText(name), swagger.DefinitionAnchor(name))
+}
+
+func summarySuffix(summary string) string {
+	if len(summary) == 0 {
+		return ""
+	}
+	return " " + escapeText(summary)
+}
+
+func requiredSuffix(required bool) string {
+	if required {
+		return ", required"
+	}
+	return ""
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// escapeText escapes the characters that markdown would format in a name
+func escapeText(s string) string {
+	return strings.NewReplacer("*", "\\*", "_", "\\_", "[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// escapeCell keeps a value in its table cell
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>").Replace(s)
+}
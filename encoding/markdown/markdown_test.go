@@ -0,0 +1,58 @@
+package markdown
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/encoding/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	maxLength := uint64(32)
+	doc := openapi2.Swagger{
+		Info: openapi3.Info{Title: "Users", Version: "1.0"},
+		Paths: map[string]*openapi2.PathItem{
+			"/users": {
+				Post: &openapi2.Operation{
+					Summary: "Creates a user",
+					Tags:    []string{"users"},
+					Parameters: openapi2.Parameters{
+						{Name: "dryRun", In: "query", Type: "boolean", Description: "Only validates | checks"},
+						{Name: "body", In: "body", Required: true, Schema: openapi3.NewSchemaRef("#/definitions/User", nil)},
+					},
+					Responses: map[string]*openapi2.Response{
+						"201": {Description: "Created", Schema: openapi3.NewSchemaRef("#/definitions/User", nil)},
+					},
+				},
+			},
+		},
+		Definitions: map[string]*openapi3.SchemaRef{
+			"User": openapi3.NewSchemaRef("", &openapi3.Schema{
+				Type:     "object",
+				Required: []string{"name"},
+				Properties: map[string]*openapi3.SchemaRef{
+					"name": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string", MaxLength: &maxLength, Example: "Jane"}),
+				},
+			}),
+		},
+	}
+	buf := &bytes.Buffer{}
+	err := Marshal(doc, swagger.Extensions{}, buf)
+	assert.Nil(t, err)
+	out := buf.String()
+	expected := []string{
+		"# Users\n",
+		"- [users](#tag-users)\n  - [`POST /users`](#users-post-users) Creates a user\n",
+		"| `dryRun` | query | boolean | no | Only validates \\| checks |  |\n",
+		"Type: [User](#definition-user), required\n",
+		"| 201 | Created | [User](#definition-user) |  |\n",
+		"| `name` | string | yes |  | `maxLength: 32` |\n",
+		"```json\n{\n  \"name\": \"Jane\"\n}\n```\n",
+	}
+	for _, e := range expected {
+		assert.Contains(t, out, e)
+	}
+}
@@ -0,0 +1,327 @@
+// Package postman exports a swagger document as a Postman collection v2.1, which Insomnia
+// imports too
+package postman
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/encoding/swagger"
+)
+
+const (
+	// SchemaURL is the schema of the collections
+	SchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+	// BaseURLVariable is the collection variable every request url starts with
+	BaseURLVariable = "baseUrl"
+)
+
+var pathVarRegExp = regexp.MustCompile(`{([^}]+)}`)
+
+// flowGrantTypes are the postman grant types of the swagger oauth2 flows
+var flowGrantTypes = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password_credentials",
+	"application": "client_credentials",
+	"accessCode":  "authorization_code",
+}
+
+// Collection is a Postman collection
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Auth     Auth       `json:"auth,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+// Info describes a collection
+type Info struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// Item is a folder when it has items or a request
+type Item struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Item        []Item   `json:"item,omitempty"`
+	Request     *Request `json:"request,omitempty"`
+}
+
+// Request is the request of an item
+type Request struct {
+	Method      string     `json:"method"`
+	Description string     `json:"description,omitempty"`
+	Header      []KeyValue `json:"header"`
+	URL         URL        `json:"url"`
+	Body        *Body      `json:"body,omitempty"`
+	Auth        Auth       `json:"auth,omitempty"`
+}
+
+// URL is the url of a request
+type URL struct {
+	Raw      string     `json:"raw"`
+	Host     []string   `json:"host"`
+	Path     []string   `json:"path"`
+	Query    []KeyValue `json:"query,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+// KeyValue is a header, a query param or a form field
+type KeyValue struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// Variable is a collection or a path variable
+type Variable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// Body is the body of a request
+type Body struct {
+	Mode       string      `json:"mode"`
+	Raw        string      `json:"raw,omitempty"`
+	URLEncoded []KeyValue  `json:"urlencoded,omitempty"`
+	FormData   []KeyValue  `json:"formdata,omitempty"`
+	Options    interface{} `json:"options,omitempty"`
+}
+
+// Auth is the authentication of a collection or a request, the attributes are under the
+// key of its type
+type Auth map[string]interface{}
+
+// Marshal writes the Postman collection of a swagger document
+func Marshal(doc openapi2.Swagger, extensions swagger.Extensions, w io.Writer) error {
+	collection := NewCollection(doc, extensions)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(collection)
+}
+
+// NewCollection converts a swagger document to a Postman collection with a folder per tag
+func NewCollection(doc openapi2.Swagger, extensions swagger.Extensions) Collection {
+	collection := Collection{
+		Info: Info{
+			Name:        doc.Info.Title,
+			Description: doc.Info.Description,
+			Version:     doc.Info.Version,
+			Schema:      SchemaURL,
+		},
+		Item:     make([]Item, 0),
+		Auth:     auth(doc.Security, doc.SecurityDefinitions),
+		Variable: []Variable{{Key: BaseURLVariable, Value: baseURL(doc)}},
+	}
+	if len(collection.Info.Name) == 0 {
+		collection.Info.Name = "API"
+	}
+	collection.Variable = append(collection.Variable, credentialVariables(doc.SecurityDefinitions)...)
+	for _, group := range swagger.TagGroups(doc, extensions) {
+		folder := Item{Name: group.Name, Description: group.Description, Item: make([]Item, 0, len(group.Endpoints))}
+		for _, e := range group.Endpoints {
+			folder.Item = append(folder.Item, newItem(doc, e))
+		}
+		collection.Item = append(collection.Item, folder)
+	}
+	return collection
+}
+
+func newItem(doc openapi2.Swagger, e swagger.Endpoint) Item {
+	op := e.Operation
+	description := strings.TrimSpace(op.Summary + "\n\n" + op.Description)
+	if e.Deprecated && !strings.Contains(description, "Deprecated") {
+		description = strings.TrimSpace("Deprecated.\n\n" + description)
+	}
+	path := strings.Split(strings.Trim(pathVarRegExp.ReplaceAllString(e.Path, ":$1"), "/"), "/")
+	if len(path) == 1 && len(path[0]) == 0 {
+		path = []string{}
+	}
+	request := &Request{
+		Method:      e.Method,
+		Description: description,
+		Header:      make([]KeyValue, 0),
+		URL: URL{
+			Host: []string{"{{" + BaseURLVariable + "}}"},
+			Path: path,
+		},
+	}
+	form := make([]KeyValue, 0)
+	for _, param := range e.Parameters {
+		param = swagger.ResolveParameter(param, doc)
+		switch param.In {
+		case "path":
+			request.URL.Variable = append(request.URL.Variable, Variable{Key: param.Name, Value: paramValue(param), Description: param.Description})
+		case "query":
+			request.URL.Query = append(request.URL.Query, KeyValue{Key: param.Name, Value: paramValue(param), Description: param.Description, Disabled: !param.Required})
+		case "header":
+			request.Header = append(request.Header, KeyValue{Key: param.Name, Value: paramValue(param), Description: param.Description, Disabled: !param.Required})
+		case "formData":
+			fieldType := "text"
+			if param.Type == "file" {
+				fieldType = "file"
+			}
+			form = append(form, KeyValue{Key: param.Name, Value: paramValue(param), Type: fieldType, Description: param.Description, Disabled: !param.Required})
+		case "body":
+			request.Body = jsonBody(swagger.Example(param.Schema, doc.Definitions))
+			request.Header = append(request.Header, KeyValue{Key: "Content-Type", Value: "application/json"})
+		}
+	}
+	if len(form) > 0 {
+		if consumes(op, "multipart/form-data") {
+			request.Body = &Body{Mode: "formdata", FormData: form}
+		} else {
+			request.Body = &Body{Mode: "urlencoded", URLEncoded: form}
+			request.Header = append(request.Header, KeyValue{Key: "Content-Type", Value: "application/x-www-form-urlencoded"})
+		}
+	}
+	request.URL.Raw = rawURL(request.URL)
+	if op.Security != nil {
+		if len(*op.Security) == 0 || len((*op.Security)[0]) == 0 {
+			request.Auth = Auth{"type": "noauth"}
+		} else {
+			request.Auth = auth(*op.Security, doc.SecurityDefinitions)
+		}
+	}
+	return Item{Name: e.Method + " " + e.Path, Request: request}
+}
+
+// auth returns the auth of the first scheme of the first alternative of the security requirements
+func auth(security openapi2.SecurityRequirements, definitions map[string]*openapi2.SecurityScheme) Auth {
+	if len(security) == 0 || len(security[0]) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(security[0]))
+	for name := range security[0] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	scheme, ok := definitions[names[0]]
+	if !ok {
+		return nil
+	}
+	variable := "{{" + names[0] + "}}"
+	switch scheme.Type {
+	case "basic":
+		return Auth{"type": "basic", "basic": []KeyValue{
+			{Key: "username", Value: "{{username}}", Type: "string"},
+			{Key: "password", Value: "{{password}}", Type: "string"},
+		}}
+	case "apiKey":
+		in := "header"
+		if scheme.In == "query" {
+			in = "query"
+		}
+		return Auth{"type": "apikey", "apikey": []KeyValue{
+			{Key: "key", Value: scheme.Name, Type: "string"},
+			{Key: "value", Value: variable, Type: "string"},
+			{Key: "in", Value: in, Type: "string"},
+		}}
+	case "oauth2":
+		return Auth{"type": "oauth2", "oauth2": []KeyValue{
+			{Key: "grant_type", Value: flowGrantTypes[scheme.Flow], Type: "string"},
+			{Key: "authUrl", Value: scheme.AuthorizationURL, Type: "string"},
+			{Key: "accessTokenUrl", Value: scheme.TokenURL, Type: "string"},
+			{Key: "scope", Value: strings.Join(security[0][names[0]], " "), Type: "string"},
+			{Key: "addTokenTo", Value: "header", Type: "string"},
+		}}
+	}
+	return nil
+}
+
+// credentialVariables are the collection variables of the api keys and the basic credentials
+func credentialVariables(definitions map[string]*openapi2.SecurityScheme) []Variable {
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	variables := make([]Variable, 0)
+	basic := false
+	for _, name := range names {
+		switch definitions[name].Type {
+		case "apiKey":
+			variables = append(variables, Variable{Key: name, Description: definitions[name].Description})
+		case "basic":
+			basic = true
+		}
+	}
+	if basic {
+		variables = append(variables, Variable{Key: "username"}, Variable{Key: "password"})
+	}
+	return variables
+}
+
+func baseURL(doc openapi2.Swagger) string {
+	scheme := "http"
+	if len(doc.Schemes) > 0 {
+		scheme = doc.Schemes[0]
+	}
+	host := doc.Host
+	if len(host) == 0 {
+		host = "localhost"
+	}
+	return scheme + "://" + host + strings.TrimSuffix(doc.BasePath, "/")
+}
+
+func rawURL(url URL) string {
+	raw := strings.Join(append(url.Host, url.Path...), "/")
+	query := make([]string, 0, len(url.Query))
+	for _, q := range url.Query {
+		if !q.Disabled {
+			query = append(query, q.Key+"="+q.Value)
+		}
+	}
+	if len(query) > 0 {
+		raw += "?" + strings.Join(query, "&")
+	}
+	return raw
+}
+
+// paramValue returns the default or the first enum of a parameter
+func paramValue(param *openapi2.Parameter) string {
+	if param.Default != nil {
+		return fmt.Sprint(param.Default)
+	}
+	if len(param.Enum) > 0 {
+		return fmt.Sprint(param.Enum[0])
+	}
+	return ""
+}
+
+func jsonBody(example interface{}) *Body {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(example); err != nil {
+		buf.Reset()
+	}
+	return &Body{
+		Mode:    "raw",
+		Raw:     strings.TrimSuffix(buf.String(), "\n"),
+		Options: map[string]interface{}{"raw": map[string]string{"language": "json"}},
+	}
+}
+
+func consumes(op *openapi2.Operation, mediaType string) bool {
+	for _, c := range op.Consumes {
+		if c == mediaType {
+			return true
+		}
+	}
+	return false
+}
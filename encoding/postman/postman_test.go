@@ -0,0 +1,64 @@
+package postman
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/encoding/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCollection(t *testing.T) {
+	public := openapi2.SecurityRequirements{}
+	doc := openapi2.Swagger{
+		Info:     openapi3.Info{Title: "Users", Version: "1.0"},
+		Schemes:  []string{"https"},
+		Host:     "api.example.com",
+		BasePath: "/v1",
+		Security: openapi2.SecurityRequirements{{"jwt": {}}},
+		SecurityDefinitions: map[string]*openapi2.SecurityScheme{
+			"jwt": {Type: "apiKey", In: "header", Name: "Authorization"},
+		},
+		Paths: map[string]*openapi2.PathItem{
+			"/users/{id}": {
+				Parameters: openapi2.Parameters{{Name: "id", In: "path", Required: true, Type: "integer"}},
+				Put: &openapi2.Operation{
+					Summary: "Updates a user",
+					Tags:    []string{"users"},
+					Parameters: openapi2.Parameters{
+						{Name: "notify", In: "query", Type: "boolean", Default: true, Required: true},
+						{Name: "dryRun", In: "query", Type: "boolean"},
+						{Name: "body", In: "body", Schema: openapi3.NewSchemaRef("", &openapi3.Schema{
+							Type:       "object",
+							Properties: map[string]*openapi3.SchemaRef{"name": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string", Example: "Jane"})},
+						})},
+					},
+				},
+			},
+			"/health": {
+				Get: &openapi2.Operation{Security: &public},
+			},
+		},
+	}
+	collection := NewCollection(doc, swagger.Extensions{})
+	assert.Equal(t, "Users", collection.Info.Name)
+	assert.Equal(t, []Variable{{Key: BaseURLVariable, Value: "https://api.example.com/v1"}, {Key: "jwt"}}, collection.Variable)
+	assert.Equal(t, "apikey", collection.Auth["type"])
+	assert.Len(t, collection.Item, 2)
+	health := collection.Item[0]
+	assert.Equal(t, swagger.DefaultTag, health.Name)
+	assert.Equal(t, Auth{"type": "noauth"}, health.Item[0].Request.Auth)
+	users := collection.Item[1]
+	assert.Equal(t, "users", users.Name)
+	request := users.Item[0].Request
+	assert.Equal(t, "PUT /users/{id}", users.Item[0].Name)
+	assert.Equal(t, "Updates a user", request.Description)
+	assert.Nil(t, request.Auth)
+	assert.Equal(t, "{{baseUrl}}/users/:id?notify=true", request.URL.Raw)
+	assert.Equal(t, []string{"users", ":id"}, request.URL.Path)
+	assert.Equal(t, []Variable{{Key: "id"}}, request.URL.Variable)
+	assert.Equal(t, []KeyValue{{Key: "notify", Value: "true"}, {Key: "dryRun", Disabled: true}}, request.URL.Query)
+	assert.Equal(t, "raw", request.Body.Mode)
+	assert.Equal(t, "{\n  \"name\": \"Jane\"\n}", request.Body.Raw)
+}
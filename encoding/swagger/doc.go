@@ -0,0 +1,492 @@
+package swagger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	// DefaultTag groups the operations without tags
+	DefaultTag = "default"
+
+	definitionsRefPrefix = "#/definitions/"
+	parametersRefPrefix  = "#/parameters/"
+	responsesRefPrefix   = "#/responses/"
+	deprecatedExtension  = "x-deprecated"
+)
+
+// methods are the http methods in the order their operations are documented
+var methods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH"}
+
+var anchorRegExp = regexp.MustCompile("[^a-z0-9]+")
+
+// Endpoint is an operation with its path, method and the parameters of its path item
+type Endpoint struct {
+	Path       string
+	Method     string
+	Operation  *openapi2.Operation
+	Parameters openapi2.Parameters
+	Deprecated bool
+}
+
+// TagGroup is a tag and the endpoints of its operations
+type TagGroup struct {
+	Name        string
+	Description string
+	Endpoints   []Endpoint
+}
+
+// Property is a property of a schema, the properties of inline objects are named after
+// their path as in meta.page
+type Property struct {
+	Name     string
+	Schema   *openapi3.SchemaRef
+	Required bool
+}
+
+// TagGroups groups the endpoints of a document by tag, the tags of the document come first
+// and an operation with many tags is in every group
+func TagGroups(swagger openapi2.Swagger, extensions Extensions) []TagGroup {
+	groups := make([]TagGroup, 0)
+	index := make(map[string]int)
+	for _, tag := range swagger.Tags {
+		index[tag.Name] = len(groups)
+		groups = append(groups, TagGroup{Name: tag.Name, Description: tag.Description})
+	}
+	paths := make([]string, 0, len(swagger.Paths))
+	for path := range swagger.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := swagger.Paths[path]
+		for _, method := range methods {
+			operation := pathItem.GetOperation(method)
+			if operation == nil {
+				continue
+			}
+			endpoint := Endpoint{
+				Path:       path,
+				Method:     method,
+				Operation:  operation,
+				Parameters: append(append(openapi2.Parameters{}, pathItem.Parameters...), operation.Parameters...),
+				Deprecated: extensions.IsDeprecated(operation),
+			}
+			tags := operation.Tags
+			if len(tags) == 0 {
+				tags = []string{DefaultTag}
+			}
+			for _, tag := range tags {
+				i, ok := index[tag]
+				if !ok {
+					i = len(groups)
+					index[tag] = i
+					groups = append(groups, TagGroup{Name: tag})
+				}
+				groups[i].Endpoints = append(groups[i].Endpoints, endpoint)
+			}
+		}
+	}
+	tagged := make([]TagGroup, 0, len(groups))
+	for _, group := range groups {
+		if len(group.Endpoints) > 0 {
+			tagged = append(tagged, group)
+		}
+	}
+	return tagged
+}
+
+// RefName returns the name of the definition, parameter or response of a reference
+func RefName(ref string) string {
+	for _, prefix := range []string{definitionsRefPrefix, parametersRefPrefix, responsesRefPrefix} {
+		if strings.HasPrefix(ref, prefix) {
+			return strings.TrimPrefix(ref, prefix)
+		}
+	}
+	return ref
+}
+
+// FieldRef returns the reference of a field schema that wraps the definition of its struct
+// in allOf to describe the field, it is nil for the other schemas
+func FieldRef(schemaRef *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if schemaRef == nil || len(schemaRef.Ref) > 0 || schemaRef.Value == nil {
+		return nil
+	}
+	schema := schemaRef.Value
+	if len(schema.AllOf) != 1 || schema.AllOf[0] == nil || len(schema.AllOf[0].Ref) == 0 || len(schema.Type) > 0 || len(schema.Properties) > 0 {
+		return nil
+	}
+	return schema.AllOf[0]
+}
+
+// ResolveSchema returns the schema of a schema reference, references to definitions are followed
+func ResolveSchema(schemaRef *openapi3.SchemaRef, definitions map[string]*openapi3.SchemaRef) *openapi3.Schema {
+	for i := 0; schemaRef != nil && len(schemaRef.Ref) > 0 && i < 32; i++ {
+		def, ok := definitions[RefName(schemaRef.Ref)]
+		if !ok {
+			return schemaRef.Value
+		}
+		schemaRef = def
+	}
+	if schemaRef == nil {
+		return nil
+	}
+	return schemaRef.Value
+}
+
+// ResolveParameter returns a parameter, references to the parameters of the document are followed
+func ResolveParameter(parameter *openapi2.Parameter, swagger openapi2.Swagger) *openapi2.Parameter {
+	if parameter != nil && len(parameter.Ref) > 0 {
+		if global, ok := swagger.Parameters[RefName(parameter.Ref)]; ok {
+			return global
+		}
+	}
+	return parameter
+}
+
+// ResolveResponse returns a response, references to the responses of the document are followed
+func ResolveResponse(response *openapi2.Response, swagger openapi2.Swagger) *openapi2.Response {
+	if response != nil && len(response.Ref) > 0 {
+		if global, ok := swagger.Responses[RefName(response.Ref)]; ok {
+			return global
+		}
+	}
+	return response
+}
+
+// SortedResponseCodes returns the status codes of the responses sorted with default last
+func SortedResponseCodes(responses map[string]*openapi2.Response) []string {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[i] == "default" || codes[j] == "default" {
+			return codes[j] == "default" && codes[i] != "default"
+		}
+		return codes[i] < codes[j]
+	})
+	return codes
+}
+
+// TypeName returns the name of the type of a schema as in User[] or map[string]integer, link
+// formats the name of a definition
+func TypeName(schemaRef *openapi3.SchemaRef, link func(definition string) string) string {
+	if schemaRef == nil {
+		return ""
+	}
+	if len(schemaRef.Ref) > 0 {
+		return link(RefName(schemaRef.Ref))
+	}
+	if ref := FieldRef(schemaRef); ref != nil {
+		return link(RefName(ref.Ref))
+	}
+	schema := schemaRef.Value
+	if schema == nil {
+		return ""
+	}
+	switch {
+	case schema.Type == "array":
+		return TypeName(schema.Items, link) + "[]"
+	case schema.AdditionalProperties != nil:
+		return "map[string]" + TypeName(schema.AdditionalProperties, link)
+	case len(schema.Type) == 0:
+		return "object"
+	case len(schema.Format) > 0:
+		return schema.Type + "(" + schema.Format + ")"
+	}
+	return schema.Type
+}
+
+// SecurityText describes the security requirements of an operation as in jwt (users:read) or apiKey,
+// scheme formats the name of a security scheme
+func SecurityText(security openapi2.SecurityRequirements, scheme func(name string) string) string {
+	alternatives := make([]string, 0, len(security))
+	for _, requirement := range security {
+		schemes := make([]string, 0, len(requirement))
+		for name, scopes := range requirement {
+			text := scheme(name)
+			if len(scopes) > 0 {
+				text += " (" + strings.Join(scopes, ", ") + ")"
+			}
+			schemes = append(schemes, text)
+		}
+		sort.Strings(schemes)
+		if len(schemes) == 0 {
+			schemes = append(schemes, "none")
+		}
+		alternatives = append(alternatives, strings.Join(schemes, " and "))
+	}
+	return strings.Join(alternatives, " or ")
+}
+
+// TagAnchor returns the id of the section of a tag
+func TagAnchor(tag string) string {
+	return "tag-" + anchor(tag)
+}
+
+// EndpointAnchor returns the id of the section of an endpoint in the section of a tag
+func EndpointAnchor(tag string, e Endpoint) string {
+	return anchor(tag + "-" + e.Method + "-" + e.Path)
+}
+
+// DefinitionAnchor returns the id of the section of a definition
+func DefinitionAnchor(name string) string {
+	return "definition-" + anchor(name)
+}
+
+func anchor(s string) string {
+	return strings.Trim(anchorRegExp.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// ParameterTypeName returns the name of the type of a parameter
+func ParameterTypeName(parameter *openapi2.Parameter, link func(definition string) string) string {
+	if parameter.Schema != nil {
+		return TypeName(parameter.Schema, link)
+	}
+	if parameter.Type == "array" {
+		return TypeName(parameter.Items, link) + "[]"
+	}
+	if len(parameter.Format) > 0 {
+		return parameter.Type + "(" + parameter.Format + ")"
+	}
+	return parameter.Type
+}
+
+// Properties returns the properties of an object schema sorted by name, the properties of
+// inline objects and arrays of inline objects follow the property that holds them
+func Properties(schema *openapi3.Schema) []Property {
+	properties := make([]Property, 0)
+	addProperties(schema, "", &properties)
+	return properties
+}
+
+func addProperties(schema *openapi3.Schema, prefix string, properties *[]Property) {
+	if schema == nil {
+		return
+	}
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property := schema.Properties[name]
+		*properties = append(*properties, Property{Name: prefix + name, Schema: property, Required: required[name]})
+		if property == nil || len(property.Ref) > 0 || property.Value == nil {
+			continue
+		}
+		switch {
+		case len(property.Value.Properties) > 0:
+			addProperties(property.Value, prefix+name+".", properties)
+		case property.Value.Items != nil && len(property.Value.Items.Ref) == 0 && property.Value.Items.Value != nil:
+			addProperties(property.Value.Items.Value, prefix+name+"[].", properties)
+		}
+	}
+}
+
+// Constraints returns the validations, defaults and flags of a schema as in minLength: 3
+func Constraints(schema *openapi3.Schema) []string {
+	constraints := make([]string, 0)
+	if schema == nil {
+		return constraints
+	}
+	if len(schema.Enum) > 0 {
+		constraints = append(constraints, "enum: "+joinValues(schema.Enum))
+	}
+	if schema.Default != nil {
+		constraints = append(constraints, fmt.Sprintf("default: %v", schema.Default))
+	}
+	constraints = appendBounds(constraints, schema.Min, schema.ExclusiveMin, schema.Max, schema.ExclusiveMax)
+	if schema.MultipleOf != nil {
+		constraints = append(constraints, fmt.Sprintf("multipleOf: %v", *schema.MultipleOf))
+	}
+	constraints = appendLengths(constraints, "Length", schema.MinLength, schema.MaxLength)
+	if len(schema.Pattern) > 0 {
+		constraints = append(constraints, "pattern: "+schema.Pattern)
+	}
+	constraints = appendLengths(constraints, "Items", schema.MinItems, schema.MaxItems)
+	if schema.UniqueItems {
+		constraints = append(constraints, "uniqueItems")
+	}
+	if schema.Nullable {
+		constraints = append(constraints, "nullable")
+	}
+	if schema.ReadOnly {
+		constraints = append(constraints, "readOnly")
+	}
+	if deprecated, ok := schema.Extensions[deprecatedExtension].(bool); ok && deprecated {
+		constraints = append(constraints, "deprecated")
+	}
+	return constraints
+}
+
+// ParameterConstraints returns the validations and defaults of a parameter
+func ParameterConstraints(parameter *openapi2.Parameter) []string {
+	if parameter.Schema != nil {
+		return Constraints(parameter.Schema.Value)
+	}
+	constraints := make([]string, 0)
+	if len(parameter.Enum) > 0 {
+		constraints = append(constraints, "enum: "+joinValues(parameter.Enum))
+	}
+	if parameter.Default != nil {
+		constraints = append(constraints, fmt.Sprintf("default: %v", parameter.Default))
+	}
+	constraints = appendBounds(constraints, parameter.Minimum, parameter.ExclusiveMin, parameter.Maximum, parameter.ExclusiveMax)
+	constraints = appendLengths(constraints, "Length", parameter.MinLength, parameter.MaxLength)
+	if len(parameter.Pattern) > 0 {
+		constraints = append(constraints, "pattern: "+parameter.Pattern)
+	}
+	constraints = appendLengths(constraints, "Items", parameter.MinItems, parameter.MaxItems)
+	if parameter.UniqueItems {
+		constraints = append(constraints, "uniqueItems")
+	}
+	return constraints
+}
+
+func appendBounds(constraints []string, min *float64, exclusiveMin bool, max *float64, exclusiveMax bool) []string {
+	if min != nil {
+		name := "minimum"
+		if exclusiveMin {
+			name = "exclusiveMinimum"
+		}
+		constraints = append(constraints, fmt.Sprintf("%s: %v", name, *min))
+	}
+	if max != nil {
+		name := "maximum"
+		if exclusiveMax {
+			name = "exclusiveMaximum"
+		}
+		constraints = append(constraints, fmt.Sprintf("%s: %v", name, *max))
+	}
+	return constraints
+}
+
+func appendLengths(constraints []string, kind string, min uint64, max *uint64) []string {
+	if min > 0 {
+		constraints = append(constraints, fmt.Sprintf("min%s: %d", kind, min))
+	}
+	if max != nil {
+		constraints = append(constraints, fmt.Sprintf("max%s: %d", kind, *max))
+	}
+	return constraints
+}
+
+func joinValues(values []interface{}) string {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		strs = append(strs, fmt.Sprint(v))
+	}
+	return strings.Join(strs, ", ")
+}
+
+// Example returns the example of a schema, when it has none the example is built from the
+// examples, defaults, enums, formats, minimums and types of its properties as the synthesized
+// examples of the swagger document are
+func Example(schemaRef *openapi3.SchemaRef, definitions map[string]*openapi3.SchemaRef) interface{} {
+	return example(schemaRef, definitions, make(map[string]bool))
+}
+
+func example(schemaRef *openapi3.SchemaRef, definitions map[string]*openapi3.SchemaRef, visiting map[string]bool) interface{} {
+	if schemaRef == nil {
+		return nil
+	}
+	if len(schemaRef.Ref) > 0 {
+		name := RefName(schemaRef.Ref)
+		if visiting[name] {
+			// recursive definitions end with an empty value
+			return nil
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		if def, ok := definitions[name]; ok {
+			return example(def, definitions, visiting)
+		}
+	}
+	schema := schemaRef.Value
+	if schema == nil {
+		return nil
+	}
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+	if ref := FieldRef(schemaRef); ref != nil {
+		return example(ref, definitions, visiting)
+	}
+	switch schema.Type {
+	case "array":
+		item := example(schema.Items, definitions, visiting)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "string":
+		return stringExample(schema.Format)
+	case "integer":
+		if schema.Min != nil {
+			return int64(*schema.Min)
+		}
+		return 0
+	case "number":
+		if schema.Min != nil {
+			return *schema.Min
+		}
+		return 0.0
+	case "boolean":
+		return true
+	}
+	object := make(map[string]interface{})
+	for name, property := range schema.Properties {
+		object[name] = example(property, definitions, visiting)
+	}
+	if schema.AdditionalProperties != nil {
+		if value := example(schema.AdditionalProperties, definitions, visiting); value != nil {
+			object["key"] = value
+		}
+	}
+	return object
+}
+
+// formatExamples are the examples of the string formats, the same values are used in the
+// examples generated in the swagger document and in the documentation
+var formatExamples = map[string]string{
+	"date-time": "2020-01-31T15:04:05Z",
+	"date":      "2020-01-31",
+	"uuid":      "3fa85f64-5717-4562-b3fc-2c963f66afa6",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "U3dhZ28=",
+	"decimal":   "10.50",
+}
+
+// FormatExample returns the example of a string format and false when the format has none
+func FormatExample(format string) (string, bool) {
+	example, ok := formatExamples[format]
+	return example, ok
+}
+
+func stringExample(format string) string {
+	if example, ok := FormatExample(format); ok {
+		return example
+	}
+	return "string"
+}
@@ -0,0 +1,137 @@
+package swagger
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExample(t *testing.T) {
+	type params struct {
+		schema *openapi3.SchemaRef
+	}
+	type expected struct {
+		example interface{}
+	}
+	definitions := map[string]*openapi3.SchemaRef{
+		"User": openapi3.NewSchemaRef("", &openapi3.Schema{
+			Type: "object",
+			Properties: map[string]*openapi3.SchemaRef{
+				"name":    openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string", Example: "Jane"}),
+				"role":    openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string", Enum: []interface{}{"admin", "user"}}),
+				"created": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string", Format: "date-time"}),
+				"friends": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "array", Items: openapi3.NewSchemaRef("#/definitions/User", nil)}),
+			},
+		}),
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "schema example",
+			params:   params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "integer", Example: 3})},
+			expected: expected{example: 3},
+		},
+		{
+			name:   "recursive definition",
+			params: params{schema: openapi3.NewSchemaRef("#/definitions/User", nil)},
+			expected: expected{example: map[string]interface{}{
+				"name":    "Jane",
+				"role":    "admin",
+				"created": "2020-01-31T15:04:05Z",
+				"friends": []interface{}{},
+			}},
+		},
+		{
+			name:     "array of integers",
+			params:   params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "array", Items: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "integer"})})},
+			expected: expected{example: []interface{}{0}},
+		},
+		{
+			name:     "integer with a minimum",
+			params:   params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "integer", Min: openapi3.Float64Ptr(1)})},
+			expected: expected{example: int64(1)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.example, Example(test.params.schema, definitions))
+		})
+	}
+}
+
+func TestTagGroups(t *testing.T) {
+	users := &openapi2.Operation{Tags: []string{"users"}}
+	deprecated := &openapi2.Operation{Tags: []string{"users", "admin"}}
+	untagged := &openapi2.Operation{}
+	doc := openapi2.Swagger{
+		Tags: openapi3.Tags{{Name: "admin", Description: "Administration"}, {Name: "unused"}},
+		Paths: map[string]*openapi2.PathItem{
+			"/users":      {Get: users, Delete: deprecated},
+			"/health":     {Get: untagged},
+			"/users/{id}": {Parameters: openapi2.Parameters{{Name: "id", In: "path"}}, Put: users},
+		},
+	}
+	extensions := Extensions{}
+	extensions.SetOperationProp(deprecated, DeprecatedProp, true)
+	groups := TagGroups(doc, extensions)
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	assert.Equal(t, []string{"admin", DefaultTag, "users"}, names)
+	assert.Equal(t, "Administration", groups[0].Description)
+	assert.True(t, groups[0].Endpoints[0].Deprecated)
+	endpoints := make([]string, 0, len(groups[2].Endpoints))
+	for _, e := range groups[2].Endpoints {
+		endpoints = append(endpoints, e.Method+" "+e.Path)
+	}
+	assert.Equal(t, []string{"GET /users", "DELETE /users", "PUT /users/{id}"}, endpoints)
+	assert.Len(t, groups[2].Endpoints[2].Parameters, 1)
+}
+
+func TestSecurityText(t *testing.T) {
+	type params struct {
+		security openapi2.SecurityRequirements
+	}
+	type expected struct {
+		text string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should describe the scopes of a scheme",
+			params:   params{security: openapi2.SecurityRequirements{{"jwt": {"users:read", "users:write"}}}},
+			expected: expected{text: "`jwt` (users:read, users:write)"},
+		},
+		{
+			name:     "should describe the required schemes and the alternatives",
+			params:   params{security: openapi2.SecurityRequirements{{"jwt": {}, "apiKey": {}}, {"basic": {}}}},
+			expected: expected{text: "`apiKey` and `jwt` or `basic`"},
+		},
+		{
+			name:     "should describe an optional security",
+			params:   params{security: openapi2.SecurityRequirements{{"jwt": {}}, {}}},
+			expected: expected{text: "`jwt` or none"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.text, SecurityText(test.params.security, func(name string) string { return "`" + name + "`" }))
+		})
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	e := Endpoint{Path: "/users/{userID}", Method: "GET"}
+	assert.Equal(t, "tag-user-admin", TagAnchor("User Admin"))
+	assert.Equal(t, "users-get-users-userid", EndpointAnchor("users", e))
+	assert.Equal(t, "definition-user-response", DefinitionAnchor("User.Response"))
+}
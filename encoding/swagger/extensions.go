@@ -0,0 +1,201 @@
+package swagger
+
+import (
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const (
+	// DeprecatedProp marks an operation as deprecated
+	DeprecatedProp = "deprecated"
+)
+
+// Extensions are the properties of a document that openapi2 does not model,
+// such as an operation being deprecated or any vendor extension
+type Extensions struct {
+	Operations map[*openapi2.Operation]map[string]interface{}
+}
+
+// SetOperationProp sets a property of an operation
+func (e *Extensions) SetOperationProp(operation *openapi2.Operation, name string, value interface{}) {
+	if e.Operations == nil {
+		e.Operations = make(map[*openapi2.Operation]map[string]interface{})
+	}
+	props, ok := e.Operations[operation]
+	if !ok {
+		props = make(map[string]interface{})
+		e.Operations[operation] = props
+	}
+	props[name] = value
+}
+
+// OperationProp returns a property of an operation
+func (e Extensions) OperationProp(operation *openapi2.Operation, name string) (interface{}, bool) {
+	props, ok := e.Operations[operation]
+	if !ok {
+		return nil, false
+	}
+	value, ok := props[name]
+	return value, ok
+}
+
+// IsDeprecated returns true if an operation was marked as deprecated
+func (e Extensions) IsDeprecated(operation *openapi2.Operation) bool {
+	value, ok := e.OperationProp(operation, DeprecatedProp)
+	if !ok {
+		return false
+	}
+	deprecated, ok := value.(bool)
+	return ok && deprecated
+}
+
+// RemoveDeprecated removes the deprecated operations from a document, paths without
+// operations are removed too. The definitions, parameters, responses and tags that only
+// the deprecated operations used are removed as well
+func (e Extensions) RemoveDeprecated(swagger *openapi2.Swagger) {
+	before := documentRefs(swagger)
+	for path, pathItem := range swagger.Paths {
+		for method, operation := range pathItem.Operations() {
+			if e.IsDeprecated(operation) {
+				pathItem.SetOperation(method, nil)
+				delete(e.Operations, operation)
+			}
+		}
+		if len(pathItem.Operations()) == 0 {
+			delete(swagger.Paths, path)
+		}
+	}
+	after := documentRefs(swagger)
+	for name := range before.definitions {
+		if !after.definitions[name] {
+			delete(swagger.Definitions, name)
+		}
+	}
+	for name := range before.parameters {
+		if !after.parameters[name] {
+			delete(swagger.Parameters, name)
+		}
+	}
+	for name := range before.responses {
+		if !after.responses[name] {
+			delete(swagger.Responses, name)
+		}
+	}
+	tags := swagger.Tags[:0]
+	for _, tag := range swagger.Tags {
+		if !before.tags[tag.Name] || after.tags[tag.Name] {
+			tags = append(tags, tag)
+		}
+	}
+	swagger.Tags = tags
+}
+
+// refs are the names of the definitions, parameters, responses and tags used by the
+// operations of a document, the references between them are followed
+type refs struct {
+	swagger     *openapi2.Swagger
+	definitions map[string]bool
+	parameters  map[string]bool
+	responses   map[string]bool
+	tags        map[string]bool
+}
+
+func documentRefs(swagger *openapi2.Swagger) refs {
+	r := refs{
+		swagger:     swagger,
+		definitions: make(map[string]bool),
+		parameters:  make(map[string]bool),
+		responses:   make(map[string]bool),
+		tags:        make(map[string]bool),
+	}
+	for _, pathItem := range swagger.Paths {
+		for _, parameter := range pathItem.Parameters {
+			r.addParameter(parameter)
+		}
+		for _, operation := range pathItem.Operations() {
+			for _, tag := range operation.Tags {
+				r.tags[tag] = true
+			}
+			for _, parameter := range operation.Parameters {
+				r.addParameter(parameter)
+			}
+			for _, response := range operation.Responses {
+				r.addResponse(response)
+			}
+		}
+	}
+	return r
+}
+
+func (r refs) addParameter(parameter *openapi2.Parameter) {
+	if parameter == nil {
+		return
+	}
+	if len(parameter.Ref) > 0 {
+		name := RefName(parameter.Ref)
+		if !r.parameters[name] {
+			r.parameters[name] = true
+			r.addParameter(r.swagger.Parameters[name])
+		}
+		return
+	}
+	r.addSchema(parameter.Schema)
+	r.addSchema(parameter.Items)
+}
+
+func (r refs) addResponse(response *openapi2.Response) {
+	if response == nil {
+		return
+	}
+	if len(response.Ref) > 0 {
+		name := RefName(response.Ref)
+		if !r.responses[name] {
+			r.responses[name] = true
+			r.addResponse(r.swagger.Responses[name])
+		}
+		return
+	}
+	r.addSchema(response.Schema)
+}
+
+func (r refs) addSchema(schemaRef *openapi3.SchemaRef) {
+	if schemaRef == nil {
+		return
+	}
+	if len(schemaRef.Ref) > 0 {
+		name := RefName(schemaRef.Ref)
+		if !r.definitions[name] {
+			r.definitions[name] = true
+			r.addSchema(r.swagger.Definitions[name])
+		}
+		return
+	}
+	schema := schemaRef.Value
+	if schema == nil {
+		return
+	}
+	r.addSchema(schema.Items)
+	r.addSchema(schema.AdditionalProperties)
+	r.addSchema(schema.Not)
+	for _, property := range schema.Properties {
+		r.addSchema(property)
+	}
+	for _, schemas := range [][]*openapi3.SchemaRef{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, s := range schemas {
+			r.addSchema(s)
+		}
+	}
+}
+
+func marshalExtensionProps(props map[string]interface{}, indent int, ew *errorWriter) {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		marshalInterface(k, props[k], indent, ew)
+	}
+}
@@ -0,0 +1,95 @@
+package swagger
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveDeprecated(t *testing.T) {
+	ref := func(name string) *openapi3.SchemaRef {
+		return openapi3.NewSchemaRef(definitionsRefPrefix+name, nil)
+	}
+	object := func(properties map[string]*openapi3.SchemaRef) *openapi3.SchemaRef {
+		return openapi3.NewSchemaRef("", &openapi3.Schema{Type: "object", Properties: properties})
+	}
+	listUsers := &openapi2.Operation{
+		Tags:       []string{"users"},
+		Parameters: openapi2.Parameters{{Ref: parametersRefPrefix + "page"}},
+		Responses: map[string]*openapi2.Response{
+			"200": {Schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "array", Items: ref("User")})},
+			"500": {Ref: responsesRefPrefix + "InternalServerError"},
+		},
+	}
+	legacyUsers := &openapi2.Operation{
+		Tags:       []string{"users", "legacy"},
+		Parameters: openapi2.Parameters{{Ref: parametersRefPrefix + "legacyFilter"}, {In: "body", Name: "body", Schema: ref("LegacyRequest")}},
+		Responses: map[string]*openapi2.Response{
+			"200": {Schema: ref("LegacyUser")},
+			"410": {Ref: responsesRefPrefix + "Gone"},
+			"500": {Ref: responsesRefPrefix + "InternalServerError"},
+		},
+	}
+	doc := &openapi2.Swagger{
+		Tags: openapi3.Tags{{Name: "users"}, {Name: "legacy"}, {Name: "unused"}},
+		Paths: map[string]*openapi2.PathItem{
+			"/users":        {Get: listUsers},
+			"/legacy/users": {Get: legacyUsers},
+		},
+		Definitions: map[string]*openapi3.SchemaRef{
+			"User":          object(map[string]*openapi3.SchemaRef{"address": ref("Address")}),
+			"Address":       object(nil),
+			"LegacyUser":    object(map[string]*openapi3.SchemaRef{"user": ref("User"), "flags": ref("LegacyFlags")}),
+			"LegacyFlags":   object(nil),
+			"LegacyRequest": object(nil),
+			"Error":         object(nil),
+			"GoneError":     object(nil),
+			"Unused":        object(nil),
+		},
+		Parameters: map[string]*openapi2.Parameter{
+			"page":         {In: "query", Name: "page", Type: "integer"},
+			"legacyFilter": {In: "query", Name: "filter", Type: "string"},
+			"unused":       {In: "query", Name: "unused", Type: "string"},
+		},
+		Responses: map[string]*openapi2.Response{
+			"InternalServerError": {Schema: ref("Error")},
+			"Gone":                {Schema: ref("GoneError")},
+			"Unused":              {Description: "Unused"},
+		},
+	}
+	extensions := Extensions{}
+	extensions.SetOperationProp(legacyUsers, DeprecatedProp, true)
+	extensions.RemoveDeprecated(doc)
+	keys := func(m interface{}) []string {
+		names := make([]string, 0)
+		switch m := m.(type) {
+		case map[string]*openapi3.SchemaRef:
+			for name := range m {
+				names = append(names, name)
+			}
+		case map[string]*openapi2.Parameter:
+			for name := range m {
+				names = append(names, name)
+			}
+		case map[string]*openapi2.Response:
+			for name := range m {
+				names = append(names, name)
+			}
+		case map[string]*openapi2.PathItem:
+			for name := range m {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+	assert.Equal(t, []string{"/users"}, keys(doc.Paths))
+	assert.Equal(t, []string{"Address", "Error", "Unused", "User"}, keys(doc.Definitions), "the definitions used by other operations or by none must be kept")
+	assert.Equal(t, []string{"page", "unused"}, keys(doc.Parameters))
+	assert.Equal(t, []string{"InternalServerError", "Unused"}, keys(doc.Responses))
+	assert.Equal(t, openapi3.Tags{{Name: "users"}, {Name: "unused"}}, doc.Tags)
+	assert.False(t, extensions.IsDeprecated(legacyUsers))
+}
@@ -0,0 +1,87 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"gopkg.in/yaml.v2"
+)
+
+// MarshalJSONWithExtensions marshals a Swagger definition and the properties that
+// openapi2 does not model to indented JSON, the keys keep the order of the YAML
+func MarshalJSONWithExtensions(swagger openapi2.Swagger, extensions Extensions, w io.Writer) error {
+	buf := &bytes.Buffer{}
+	if err := MarshalYAMLWithExtensions(swagger, extensions, buf); err != nil {
+		return err
+	}
+	return YAMLToJSON(buf.Bytes(), w)
+}
+
+// YAMLToJSON converts a YAML document to indented JSON
+func YAMLToJSON(in []byte, w io.Writer) error {
+	doc := yaml.MapSlice{}
+	if err := yaml.Unmarshal(in, &doc); err != nil {
+		return fmt.Errorf("error parsing yaml: %w", err)
+	}
+	ew := &errorWriter{w: w}
+	writeJSONValue(doc, 0, ew)
+	ew.Write([]byte("\n"))
+	return ew.err
+}
+
+func writeJSONValue(value interface{}, indent int, ew *errorWriter) {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		if len(v) == 0 {
+			ew.Write([]byte("{}"))
+			return
+		}
+		ew.Write([]byte("{\n"))
+		for i, item := range v {
+			writeJSONIndent(indent+1, ew)
+			writeJSONScalar(fmt.Sprint(item.Key), ew)
+			ew.Write([]byte(": "))
+			writeJSONValue(item.Value, indent+1, ew)
+			if i < len(v)-1 {
+				ew.Write([]byte(","))
+			}
+			ew.Write([]byte("\n"))
+		}
+		writeJSONIndent(indent, ew)
+		ew.Write([]byte("}"))
+	case []interface{}:
+		if len(v) == 0 {
+			ew.Write([]byte("[]"))
+			return
+		}
+		ew.Write([]byte("[\n"))
+		for i, item := range v {
+			writeJSONIndent(indent+1, ew)
+			writeJSONValue(item, indent+1, ew)
+			if i < len(v)-1 {
+				ew.Write([]byte(","))
+			}
+			ew.Write([]byte("\n"))
+		}
+		writeJSONIndent(indent, ew)
+		ew.Write([]byte("]"))
+	default:
+		writeJSONScalar(v, ew)
+	}
+}
+
+func writeJSONScalar(value interface{}, ew *errorWriter) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		// yaml keys of other types than strings are written as strings
+		b, _ = json.Marshal(fmt.Sprint(value))
+	}
+	ew.Write(b)
+}
+
+func writeJSONIndent(indent int, ew *errorWriter) {
+	ew.Write(bytes.Repeat([]byte("  "), indent))
+}
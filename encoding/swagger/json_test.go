@@ -0,0 +1,61 @@
+package swagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalJSONWithExtensions(t *testing.T) {
+	type params struct {
+		doc openapi2.Swagger
+	}
+	type expected struct {
+		json string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name: "should keep the strings that yaml reads as other types",
+			params: params{doc: openapi2.Swagger{
+				Info:     openapi3.Info{Title: "true", Version: "1.0"},
+				BasePath: "null",
+				Tags:     openapi3.Tags{{Name: "2020"}},
+			}},
+			expected: expected{json: `{"swagger": "2.0", "info": {"title": "true", "version": "1.0"}, "basePath": "null", "tags": [{"name": "2020"}]}`},
+		},
+		{
+			name: "should keep the names that yaml reads as other types",
+			params: params{doc: openapi2.Swagger{
+				Definitions: map[string]*openapi3.SchemaRef{
+					"Flags": openapi3.NewSchemaRef("", &openapi3.Schema{
+						Type:     "object",
+						Required: []string{"yes", "1e3"},
+						Properties: map[string]*openapi3.SchemaRef{
+							"1e3": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string"}),
+							"yes": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "boolean"}),
+						},
+					}),
+				},
+			}},
+			expected: expected{json: `{"swagger": "2.0", "definitions": {"Flags": {"type": "object", "properties": {"1e3": {"type": "string"}, "yes": {"type": "boolean"}}, "required": ["yes", "1e3"]}}}`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			assert.Nil(t, MarshalJSONWithExtensions(test.params.doc, Extensions{}, buf))
+			var actual, expected interface{}
+			assert.Nil(t, json.Unmarshal(buf.Bytes(), &actual))
+			assert.Nil(t, json.Unmarshal([]byte(test.expected.json), &expected))
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v2"
@@ -17,13 +18,19 @@ import (
 
 var (
 	escapePropNameRegExp = regexp.MustCompile("^[a-zA-Z_0-9]+$")
-	escapeStrValRegExp   = regexp.MustCompile("[#]")
+	escapeStrValRegExp   = regexp.MustCompile("[#\\\\]|: |^[-?:,\\[\\]{}&*!|>'\"%@`\\s]|\\s$")
 )
 
 // MarshalYAML marshals a Swagger definition to YAML
 func MarshalYAML(swagger openapi2.Swagger, w io.Writer) error {
+	return MarshalYAMLWithExtensions(swagger, Extensions{}, w)
+}
+
+// MarshalYAMLWithExtensions marshals a Swagger definition and the properties that
+// openapi2 does not model to YAML
+func MarshalYAMLWithExtensions(swagger openapi2.Swagger, extensions Extensions, w io.Writer) error {
 	ew := &errorWriter{w: w}
-	writeStringProp("swagger", "\"2.0\"", 0, ew)
+	writeRawStringProp("swagger", "\"2.0\"", 0, ew)
 	if !isEmptyInfo(swagger.Info) {
 		marshalInfo(swagger.Info, ew)
 	}
@@ -41,35 +48,60 @@ func MarshalYAML(swagger openapi2.Swagger, w io.Writer) error {
 	}
 	if !isEmptyParameters(swagger.Parameters) {
 		writeObject("parameters", 0, ew)
-		for def, param := range swagger.Parameters {
+		keys := make([]string, 0, len(swagger.Parameters))
+		for k := range swagger.Parameters {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, def := range keys {
 			writeObject(def, 1, ew)
-			marshalParameter(param, 2, ew)
+			marshalParameter(swagger.Parameters[def], 2, ew)
 		}
 	}
 	if !isEmptyPaths(swagger.Paths) {
 		writeObject("paths", 0, ew)
-		for url, pathItem := range swagger.Paths {
+		keys := make([]string, 0, len(swagger.Paths))
+		for k := range swagger.Paths {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, url := range keys {
 			writeObject(url, 1, ew)
-			marshalPath(pathItem, ew)
+			marshalPath(swagger.Paths[url], extensions, ew)
 		}
 	}
 	if !isEmptyDefinitions(swagger.Definitions) {
 		writeObject("definitions", 0, ew)
-		for def, schemaRef := range swagger.Definitions {
+		keys := make([]string, 0, len(swagger.Definitions))
+		for k := range swagger.Definitions {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, def := range keys {
 			writeObject(def, 1, ew)
-			marshalSchemaRef(schemaRef, 2, ew)
+			marshalSchemaRef(swagger.Definitions[def], 2, ew)
 		}
 	}
 	if !isEmptyResponses(swagger.Responses) {
 		writeObject("responses", 0, ew)
-		for code, resp := range swagger.Responses {
-			marshalResponse(code, resp, 1, ew)
+		keys := make([]string, 0, len(swagger.Responses))
+		for k := range swagger.Responses {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, code := range keys {
+			marshalResponse(code, swagger.Responses[code], 1, ew)
 		}
 	}
 	if !isEmptySecurityDefinitions(swagger.SecurityDefinitions) {
 		writeObject("securityDefinitions", 0, ew)
-		for name, scheme := range swagger.SecurityDefinitions {
-			marshalSecurityScheme(name, scheme, 1, ew)
+		keys := make([]string, 0, len(swagger.SecurityDefinitions))
+		for k := range swagger.SecurityDefinitions {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, name := range keys {
+			marshalSecurityScheme(name, swagger.SecurityDefinitions[name], 1, ew)
 		}
 	}
 	if !isEmptySecurity(swagger.Security) {
@@ -89,9 +121,30 @@ func writeLn(line string, indent int, ew *errorWriter) {
 	ew.Write([]byte("\n"))
 }
 
+// plainScalar returns the value a plain yaml scalar is read as, as a float for 1.0 or a bool for true
+func plainScalar(val string) interface{} {
+	var scalar interface{}
+	if err := yaml.Unmarshal([]byte(val), &scalar); err != nil {
+		return nil
+	}
+	return scalar
+}
+
+// isPlainName returns true when a plain yaml key is read as the same name, status codes are
+// read as integers which are converted back to the same name
+func isPlainName(name string) bool {
+	switch scalar := plainScalar(name).(type) {
+	case string:
+		return scalar == name
+	case int:
+		return strconv.Itoa(scalar) == name
+	}
+	return false
+}
+
 func escapePropName(name string) string {
 	escapedName := name
-	if !escapePropNameRegExp.MatchString(name) {
+	if !escapePropNameRegExp.MatchString(name) || !isPlainName(name) {
 		escapedName = "\"" + strings.ReplaceAll(name, "\"", "\\\"") + "\""
 	}
 	return escapedName
@@ -99,8 +152,10 @@ func escapePropName(name string) string {
 
 func escapeStrVal(val string) string {
 	escapedName := val
-	if escapeStrValRegExp.MatchString(val) {
-		escapedName = "\"" + strings.ReplaceAll(val, "\"", "\\\"") + "\""
+	// values as 1.0, true or null are quoted so they are read as strings
+	if escapeStrValRegExp.MatchString(val) || plainScalar(val) != interface{}(val) {
+		escaped := strings.ReplaceAll(val, "\\", "\\\\")
+		escapedName = "\"" + strings.ReplaceAll(escaped, "\"", "\\\"") + "\""
 	}
 	return escapedName
 }
@@ -119,7 +174,12 @@ func writeRawStringProp(name, value string, indent int, ew *errorWriter) {
 
 func writeStringProp(name, value string, indent int, ew *errorWriter) {
 	escapedName := escapePropName(name)
-	writeRawStringProp(escapedName, escapeStrVal(value), indent, ew)
+	if strings.Contains(value, "\n") {
+		// multiline values are written as folded blocks which do not need escaping
+		writeRawStringProp(escapedName, value, indent, ew)
+	} else {
+		writeRawStringProp(escapedName, escapeStrVal(value), indent, ew)
+	}
 }
 
 func writeStrSlice(name string, values []string, indent int, ew *errorWriter) {
@@ -127,7 +187,7 @@ func writeStrSlice(name string, values []string, indent int, ew *errorWriter) {
 	escapedName := escapePropName(name)
 	writeLn(escapedName+":", indent, ew)
 	for _, v := range values {
-		writeLn(fmt.Sprintf("- %s", v), indent+1, ew)
+		writeLn(fmt.Sprintf("- %s", escapeStrVal(v)), indent+1, ew)
 	}
 }
 
@@ -137,7 +197,7 @@ func writeArrStringProp(name, value string, indent int, ew *errorWriter) {
 
 func writeFloat64Prop(name string, value float64, indent int, ew *errorWriter) {
 	escapedName := escapePropName(name)
-	writeLn(fmt.Sprintf("%s: %f.ff", escapedName, value), indent, ew)
+	writeLn(fmt.Sprintf("%s: %s", escapedName, strconv.FormatFloat(value, 'f', -1, 64)), indent, ew)
 }
 
 func writeUint64Prop(name string, value uint64, indent int, ew *errorWriter) {
@@ -223,31 +283,37 @@ func marshalLicensePtr(license *openapi3.License, ew *errorWriter) {
 	}
 }
 
-func marshalPath(pathItem *openapi2.PathItem, ew *errorWriter) {
+func marshalPath(pathItem *openapi2.PathItem, extensions Extensions, ew *errorWriter) {
 	if !isEmptyString(pathItem.Ref) {
 		writeStringProp("$ref", pathItem.Ref, 2, ew)
 	}
 	if pathItem.Delete != nil {
 		writeObject("delete", 2, ew)
-		marshalOperation(pathItem.Delete, ew)
-	} else if pathItem.Get != nil {
+		marshalOperation(pathItem.Delete, extensions, ew)
+	}
+	if pathItem.Get != nil {
 		writeObject("get", 2, ew)
-		marshalOperation(pathItem.Get, ew)
-	} else if pathItem.Head != nil {
+		marshalOperation(pathItem.Get, extensions, ew)
+	}
+	if pathItem.Head != nil {
 		writeObject("head", 2, ew)
-		marshalOperation(pathItem.Head, ew)
-	} else if pathItem.Options != nil {
+		marshalOperation(pathItem.Head, extensions, ew)
+	}
+	if pathItem.Options != nil {
 		writeObject("options", 2, ew)
-		marshalOperation(pathItem.Options, ew)
-	} else if pathItem.Patch != nil {
+		marshalOperation(pathItem.Options, extensions, ew)
+	}
+	if pathItem.Patch != nil {
 		writeObject("patch", 2, ew)
-		marshalOperation(pathItem.Patch, ew)
-	} else if pathItem.Post != nil {
+		marshalOperation(pathItem.Patch, extensions, ew)
+	}
+	if pathItem.Post != nil {
 		writeObject("post", 2, ew)
-		marshalOperation(pathItem.Post, ew)
-	} else if pathItem.Put != nil {
+		marshalOperation(pathItem.Post, extensions, ew)
+	}
+	if pathItem.Put != nil {
 		writeObject("put", 2, ew)
-		marshalOperation(pathItem.Put, ew)
+		marshalOperation(pathItem.Put, extensions, ew)
 	}
 	if !isEmptyPathItemParameters(pathItem.Parameters) {
 		writeObject("parameters", 2, ew)
@@ -257,7 +323,7 @@ func marshalPath(pathItem *openapi2.PathItem, ew *errorWriter) {
 	}
 }
 
-func marshalOperation(operation *openapi2.Operation, ew *errorWriter) {
+func marshalOperation(operation *openapi2.Operation, extensions Extensions, ew *errorWriter) {
 	if !isEmptyString(operation.Summary) {
 		writeStringProp("summary", operation.Summary, 3, ew)
 	}
@@ -265,7 +331,7 @@ func marshalOperation(operation *openapi2.Operation, ew *errorWriter) {
 		writeStringProp("description", operation.Description, 3, ew)
 	}
 	if !isEmptyString(operation.OperationID) {
-		writeStringProp("operationid", operation.OperationID, 3, ew)
+		writeStringProp("operationId", operation.OperationID, 3, ew)
 	}
 	if !isEmptyStrSlice(operation.Consumes) {
 		writeStrSlice("consumes", operation.Consumes, 3, ew)
@@ -300,6 +366,9 @@ func marshalOperation(operation *openapi2.Operation, ew *errorWriter) {
 			marshalResponse(code, operation.Responses[code], 4, ew)
 		}
 	}
+	if props, ok := extensions.Operations[operation]; ok {
+		marshalExtensionProps(props, 3, ew)
+	}
 }
 
 func marshalExternalDocs(externalDocs *openapi3.ExternalDocs, indent int, ew *errorWriter) {
@@ -461,7 +530,13 @@ func marshalResponse(code string, response *openapi2.Response, indent int, ew *e
 
 func marshalHeaders(headers map[string]*openapi2.Header, indent int, ew *errorWriter) {
 	writeObject("headers", indent, ew)
-	for name, header := range headers {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		header := headers[name]
 		writeObject(name, indent+1, ew)
 		if !isEmptyString(header.Ref) {
 			writeStringProp("$ref", header.Ref, indent+2, ew)
@@ -544,6 +619,9 @@ func marshalSchema(schema *openapi3.Schema, indent int, ew *errorWriter) {
 	if !isEmptyString(schema.Pattern) {
 		writeStringProp("pattern", schema.Pattern, indent, ew)
 	}
+	if schema.Nullable {
+		writeBoolProp("x-nullable", schema.Nullable, indent, ew)
+	}
 	if !isEmptyInterfaceSlice(schema.Enum) {
 		marshalInterface("enum", schema.Enum, indent, ew)
 	}
@@ -595,9 +673,14 @@ func marshalSchema(schema *openapi3.Schema, indent int, ew *errorWriter) {
 	}
 	if !isEmptySchemaRefMap(schema.Properties) {
 		writeObject("properties", indent, ew)
-		for name, property := range schema.Properties {
+		keys := make([]string, 0, len(schema.Properties))
+		for k := range schema.Properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, name := range keys {
 			writeObject(name, indent+1, ew)
-			marshalSchemaRef(property, indent+2, ew)
+			marshalSchemaRef(schema.Properties[name], indent+2, ew)
 		}
 	}
 	if !isEmptySchemaRef(schema.AdditionalProperties) {
@@ -619,6 +702,9 @@ func marshalSchema(schema *openapi3.Schema, indent int, ew *errorWriter) {
 	if !isEmptyInterface(schema.Example) {
 		marshalInterface("example", schema.Example, indent, ew)
 	}
+	if len(schema.Extensions) > 0 {
+		marshalExtensionProps(schema.Extensions, indent, ew)
+	}
 	if !isEmptyStrSlice(schema.Required) {
 		writeStrSlice("required", schema.Required, indent, ew)
 	}
@@ -737,12 +823,12 @@ func marshalTags(tags []*openapi3.Tag, indent int, ew *errorWriter) {
 	writeObject("tags", indent, ew)
 	for _, tag := range tags {
 		// name is a required property for tags, so it must exists
-		writeArrStringProp("name", tag.Name, indent+1, ew)
+		writeArrStringProp("name", escapeStrVal(tag.Name), indent+1, ew)
 		if !isEmptyString(tag.Description) {
-			writeStringProp("description", tag.Description, indent+1, ew)
+			writeStringProp("description", tag.Description, indent+2, ew)
 		}
 		if !isEmptyExternalDocs(tag.ExternalDocs) {
-			marshalExternalDocs(tag.ExternalDocs, indent+1, ew)
+			marshalExternalDocs(tag.ExternalDocs, indent+2, ew)
 		}
 	}
 }
@@ -0,0 +1,407 @@
+// Package typescript writes the TypeScript types of a swagger document, the interfaces of its
+// definitions and the requests and responses of its operations
+package typescript
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/encoding/swagger"
+)
+
+var (
+	identifierRegExp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	nonWordRegExp    = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// paramGroups are the keys of a request type by parameter location
+var paramGroups = []struct {
+	in  string
+	key string
+}{
+	{in: "path", key: "path"},
+	{in: "query", key: "query"},
+	{in: "header", key: "headers"},
+	{in: "formData", key: "form"},
+}
+
+type writer struct {
+	w       io.Writer
+	err     error
+	swagger openapi2.Swagger
+}
+
+func (tw *writer) printf(format string, args ...interface{}) {
+	if tw.err == nil {
+		_, tw.err = fmt.Fprintf(tw.w, format, args...)
+	}
+}
+
+// operation is an operation with the name of its types
+type operation struct {
+	name     string
+	endpoint swagger.Endpoint
+}
+
+// Marshal writes an interface or a type alias for every definition, the OperationRequests and
+// OperationResponses maps keyed by operation id and the operations constant with the method
+// and path of every operation
+func Marshal(doc openapi2.Swagger, extensions swagger.Extensions, w io.Writer) error {
+	tw := &writer{w: w, swagger: doc}
+	tw.printf("// Code generated by swago. DO NOT EDIT.\n")
+	names := make([]string, 0, len(doc.Definitions))
+	for name := range doc.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tw.definition(name, doc.Definitions[name])
+	}
+	operations := collectOperations(doc, extensions)
+	tw.printf("\nexport interface OperationRequests {\n")
+	for _, op := range operations {
+		tw.comment(op.endpoint.Method+" "+op.endpoint.Path, op.endpoint.Deprecated, 1)
+		tw.printf("  %s: %s;\n", propertyName(op.name), tw.request(op.endpoint, 1))
+	}
+	tw.printf("}\n")
+	tw.printf("\nexport interface OperationResponses {\n")
+	for _, op := range operations {
+		tw.comment(op.endpoint.Method+" "+op.endpoint.Path, op.endpoint.Deprecated, 1)
+		tw.printf("  %s: %s;\n", propertyName(op.name), tw.responses(op.endpoint.Operation.Responses, 1))
+	}
+	tw.printf("}\n")
+	tw.printf("\nexport const operations = {\n")
+	for _, op := range operations {
+		tw.printf("  %s: { method: %s, path: %s },\n", propertyName(op.name), strconv.Quote(op.endpoint.Method), strconv.Quote(op.endpoint.Path))
+	}
+	tw.printf("} as const;\n")
+	tw.printf("\nexport type OperationId = keyof typeof operations;\n")
+	return tw.err
+}
+
+// collectOperations returns every operation once named after its operation id or its method and path
+func collectOperations(doc openapi2.Swagger, extensions swagger.Extensions) []operation {
+	operations := make([]operation, 0)
+	seen := make(map[*openapi2.Operation]bool)
+	names := make(map[string]bool)
+	for _, group := range swagger.TagGroups(doc, extensions) {
+		for _, e := range group.Endpoints {
+			if seen[e.Operation] {
+				continue
+			}
+			seen[e.Operation] = true
+			name := e.Operation.OperationID
+			if len(name) == 0 {
+				name = operationName(e.Method, e.Path)
+			}
+			unique := name
+			for i := 2; names[unique]; i++ {
+				unique = name + strconv.Itoa(i)
+			}
+			names[unique] = true
+			operations = append(operations, operation{name: unique, endpoint: e})
+		}
+	}
+	sort.SliceStable(operations, func(i, j int) bool {
+		return operations[i].name < operations[j].name
+	})
+	return operations
+}
+
+func (tw *writer) definition(name string, def *openapi3.SchemaRef) {
+	tw.printf("\n")
+	if def == nil || def.Value == nil {
+		tw.printf("export type %s = unknown;\n", typeName(name))
+		return
+	}
+	schema := def.Value
+	tw.comment(schema.Description, isDeprecated(schema), 0)
+	if len(def.Ref) == 0 && isObject(schema) && len(schema.Properties) > 0 && schema.AdditionalProperties == nil && !schema.Nullable {
+		tw.printf("export interface %s %s\n", typeName(name), tw.object(schema, 0))
+		return
+	}
+	tw.printf("export type %s = %s;\n", typeName(name), tw.schemaType(def, 0))
+}
+
+// object writes the properties of an object schema, the properties not required are optional
+func (tw *writer) object(schema *openapi3.Schema, indent int) string {
+	if len(schema.Properties) == 0 {
+		return "{}"
+	}
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b := &strings.Builder{}
+	b.WriteString("{\n")
+	for _, name := range names {
+		property := schema.Properties[name]
+		if property != nil && property.Value != nil && len(property.Ref) == 0 {
+			b.WriteString(commentText(property.Value.Description, isDeprecated(property.Value), indent+1))
+		}
+		optional := ""
+		if !required[name] {
+			optional = "?"
+		}
+		fmt.Fprintf(b, "%s%s%s: %s;\n", pad(indent+1), propertyName(name), optional, tw.schemaType(property, indent+1))
+	}
+	b.WriteString(pad(indent) + "}")
+	return b.String()
+}
+
+// schemaType returns the type of a schema, nullable schemas are a union with null
+func (tw *writer) schemaType(schemaRef *openapi3.SchemaRef, indent int) string {
+	if schemaRef == nil {
+		return "unknown"
+	}
+	if len(schemaRef.Ref) > 0 {
+		t := typeName(swagger.RefName(schemaRef.Ref))
+		if schemaRef.Value != nil && schemaRef.Value.Nullable {
+			t += " | null"
+		}
+		return t
+	}
+	schema := schemaRef.Value
+	if schema == nil {
+		return "unknown"
+	}
+	t := tw.valueType(schema, indent)
+	if ref := swagger.FieldRef(schemaRef); ref != nil {
+		t = typeName(swagger.RefName(ref.Ref))
+	}
+	if schema.Nullable {
+		t += " | null"
+	}
+	return t
+}
+
+func (tw *writer) valueType(schema *openapi3.Schema, indent int) string {
+	if len(schema.Enum) > 0 {
+		literals := make([]string, 0, len(schema.Enum))
+		for _, v := range schema.Enum {
+			literals = append(literals, literal(v))
+		}
+		return strings.Join(literals, " | ")
+	}
+	switch schema.Type {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "file":
+		return "Blob"
+	case "array":
+		item := tw.schemaType(schema.Items, indent)
+		if isUnion(item) {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	}
+	if schema.AdditionalProperties != nil {
+		return "Record<string, " + tw.schemaType(schema.AdditionalProperties, indent) + ">"
+	}
+	if len(schema.Properties) > 0 {
+		return tw.object(schema, indent)
+	}
+	if len(schema.Type) == 0 {
+		// a schema without a type might be any value
+		return "unknown"
+	}
+	return "Record<string, unknown>"
+}
+
+// request returns the type of the parameters and the body of an operation
+func (tw *writer) request(e swagger.Endpoint, indent int) string {
+	params := make(map[string][]*openapi2.Parameter)
+	var body *openapi2.Parameter
+	for _, p := range e.Parameters {
+		p = swagger.ResolveParameter(p, tw.swagger)
+		if p.In == "body" {
+			body = p
+			continue
+		}
+		params[p.In] = append(params[p.In], p)
+	}
+	b := &strings.Builder{}
+	b.WriteString("{\n")
+	for _, group := range paramGroups {
+		if len(params[group.in]) == 0 {
+			continue
+		}
+		optional := "?"
+		for _, p := range params[group.in] {
+			if p.Required {
+				optional = ""
+			}
+		}
+		fmt.Fprintf(b, "%s%s%s: {\n", pad(indent+1), group.key, optional)
+		for _, p := range params[group.in] {
+			b.WriteString(commentText(p.Description, false, indent+2))
+			paramOptional := "?"
+			if p.Required {
+				paramOptional = ""
+			}
+			fmt.Fprintf(b, "%s%s%s: %s;\n", pad(indent+2), propertyName(p.Name), paramOptional, tw.paramType(p, indent+2))
+		}
+		fmt.Fprintf(b, "%s};\n", pad(indent+1))
+	}
+	if body != nil {
+		optional := "?"
+		if body.Required {
+			optional = ""
+		}
+		fmt.Fprintf(b, "%sbody%s: %s;\n", pad(indent+1), optional, tw.schemaType(body.Schema, indent+1))
+	}
+	if b.Len() == len("{\n") {
+		return "{}"
+	}
+	b.WriteString(pad(indent) + "}")
+	return b.String()
+}
+
+func (tw *writer) paramType(p *openapi2.Parameter, indent int) string {
+	if p.Schema != nil {
+		return tw.schemaType(p.Schema, indent)
+	}
+	schema := &openapi3.Schema{Type: p.Type, Enum: p.Enum, Items: p.Items}
+	return tw.valueType(schema, indent)
+}
+
+// responses returns the type of the responses of an operation by status code, responses
+// without a schema have no body
+func (tw *writer) responses(responses map[string]*openapi2.Response, indent int) string {
+	if len(responses) == 0 {
+		return "{}"
+	}
+	b := &strings.Builder{}
+	b.WriteString("{\n")
+	for _, code := range swagger.SortedResponseCodes(responses) {
+		r := swagger.ResolveResponse(responses[code], tw.swagger)
+		t := "void"
+		if r.Schema != nil {
+			t = tw.schemaType(r.Schema, indent+1)
+		}
+		b.WriteString(commentText(r.Description, false, indent+1))
+		fmt.Fprintf(b, "%s%s: %s;\n", pad(indent+1), propertyName(code), t)
+	}
+	b.WriteString(pad(indent) + "}")
+	return b.String()
+}
+
+func (tw *writer) comment(text string, deprecated bool, indent int) {
+	tw.printf("%s", commentText(text, deprecated, indent))
+}
+
+// commentText returns a JSDoc comment, deprecated adds the @deprecated tag
+func commentText(text string, deprecated bool, indent int) string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, strings.Replace(line, "*/", "*\\/", -1))
+		}
+	}
+	if deprecated {
+		lines = append(lines, "@deprecated")
+	}
+	switch len(lines) {
+	case 0:
+		return ""
+	case 1:
+		return pad(indent) + "/** " + lines[0] + " */\n"
+	}
+	b := &strings.Builder{}
+	b.WriteString(pad(indent) + "/**\n")
+	for _, line := range lines {
+		b.WriteString(pad(indent) + " * " + line + "\n")
+	}
+	b.WriteString(pad(indent) + " */\n")
+	return b.String()
+}
+
+// isUnion returns true for a union type that is not nested in an object or a generic
+func isUnion(t string) bool {
+	depth := 0
+	for i, r := range t {
+		switch r {
+		case '{', '<', '(':
+			depth++
+		case '}', '>', ')':
+			depth--
+		case '|':
+			if depth == 0 && i > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isObject(schema *openapi3.Schema) bool {
+	return schema.Type == "object" || len(schema.Type) == 0
+}
+
+func isDeprecated(schema *openapi3.Schema) bool {
+	deprecated, ok := schema.Extensions["x-deprecated"].(bool)
+	return ok && deprecated
+}
+
+// literal returns a TypeScript literal type of an enum value
+func literal(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return strconv.Quote(value)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(v)
+}
+
+// propertyName quotes the names that are not identifiers
+func propertyName(name string) string {
+	if identifierRegExp.MatchString(name) {
+		return name
+	}
+	if _, err := strconv.Atoi(name); err == nil {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// typeName makes an identifier of a definition name
+func typeName(name string) string {
+	t := nonWordRegExp.ReplaceAllString(name, "_")
+	if len(t) == 0 || unicode.IsDigit(rune(t[0])) {
+		t = "_" + t
+	}
+	return t
+}
+
+// operationName names an operation without id as in postUsersId
+func operationName(method, path string) string {
+	b := &strings.Builder{}
+	b.WriteString(strings.ToLower(method))
+	for _, word := range nonWordRegExp.Split(path, -1) {
+		if len(word) > 0 {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+func pad(indent int) string {
+	return strings.Repeat("  ", indent)
+}
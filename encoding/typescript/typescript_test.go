@@ -0,0 +1,126 @@
+package typescript
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/encoding/swagger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaType(t *testing.T) {
+	type params struct {
+		schema *openapi3.SchemaRef
+	}
+	type expected struct {
+		typeScript string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "definition",
+			params:   params{schema: openapi3.NewSchemaRef("#/definitions/User", nil)},
+			expected: expected{typeScript: "User"},
+		},
+		{
+			name:     "nullable integer",
+			params:   params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "integer", Nullable: true})},
+			expected: expected{typeScript: "number | null"},
+		},
+		{
+			name:     "enum",
+			params:   params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string", Enum: []interface{}{"admin", "user"}})},
+			expected: expected{typeScript: `"admin" | "user"`},
+		},
+		{
+			name: "array of an enum",
+			params: params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{
+				Type:  "array",
+				Items: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "integer", Enum: []interface{}{1, 2}}),
+			})},
+			expected: expected{typeScript: "(1 | 2)[]"},
+		},
+		{
+			name: "map",
+			params: params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{
+				Type:                 "object",
+				AdditionalProperties: openapi3.NewSchemaRef("#/definitions/User", nil),
+			})},
+			expected: expected{typeScript: "Record<string, User>"},
+		},
+		{
+			name:     "untyped value",
+			params:   params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{})},
+			expected: expected{typeScript: "unknown"},
+		},
+		{
+			name: "array of inline objects",
+			params: params{schema: openapi3.NewSchemaRef("", &openapi3.Schema{
+				Type: "array",
+				Items: openapi3.NewSchemaRef("", &openapi3.Schema{
+					Type:       "object",
+					Required:   []string{"nick"},
+					Properties: map[string]*openapi3.SchemaRef{"nick": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string", Nullable: true})},
+				}),
+			})},
+			expected: expected{typeScript: "{\n  nick: string | null;\n}[]"},
+		},
+	}
+	tw := &writer{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.typeScript, tw.schemaType(test.params.schema, 0))
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	doc := openapi2.Swagger{
+		Paths: map[string]*openapi2.PathItem{
+			"/users/{id}": {
+				Get: &openapi2.Operation{
+					Parameters: openapi2.Parameters{
+						{Name: "id", In: "path", Type: "integer", Required: true},
+						{Name: "fields", In: "query", Type: "array", Items: openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string"})},
+					},
+					Responses: map[string]*openapi2.Response{
+						"200": {Description: "OK", Schema: openapi3.NewSchemaRef("#/definitions/User", nil)},
+						"204": {Description: "No Content"},
+					},
+				},
+			},
+		},
+		Definitions: map[string]*openapi3.SchemaRef{
+			"User": openapi3.NewSchemaRef("", &openapi3.Schema{
+				Type:        "object",
+				Description: "Is a user",
+				Required:    []string{"first-name"},
+				Properties: map[string]*openapi3.SchemaRef{
+					"first-name": openapi3.NewSchemaRef("", &openapi3.Schema{Type: "string"}),
+					"age": openapi3.NewSchemaRef("", &openapi3.Schema{
+						Type:           "integer",
+						ExtensionProps: openapi3.ExtensionProps{Extensions: map[string]interface{}{"x-deprecated": true}},
+					}),
+				},
+			}),
+		},
+	}
+	buf := &bytes.Buffer{}
+	err := Marshal(doc, swagger.Extensions{}, buf)
+	assert.Nil(t, err)
+	out := buf.String()
+	expected := []string{
+		"/** Is a user */\nexport interface User {\n  /** @deprecated */\n  age?: number;\n  \"first-name\": string;\n}\n",
+		"  getUsersId: {\n    path: {\n      id: number;\n    };\n    query?: {\n      fields?: string[];\n    };\n  };\n",
+		"  getUsersId: {\n    /** OK */\n    200: User;\n    /** No Content */\n    204: void;\n  };\n",
+		"  getUsersId: { method: \"GET\", path: \"/users/{id}\" },\n",
+	}
+	for _, e := range expected {
+		assert.Contains(t, out, e)
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
+const testFileSuffix = "_test.go"
+
 // IsGoFile returns true if a file name is a go file
 func IsGoFile(fileName string) bool {
 	return strings.HasSuffix(fileName, ".go")
@@ -25,6 +29,30 @@ func ListGoFilesRecursively(dir string, blacklist []*regexp.Regexp) ([]string, e
 	return files, err
 }
 
+// ListGoTestFilesRecursively returns a list of go test files in a directory recursively, a test
+// file is ignored when the blacklist ignores the file it tests, as user.go for user_test.go,
+// so a blacklist that ignores every test file still ignores the directories it lists
+func ListGoTestFilesRecursively(dir string, blacklist []*regexp.Regexp) ([]string, error) {
+	files := make([]string, 0)
+	err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && isListedTestFile(filePath, blacklist) {
+			files = append(files, filePath)
+		}
+		return err
+	})
+	return files, err
+}
+
+// isListedTestFile returns true for a test file whose tested file is not ignored by the blacklist
+func isListedTestFile(filePath string, blacklist []*regexp.Regexp) bool {
+	return IsGoTestFile(filepath.Base(filePath)) && !shouldIgnore(strings.TrimSuffix(filePath, testFileSuffix)+".go", blacklist)
+}
+
+// IsGoTestFile returns true if a file name is a go test file
+func IsGoTestFile(fileName string) bool {
+	return strings.HasSuffix(fileName, testFileSuffix)
+}
+
 // ListGoFiles list all files in directory
 func ListGoFiles(dir string, blacklist []*regexp.Regexp) ([]string, error) {
 	files := make([]string, 0)
@@ -40,6 +68,51 @@ func ListGoFiles(dir string, blacklist []*regexp.Regexp) ([]string, error) {
 	return files, err
 }
 
+// Stamp is the modification time and the size of a file
+type Stamp struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// Equal returns true when two stamps have the same modification time and size
+func (s Stamp) Equal(other Stamp) bool {
+	return s.ModTime.Equal(other.ModTime) && s.Size == other.Size
+}
+
+// StampGoFilesRecursively returns the stamp of every go file in a directory recursively, the
+// test files are stamped when ListGoTestFilesRecursively lists them even if the blacklist ignores them
+func StampGoFilesRecursively(dir string, blacklist []*regexp.Regexp) (map[string]Stamp, error) {
+	stamps := make(map[string]Stamp)
+	err := filepath.Walk(dir, func(filePath string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && IsGoFile(info.Name()) && (!shouldIgnore(filePath, blacklist) || isListedTestFile(filePath, blacklist)) {
+			stamps[filePath] = Stamp{ModTime: info.ModTime(), Size: info.Size()}
+		}
+		return err
+	})
+	return stamps, err
+}
+
+// ChangedDirs returns the directories of the files added, removed or modified between two stamps
+func ChangedDirs(before, after map[string]Stamp) []string {
+	changed := make(map[string]bool)
+	for filePath, stamp := range after {
+		if previous, ok := before[filePath]; !ok || !previous.Equal(stamp) {
+			changed[filepath.Dir(filePath)] = true
+		}
+	}
+	for filePath := range before {
+		if _, ok := after[filePath]; !ok {
+			changed[filepath.Dir(filePath)] = true
+		}
+	}
+	dirs := make([]string, 0, len(changed))
+	for dir := range changed {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
 func shouldIgnore(filePath string, blacklist []*regexp.Regexp) bool {
 	for _, r := range blacklist {
 		if r.MatchString(filePath) {
@@ -0,0 +1,102 @@
+package folder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStampGoFilesRecursively(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := []string{
+		"main.go",
+		"README.md",
+		"user/user.go",
+		"user/user_test.go",
+		"generated/models.go",
+		"generated/models_test.go",
+	}
+	for _, name := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte("package "+filepath.Base(filepath.Dir(filePath))), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	blacklist := []*regexp.Regexp{regexp.MustCompile(`_test\.go$`), regexp.MustCompile(`/generated/`)}
+	stamps, err := StampGoFilesRecursively(dir, blacklist)
+	assert.Nil(t, err)
+	names := make([]string, 0, len(stamps))
+	for filePath, stamp := range stamps {
+		name, err := filepath.Rel(dir, filePath)
+		assert.Nil(t, err)
+		names = append(names, filepath.ToSlash(name))
+		assert.NotZero(t, stamp.Size)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"main.go", "user/user.go", "user/user_test.go"}, names, "the test files of the files that are not ignored must be stamped")
+}
+
+func TestChangedDirs(t *testing.T) {
+	now := time.Now()
+	before := map[string]Stamp{
+		"/project/main.go":           {ModTime: now, Size: 10},
+		"/project/user/user.go":      {ModTime: now, Size: 20},
+		"/project/user/user_test.go": {ModTime: now, Size: 30},
+		"/project/auth/auth.go":      {ModTime: now, Size: 40},
+	}
+	type params struct {
+		after map[string]Stamp
+	}
+	type expected struct {
+		dirs []string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should not return a directory when nothing changed",
+			params:   params{after: before},
+			expected: expected{dirs: []string{}},
+		},
+		{
+			name: "should return the directories of the modified files",
+			params: params{after: map[string]Stamp{
+				"/project/main.go":           {ModTime: now, Size: 10},
+				"/project/user/user.go":      {ModTime: now, Size: 20},
+				"/project/user/user_test.go": {ModTime: now.Add(time.Second), Size: 30},
+				"/project/auth/auth.go":      {ModTime: now, Size: 41},
+			}},
+			expected: expected{dirs: []string{"/project/auth", "/project/user"}},
+		},
+		{
+			name: "should return the directories of the added and removed files",
+			params: params{after: map[string]Stamp{
+				"/project/main.go":           {ModTime: now, Size: 10},
+				"/project/user/user.go":      {ModTime: now, Size: 20},
+				"/project/user/user_test.go": {ModTime: now, Size: 30},
+				"/project/admin/admin.go":    {ModTime: now, Size: 50},
+			}},
+			expected: expected{dirs: []string{"/project/admin", "/project/auth"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.dirs, ChangedDirs(before, test.params.after))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package swago
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/javiercbk/swago/criteria"
+	"github.com/stretchr/testify/assert"
+)
+
+const handlersCriteria = `
+routes:
+  - funcRoute:
+      pkg: router
+      funcName: GET
+      pathIndex: 0
+      handlerIndex: 1
+`
+
+var handlersProject = map[string]string{
+	"go.mod": "module example.com/api\n",
+	"main.go": `package main
+
+import (
+	"example.com/api/router"
+	"example.com/api/v1/handler"
+)
+
+func main() {
+	users := &handler.Users{}
+	admins := &handler.Admins{}
+	router.GET("/v1/users", users.List)
+	router.GET("/v1/admins", admins.List)
+	router.GET("/v1/health", handler.Health)
+	registerV2()
+}
+`,
+	"v2.go": `package main
+
+import (
+	"example.com/api/router"
+	"example.com/api/v2/handler"
+)
+
+func registerV2() {
+	users := handler.Users{}
+	router.GET("/v2/users", users.List)
+}
+`,
+	"router/router.go": `package router
+
+import "net/http"
+
+func GET(path string, handler http.HandlerFunc) {}
+`,
+	"v1/handler/handler.go": `package handler
+
+import "net/http"
+
+type Users struct{}
+
+type Admins struct{}
+
+func (a *Admins) List(w http.ResponseWriter, r *http.Request) {}
+
+func (u *Users) List(w http.ResponseWriter, r *http.Request) {}
+
+func Health(w http.ResponseWriter, r *http.Request) {}
+`,
+	"v2/handler/handler.go": `package handler
+
+import "net/http"
+
+type Users struct{}
+
+func (u Users) List(w http.ResponseWriter, r *http.Request) {}
+`,
+}
+
+func TestFindHandlers(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	root := writeTestProject(t, handlersProject)
+	defer os.RemoveAll(root)
+	c := criteria.Criteria{}
+	if err := criteria.NewCriteriaDecoder(logger).ParseCriteriaFromYAML(strings.NewReader(handlersCriteria), &c); err != nil {
+		t.Fatal(err)
+	}
+	sg, err := NewSwaggerGeneratorWithBlacklist(root, root, nil, logger, []*regexp.Regexp{regexp.MustCompile(`_test\.go$`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes, err := sg.FindRoutes(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type expected struct {
+		handlerType string
+		memberOf    string
+		dir         string
+	}
+	handlers := make(map[string]expected)
+	for _, r := range routes {
+		if assert.NotNil(t, r.Handler, r.Path) {
+			dir, _ := filepath.Rel(root, filepath.Dir(r.Handler.File.Name))
+			handlers[r.Path] = expected{handlerType: r.HandlerType, memberOf: r.Handler.MemberOf, dir: filepath.ToSlash(dir)}
+		}
+	}
+	assert.Equal(t, map[string]expected{
+		"/v1/users":  {handlerType: "handler.Users.List", memberOf: "*Users", dir: "v1/handler"},
+		"/v1/admins": {handlerType: "handler.Admins.List", memberOf: "*Admins", dir: "v1/handler"},
+		"/v1/health": {handlerType: "handler.Health", dir: "v1/handler"},
+		"/v2/users":  {handlerType: "handler.Users.List", memberOf: "Users", dir: "v2/handler"},
+	}, handlers)
+	assert.Equal(t, 0, sg.Diagnostics.Len())
+}
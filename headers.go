@@ -0,0 +1,79 @@
+package swago
+
+import (
+	"net/textproto"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/pkg"
+)
+
+const (
+	defaultHeaderType = "string"
+)
+
+// responseHeaders returns the headers of a response described by the criteria
+func responseHeaders(names []string, responseHeaders []criteria.ResponseHeader) map[string]*openapi2.Header {
+	if len(names) == 0 {
+		return nil
+	}
+	headers := make(map[string]*openapi2.Header, len(names))
+	for _, name := range names {
+		headers[name] = responseHeader(name, responseHeaders)
+	}
+	return headers
+}
+
+func responseHeader(name string, responseHeaders []criteria.ResponseHeader) *openapi2.Header {
+	header := &openapi2.Header{
+		Type: defaultHeaderType,
+	}
+	for _, rh := range responseHeaders {
+		if textproto.CanonicalMIMEHeaderKey(rh.Name) == textproto.CanonicalMIMEHeaderKey(name) {
+			header.Description = rh.Description
+			if len(rh.Type) > 0 {
+				header.Type = rh.Type
+			}
+			break
+		}
+	}
+	return header
+}
+
+// addMiddlewareHeaders adds the headers set by the middlewares of a route to its responses
+func addMiddlewareHeaders(r pkg.Route, responses map[string]*openapi2.Response, responseHeaders []criteria.ResponseHeader) {
+	for _, rh := range responseHeaders {
+		if rh.MiddlewareRegExp == nil || !usesMiddleware(r, rh.MiddlewareRegExp.MatchString) {
+			continue
+		}
+		for code, resp := range responses {
+			if len(resp.Ref) > 0 || (len(rh.Codes) > 0 && !containsString(rh.Codes, code)) {
+				continue
+			}
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]*openapi2.Header)
+			}
+			if _, ok := resp.Headers[rh.Name]; !ok {
+				resp.Headers[rh.Name] = responseHeader(rh.Name, responseHeaders)
+			}
+		}
+	}
+}
+
+func usesMiddleware(r pkg.Route, matches func(string) bool) bool {
+	for _, m := range r.Middlewares {
+		if matches(m) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package swago
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+var testResponseHeaders = []criteria.ResponseHeader{
+	{Name: "Location", Description: "URL of the resource created"},
+	{Name: "X-RateLimit-Remaining", Type: "integer", Description: "Requests left", MiddlewareRegExp: regexp.MustCompile(`^middleware\.RateLimit`)},
+	{Name: "X-Total-Count", Type: "integer", MiddlewareRegExp: regexp.MustCompile(`^middleware\.Paginate`), Codes: []string{"200"}},
+}
+
+func TestResponseHeaders(t *testing.T) {
+	type params struct {
+		names []string
+	}
+	type expected struct {
+		headers map[string]*openapi2.Header
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should not document headers when none is set",
+			params:   params{},
+			expected: expected{},
+		},
+		{
+			name:   "should match the criteria header whatever the case of its name is",
+			params: params{names: []string{"location"}},
+			expected: expected{headers: map[string]*openapi2.Header{
+				"location": {Type: "string", Description: "URL of the resource created"},
+			}},
+		},
+		{
+			name:   "should take the type of the criteria header",
+			params: params{names: []string{"X-RateLimit-Remaining"}},
+			expected: expected{headers: map[string]*openapi2.Header{
+				"X-RateLimit-Remaining": {Type: "integer", Description: "Requests left"},
+			}},
+		},
+		{
+			name:   "should document a header without a criteria as a string",
+			params: params{names: []string{"X-Request-Id"}},
+			expected: expected{headers: map[string]*openapi2.Header{
+				"X-Request-Id": {Type: "string"},
+			}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.headers, responseHeaders(test.params.names, testResponseHeaders))
+		})
+	}
+}
+
+func TestAddMiddlewareHeaders(t *testing.T) {
+	type params struct {
+		middlewares []string
+	}
+	type expected struct {
+		headers map[string][]string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:   "should not add headers without a matching middleware",
+			params: params{middlewares: []string{"middleware.Logger()"}},
+			expected: expected{headers: map[string][]string{
+				"200": {"Location"},
+				"400": {},
+			}},
+		},
+		{
+			name:   "should add the headers of a middleware to every response",
+			params: params{middlewares: []string{"middleware.RateLimit(100)"}},
+			expected: expected{headers: map[string][]string{
+				"200": {"Location", "X-RateLimit-Remaining"},
+				"400": {"X-RateLimit-Remaining"},
+			}},
+		},
+		{
+			name:   "should add the headers of a middleware only to its codes",
+			params: params{middlewares: []string{"middleware.Paginate()"}},
+			expected: expected{headers: map[string][]string{
+				"200": {"Location", "X-Total-Count"},
+				"400": {},
+			}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			responses := map[string]*openapi2.Response{
+				"200": {Headers: map[string]*openapi2.Header{"Location": {Type: "string"}}},
+				"400": {},
+				"500": {Ref: "#/responses/InternalServerError"},
+			}
+			addMiddlewareHeaders(pkg.Route{Middlewares: test.params.middlewares}, responses, testResponseHeaders)
+			assert.Nil(t, responses["500"].Headers, "referenced responses are not modified")
+			headers := make(map[string][]string)
+			for code, resp := range responses {
+				if len(resp.Ref) > 0 {
+					continue
+				}
+				names := make([]string, 0, len(resp.Headers))
+				for name := range resp.Headers {
+					names = append(names, name)
+				}
+				headers[code] = names
+			}
+			for code, names := range test.expected.headers {
+				assert.ElementsMatch(t, names, headers[code], code)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+const (
+	// AnnotationPrefix is the prefix of every swago annotation in a doc comment
+	AnnotationPrefix = "swago:"
+	// AnnotationTags overrides the tags of an operation: swago:tags users admin
+	AnnotationTags = "tags"
+	// AnnotationOperationID overrides the operationId of an operation: swago:operationId listUsers
+	AnnotationOperationID = "operationId"
+	// AnnotationDeprecated marks an operation as deprecated: swago:deprecated
+	AnnotationDeprecated = "deprecated"
+	// AnnotationResponse adds a response to an operation: swago:response 404 pkg.ErrorBody description
+	AnnotationResponse = "response"
+	// AnnotationParam adds a parameter to an operation: swago:param query page int required description
+	AnnotationParam = "param"
+	// AnnotationSecurity sets the security of an operation: swago:security jwt scope1 scope2
+	AnnotationSecurity = "security"
+	// AnnotationIgnore excludes an operation from the documentation: swago:ignore
+	AnnotationIgnore = "ignore"
+)
+
+// Annotation is a swago directive found in a doc comment. An annotation starts
+// with "swago:" followed by its name and its arguments are the rest of the line.
+type Annotation struct {
+	Name string
+	Args []string
+	// Pos is where the annotation starts in the doc comment, it is only set when the
+	// annotations are parsed from a declaration
+	Pos token.Pos
+}
+
+// ParseAnnotations extracts every annotation from a doc comment and returns
+// the doc comment without them
+func ParseAnnotations(doc string) ([]Annotation, string) {
+	annotations := make([]Annotation, 0)
+	lines := strings.Split(doc, "\n")
+	cleanLines := make([]string, 0, len(lines))
+	for _, line := range lines {
+		idx := annotationIndex(line)
+		if idx == -1 {
+			cleanLines = append(cleanLines, line)
+			continue
+		}
+		fields := strings.Fields(line[idx+len(AnnotationPrefix):])
+		if len(fields) > 0 {
+			annotations = append(annotations, Annotation{
+				Name: fields[0],
+				Args: fields[1:],
+			})
+		}
+		text := strings.TrimRight(line[:idx], " \t")
+		if len(text) > 0 {
+			cleanLines = append(cleanLines, text)
+		}
+	}
+	return annotations, strings.TrimSpace(strings.Join(cleanLines, "\n"))
+}
+
+// setAnnotationPositions sets the position of the annotations parsed from a doc comment, the
+// annotations are found in the same order in the comments of the group
+func setAnnotationPositions(doc *ast.CommentGroup, annotations []Annotation) {
+	if doc == nil {
+		return
+	}
+	i := 0
+	for _, c := range doc.List {
+		offset := 0
+		for _, line := range strings.Split(c.Text, "\n") {
+			idx := annotationIndex(line)
+			if idx != -1 && len(strings.Fields(line[idx+len(AnnotationPrefix):])) > 0 {
+				if i == len(annotations) {
+					return
+				}
+				annotations[i].Pos = c.Slash + token.Pos(offset+idx)
+				i++
+			}
+			offset += len(line) + 1
+		}
+	}
+}
+
+// annotationIndex returns the index where an annotation starts in a line or -1
+func annotationIndex(line string) int {
+	offset := 0
+	for {
+		idx := strings.Index(line[offset:], AnnotationPrefix)
+		if idx == -1 {
+			return -1
+		}
+		idx += offset
+		// annotations must be words on their own, so "noswago:tags" is not one
+		if idx == 0 || line[idx-1] == ' ' || line[idx-1] == '\t' {
+			return idx
+		}
+		offset = idx + len(AnnotationPrefix)
+	}
+}
+
+// FindAnnotations returns every annotation with a given name
+func FindAnnotations(annotations []Annotation, name string) []Annotation {
+	found := make([]Annotation, 0)
+	for _, a := range annotations {
+		if a.Name == name {
+			found = append(found, a)
+		}
+	}
+	return found
+}
+
+// HasAnnotation returns true if there is an annotation with a given name
+func HasAnnotation(annotations []Annotation, name string) bool {
+	return len(FindAnnotations(annotations, name)) > 0
+}
@@ -0,0 +1,114 @@
+package pkg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	type params struct {
+		doc string
+	}
+	type expected struct {
+		annotations []Annotation
+		doc         string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should parse the tags",
+			params:   params{doc: "ListUsers lists the users.\nswago:tags users admin\n"},
+			expected: expected{annotations: []Annotation{{Name: AnnotationTags, Args: []string{"users", "admin"}}}, doc: "ListUsers lists the users."},
+		},
+		{
+			name:     "should parse the operation id",
+			params:   params{doc: "swago:operationId listUsers\n"},
+			expected: expected{annotations: []Annotation{{Name: AnnotationOperationID, Args: []string{"listUsers"}}}},
+		},
+		{
+			name:     "should parse a directive with no arguments",
+			params:   params{doc: "swago:deprecated\n"},
+			expected: expected{annotations: []Annotation{{Name: AnnotationDeprecated, Args: []string{}}}},
+		},
+		{
+			name:   "should parse a response with a type and a description",
+			params: params{doc: "swago:response 404 handler.ErrorBody the user does not exist\n"},
+			expected: expected{annotations: []Annotation{{
+				Name: AnnotationResponse,
+				Args: []string{"404", "handler.ErrorBody", "the", "user", "does", "not", "exist"},
+			}}},
+		},
+		{
+			name:   "should parse a parameter",
+			params: params{doc: "swago:param query page int required the page\n"},
+			expected: expected{annotations: []Annotation{{
+				Name: AnnotationParam,
+				Args: []string{"query", "page", "int", "required", "the", "page"},
+			}}},
+		},
+		{
+			name:   "should parse the security alternatives",
+			params: params{doc: "swago:security jwt users:read | apiKey\nswago:security none\n"},
+			expected: expected{annotations: []Annotation{
+				{Name: AnnotationSecurity, Args: []string{"jwt", "users:read", "|", "apiKey"}},
+				{Name: AnnotationSecurity, Args: []string{"none"}},
+			}},
+		},
+		{
+			name:     "should parse ignore",
+			params:   params{doc: "Health is not documented.\n\nswago:ignore\n"},
+			expected: expected{annotations: []Annotation{{Name: AnnotationIgnore, Args: []string{}}}, doc: "Health is not documented."},
+		},
+		{
+			name:     "should keep the text before an annotation",
+			params:   params{doc: "GetUser returns a user. swago:tags users\n"},
+			expected: expected{annotations: []Annotation{{Name: AnnotationTags, Args: []string{"users"}}}, doc: "GetUser returns a user."},
+		},
+		{
+			name:     "should ignore annotations that are not words on their own",
+			params:   params{doc: "GetUser returns a user, noswago:tags users\n"},
+			expected: expected{annotations: []Annotation{}, doc: "GetUser returns a user, noswago:tags users"},
+		},
+		{
+			name:     "should ignore an annotation without a name",
+			params:   params{doc: "GetUser returns a user.\nswago:\n"},
+			expected: expected{annotations: []Annotation{}, doc: "GetUser returns a user."},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			annotations, doc := ParseAnnotations(test.params.doc)
+			assert.Equal(t, test.expected.annotations, annotations)
+			assert.Equal(t, test.expected.doc, doc)
+		})
+	}
+}
+
+const annotationsSrc = `package handler
+
+// GetUser returns a user.
+// swago:tags users
+/* swago:response 404 handler.ErrorBody
+   swago:deprecated */
+func GetUser() {}
+`
+
+func TestSetAnnotationPositions(t *testing.T) {
+	file := parseTestFile(t, annotationsSrc)
+	f := file.Functions[0]
+	positions := make([]string, 0, len(f.Annotations))
+	for _, a := range f.Annotations {
+		pos := f.PositionOf(a.Pos)
+		positions = append(positions, fmt.Sprintf("%s %s:%d:%d", a.Name, pos.Filename, pos.Line, pos.Column))
+	}
+	assert.Equal(t, []string{
+		"tags handler.go:4:4",
+		"response handler.go:5:4",
+		"deprecated handler.go:6:4",
+	}, positions)
+}
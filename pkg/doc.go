@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	deprecatedPrefix = "Deprecated: "
+	// DeprecatedExtension marks a schema as deprecated
+	DeprecatedExtension = "x-deprecated"
+)
+
+// DocText returns a doc comment without the name of the documented
+// declaration, as in "V1Handler1 is a sample handler" is "Is a sample handler"
+func DocText(name, doc string) string {
+	doc = strings.TrimSpace(doc)
+	if len(name) > 0 && strings.HasPrefix(doc, name+" ") {
+		doc = upperFirst(strings.TrimSpace(doc[len(name):]))
+	}
+	return doc
+}
+
+// IsDeprecated returns true if a doc comment has a paragraph starting with "Deprecated: "
+func IsDeprecated(doc string) bool {
+	for _, paragraph := range strings.Split(doc, "\n\n") {
+		if strings.HasPrefix(strings.TrimSpace(paragraph), deprecatedPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func upperFirst(str string) string {
+	if len(str) == 0 {
+		return str
+	}
+	r, size := utf8.DecodeRuneInString(str)
+	return string(unicode.ToUpper(r)) + str[size:]
+}
+
+func lowerFirst(str string) string {
+	if len(str) == 0 {
+		return str
+	}
+	r, size := utf8.DecodeRuneInString(str)
+	return string(unicode.ToLower(r)) + str[size:]
+}
+
+// DocSummary splits a doc comment in a summary, which is its first sentence,
+// and a description, which is the rest of the comment
+func DocSummary(name, doc string) (string, string) {
+	doc = DocText(name, doc)
+	if len(doc) == 0 {
+		return "", ""
+	}
+	end := firstSentenceEnd(doc)
+	summary := strings.Join(strings.Fields(doc[:end]), " ")
+	summary = strings.TrimSuffix(summary, ".")
+	description := strings.TrimSpace(doc[end:])
+	return summary, description
+}
+
+// firstSentenceEnd returns the position where the first sentence of a text
+// ends, a sentence ends with a period followed by a space or with a paragraph
+func firstSentenceEnd(text string) int {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '.':
+			if i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\n' {
+				return i + 1
+			}
+		case '\n':
+			if i+1 < len(text) && text[i+1] == '\n' {
+				return i
+			}
+		}
+	}
+	return len(text)
+}
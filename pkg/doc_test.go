@@ -0,0 +1,139 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocText(t *testing.T) {
+	type params struct {
+		name string
+		doc  string
+	}
+	type expected struct {
+		text string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "name prefix",
+			params:   params{name: "GetUser", doc: "GetUser returns a user.\n"},
+			expected: expected{text: "Returns a user."},
+		},
+		{
+			name:     "name in the middle of the comment",
+			params:   params{name: "GetUser", doc: "Handler of GetUser.\n"},
+			expected: expected{text: "Handler of GetUser."},
+		},
+		{
+			name:     "longer name sharing the prefix",
+			params:   params{name: "Get", doc: "GetUser returns a user."},
+			expected: expected{text: "GetUser returns a user."},
+		},
+		{
+			name:     "no name",
+			params:   params{doc: "  returns a user\n"},
+			expected: expected{text: "returns a user"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.text, DocText(test.params.name, test.params.doc))
+		})
+	}
+}
+
+func TestDocSummary(t *testing.T) {
+	type params struct {
+		name string
+		doc  string
+	}
+	type expected struct {
+		summary     string
+		description string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:   "empty comment",
+			params: params{name: "GetUser"},
+		},
+		{
+			name:     "single sentence",
+			params:   params{name: "GetUser", doc: "GetUser returns a user.\n"},
+			expected: expected{summary: "Returns a user"},
+		},
+		{
+			name:     "first sentence",
+			params:   params{name: "GetUser", doc: "GetUser returns a user. The user must exist.\n"},
+			expected: expected{summary: "Returns a user", description: "The user must exist."},
+		},
+		{
+			name:     "period inside a word",
+			params:   params{name: "GetUser", doc: "GetUser returns a user as in api.User, by id.\n"},
+			expected: expected{summary: "Returns a user as in api.User, by id"},
+		},
+		{
+			name:     "sentence across lines",
+			params:   params{name: "ListUsers", doc: "ListUsers returns the users\nof a team. It is paginated.\n"},
+			expected: expected{summary: "Returns the users of a team", description: "It is paginated."},
+		},
+		{
+			name:     "paragraph without a period",
+			params:   params{name: "ListUsers", doc: "ListUsers returns the users\n\nThe users are sorted by name.\nDeleted users are not listed.\n"},
+			expected: expected{summary: "Returns the users", description: "The users are sorted by name.\nDeleted users are not listed."},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			summary, description := DocSummary(test.params.name, test.params.doc)
+			assert.Equal(t, test.expected.summary, summary)
+			assert.Equal(t, test.expected.description, description)
+		})
+	}
+}
+
+func TestIsDeprecated(t *testing.T) {
+	type params struct {
+		doc string
+	}
+	type expected struct {
+		deprecated bool
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "deprecated paragraph",
+			params:   params{doc: "GetUser returns a user.\n\nDeprecated: use GetAccount instead.\n"},
+			expected: expected{deprecated: true},
+		},
+		{
+			name:     "deprecated comment",
+			params:   params{doc: "Deprecated: use Email instead.\n"},
+			expected: expected{deprecated: true},
+		},
+		{
+			name:   "deprecated in the middle of a paragraph",
+			params: params{doc: "GetUser returns a user.\nDeprecated: not really.\n"},
+		},
+		{
+			name:   "no deprecation",
+			params: params{doc: "GetUser returns a deprecated user.\n"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.deprecated, IsDeprecated(test.params.doc))
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/encoding/swagger"
+	"github.com/javiercbk/swago/folder"
+)
+
+const (
+	testPkgSuffix = "_test"
+)
+
+// LiteralExamples are the values of the fields of composite literals, the keys are
+// a type as in pkg.User and the name of a field
+type LiteralExamples map[string]map[string]interface{}
+
+// FindTestExamples reads the composite literals in the test files of a project, for every
+// type the literal with more fields is kept. A test file is read unless the blacklist
+// ignores the file it tests, the values of the literals are copied verbatim to the examples
+func FindTestExamples(path string, logger *log.Logger, blacklist []*regexp.Regexp) (LiteralExamples, error) {
+	examples := make(LiteralExamples)
+	goFiles, err := folder.ListGoTestFilesRecursively(path, blacklist)
+	if err != nil {
+		return examples, err
+	}
+	for _, goFile := range goFiles {
+		fset := token.NewFileSet()
+		astFile, err := astForFile(goFile, fset)
+		if err != nil {
+			logger.Printf("error reading ast for file %s: %v\n", goFile, err)
+			return examples, err
+		}
+		file := &File{
+			Pkg:  &Pkg{Name: strings.TrimSuffix(readFilePackage(astFile), testPkgSuffix), Path: filepath.Dir(goFile)},
+			FSet: fset,
+			File: astFile,
+		}
+		for _, d := range astFile.Decls {
+			if genDecl, ok := d.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+				extractImport(file, genDecl)
+			}
+		}
+		file.addLiteralExamples(examples)
+	}
+	return examples, nil
+}
+
+func (file *File) addLiteralExamples(examples LiteralExamples) {
+	ast.Inspect(file.File, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || lit.Type == nil {
+			return true
+		}
+		t := flattenType(lit.Type, file.Pkg.Name, file.importMappings)
+		if !strings.Contains(t, ".") || strings.HasPrefix(t, arrayPrefix) {
+			return true
+		}
+		values := make(map[string]interface{})
+		for _, el := range lit.Elts {
+			kv, ok := el.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if value, ok := literalValue(kv.Value); ok {
+				values[key.Name] = value
+			}
+		}
+		if len(values) > len(examples[t]) {
+			examples[t] = values
+		}
+		return true
+	})
+}
+
+// literalValue returns the value of a basic literal, a boolean or a slice of them
+func literalValue(expr ast.Expr) (interface{}, bool) {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		switch x.Kind {
+		case token.INT:
+			v, err := strconv.ParseInt(x.Value, 0, 64)
+			return v, err == nil
+		case token.FLOAT:
+			v, err := strconv.ParseFloat(x.Value, 64)
+			return v, err == nil
+		case token.STRING, token.CHAR:
+			v, err := strconv.Unquote(x.Value)
+			return v, err == nil
+		}
+	case *ast.Ident:
+		if x.Name == "true" || x.Name == "false" {
+			return x.Name == "true", true
+		}
+	case *ast.UnaryExpr:
+		if x.Op == token.SUB {
+			v, ok := literalValue(x.X)
+			switch value := v.(type) {
+			case int64:
+				return -value, ok
+			case float64:
+				return -value, ok
+			}
+		}
+	case *ast.CompositeLit:
+		if _, ok := x.Type.(*ast.ArrayType); !ok {
+			return nil, false
+		}
+		values := make([]interface{}, 0, len(x.Elts))
+		for _, el := range x.Elts {
+			v, ok := literalValue(el)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, v)
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// synthesizedExample returns an example for a schema from its enum, default or format
+func synthesizedExample(sch *openapi3.Schema) interface{} {
+	if len(sch.Enum) > 0 {
+		return sch.Enum[0]
+	}
+	if sch.Default != nil {
+		return sch.Default
+	}
+	if example, ok := swagger.FormatExample(sch.Format); ok && sch.Type == "string" {
+		return example
+	}
+	if sch.Min != nil && (sch.Type == "integer" || sch.Type == "number") {
+		return typedValue(sch.Type, strconv.FormatFloat(*sch.Min, 'f', -1, 64))
+	}
+	return nil
+}
+
+// SchemaExample returns an example of a whole schema built from the examples of its
+// properties and items, it returns nil when no example is found
+func SchemaExample(sch *openapi3.Schema) interface{} {
+	if sch == nil {
+		return nil
+	}
+	if sch.Example != nil {
+		return sch.Example
+	}
+	switch sch.Type {
+	case swaggerObjectType:
+		example := make(map[string]interface{})
+		for name, prop := range sch.Properties {
+			if prop.Value == nil {
+				continue
+			}
+			if v := SchemaExample(prop.Value); v != nil {
+				example[name] = v
+			}
+		}
+		if len(example) > 0 {
+			return example
+		}
+	case "array":
+		if sch.Items != nil {
+			if v := SchemaExample(sch.Items.Value); v != nil {
+				return []interface{}{v}
+			}
+		}
+	}
+	return nil
+}
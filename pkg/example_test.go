@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+)
+
+const examplesSrc = `package handler_test
+
+import (
+	"testing"
+
+	"example.com/api/handler"
+)
+
+func TestCreateUser(t *testing.T) {
+	_ = handler.User{Name: "Jane"}
+	_ = handler.User{Name: "John", Age: 30, Score: -1.5, Admin: true, Tags: []string{"a", "b"}, Friend: &handler.User{}}
+	_ = []handler.User{}
+}
+`
+
+func TestLiteralExamples(t *testing.T) {
+	file := parseTestFile(t, examplesSrc)
+	examples := make(LiteralExamples)
+	file.addLiteralExamples(examples)
+	assert.Equal(t, LiteralExamples{
+		"handler.User": {
+			"Name":  "John",
+			"Age":   int64(30),
+			"Score": -1.5,
+			"Admin": true,
+			"Tags":  []interface{}{"a", "b"},
+		},
+	}, examples)
+}
+
+func TestFindTestExamples(t *testing.T) {
+	dir := writeTestProject(t, map[string]string{
+		"handler/handler_test.go":   examplesSrc,
+		"generated/handler_test.go": strings.Replace(examplesSrc, "John", "Generated", 1),
+		"vendor/lib/lib_test.go":    strings.Replace(examplesSrc, "John", "Vendored", 1),
+	})
+	defer os.RemoveAll(dir)
+	blacklist := []*regexp.Regexp{
+		regexp.MustCompile(`_test\.go$|` + regexp.QuoteMeta(string(os.PathSeparator)) + `generated` + regexp.QuoteMeta(string(os.PathSeparator))),
+		regexp.MustCompile(regexp.QuoteMeta(string(os.PathSeparator)) + `vendor` + regexp.QuoteMeta(string(os.PathSeparator))),
+	}
+	examples, err := FindTestExamples(dir, log.New(ioutil.Discard, "", 0), blacklist)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "John", examples["handler.User"]["Name"])
+	assert.Equal(t, 1, len(examples))
+}
+
+func TestSynthesizedExample(t *testing.T) {
+	min := float64(1)
+	type params struct {
+		sch *openapi3.Schema
+	}
+	type expected struct {
+		example interface{}
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should use the first enum value",
+			params:   params{sch: &openapi3.Schema{Type: "string", Enum: []interface{}{"active", "blocked"}}},
+			expected: expected{example: "active"},
+		},
+		{
+			name:     "should use the format",
+			params:   params{sch: &openapi3.Schema{Type: "string", Format: "date-time"}},
+			expected: expected{example: "2020-01-31T15:04:05Z"},
+		},
+		{
+			name:     "should use the minimum",
+			params:   params{sch: &openapi3.Schema{Type: "integer", Min: &min}},
+			expected: expected{example: int64(1)},
+		},
+		{
+			name:   "should not make up plain values",
+			params: params{sch: &openapi3.Schema{Type: "string"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.example, synthesizedExample(test.params.sch))
+		})
+	}
+}
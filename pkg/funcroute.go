@@ -0,0 +1,630 @@
+package pkg
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/javiercbk/swago/criteria"
+)
+
+const (
+	// maxRouteWalkDepth limits how deep function calls are followed when a router is passed as an argument
+	maxRouteWalkDepth = 8
+)
+
+// routeScope is a router or a group of routes, every route registered in it
+// shares its path prefix and its middlewares
+type routeScope struct {
+	prefix      string
+	middlewares []string
+	// field is true for the scopes held in struct fields, they are shared by every function
+	field bool
+}
+
+func (rs *routeScope) group(prefix string, middlewares []string) *routeScope {
+	return &routeScope{
+		prefix:      rs.prefix + prefix,
+		middlewares: append(append([]string{}, rs.middlewares...), middlewares...),
+	}
+}
+
+// routeEnv holds what is known about the variables of a function being walked
+type routeEnv struct {
+	fun         *Function
+	routers     map[string]*routeScope
+	middlewares map[string]string
+	varTypes    map[string]string
+	depth       int
+	stack       map[*Function]bool
+}
+
+// routeWalker walks every function looking for routes registered with function calls
+type routeWalker struct {
+	project    *Project
+	funcRoutes []criteria.FuncRoute
+	groups     []criteria.RouteGroup
+	called     map[*Function]bool
+	routes     []Route
+	// fields are the routers held in struct fields by type and field name as in handler.Server.router
+	fields map[string]*routeScope
+	// routerFields caches whether a field holds a router
+	routerFields map[string]bool
+	// fieldsResolved is true once every function was walked to find the routers held in fields
+	fieldsResolved bool
+}
+
+// SearchForFuncRoutes searches for routes registered with function calls, as in
+// e.GET("/users", h.listUsers, middleware). Routes inherit the path prefix and the
+// middlewares of the group they are registered in, even when the group is passed
+// to another function or held in a struct field.
+func (p *Project) SearchForFuncRoutes(funcRoutes []criteria.FuncRoute, groups []criteria.RouteGroup) []Route {
+	w := routeWalker{
+		project:      p,
+		funcRoutes:   funcRoutes,
+		groups:       groups,
+		fields:       make(map[string]*routeScope),
+		routerFields: make(map[string]bool),
+	}
+	// the routers assigned to fields are found first, so the routes registered on a field
+	// do not depend on the order of the functions
+	w.walkAll()
+	w.fieldsResolved = true
+	return w.walkAll()
+}
+
+// walkAll walks every function of the project and returns the routes registered
+func (w *routeWalker) walkAll() []Route {
+	p := w.project
+	w.called = make(map[*Function]bool)
+	entryRoutes := make(map[*Function][]Route)
+	entries := make([]*Function, 0)
+	for _, pkg := range p.Pkgs {
+		for i := range pkg.Files {
+			for j := range pkg.Files[i].Functions {
+				fun := &pkg.Files[i].Functions[j]
+				if fun.block == nil {
+					continue
+				}
+				w.routes = make([]Route, 0)
+				env := w.newEnv(fun, 0, map[*Function]bool{})
+				for _, a := range fun.Args {
+					if w.isRouterType(fun.File, a.GoType) {
+						env.routers[a.Name] = &routeScope{}
+					}
+				}
+				w.walk(env)
+				entryRoutes[fun] = w.routes
+				entries = append(entries, fun)
+			}
+		}
+	}
+	routes := make([]Route, 0)
+	for _, fun := range entries {
+		// functions that receive a router from another function were already
+		// walked with the prefix and middlewares of the caller
+		if !w.called[fun] {
+			routes = append(routes, entryRoutes[fun]...)
+		}
+	}
+	return routes
+}
+
+func (w *routeWalker) newEnv(fun *Function, depth int, stack map[*Function]bool) *routeEnv {
+	env := &routeEnv{
+		fun:         fun,
+		routers:     make(map[string]*routeScope),
+		middlewares: make(map[string]string),
+		varTypes:    make(map[string]string),
+		depth:       depth,
+		stack:       stack,
+	}
+	for _, a := range fun.Args {
+		env.varTypes[a.Name] = strings.TrimPrefix(a.GoType, "*")
+	}
+	if len(fun.Receiver) > 0 {
+		env.varTypes[fun.Receiver] = fun.File.Pkg.Name + "." + strings.TrimPrefix(fun.MemberOf, "*")
+	}
+	return env
+}
+
+// isRouterType returns true if a type of a file belongs to a package that registers routes
+func (w *routeWalker) isRouterType(file *File, goType string) bool {
+	pkgName, _ := TypeParts(strings.TrimPrefix(goType, "*"))
+	if len(pkgName) == 0 {
+		return false
+	}
+	return w.isRouterPkg(file, pkgName)
+}
+
+// isRouterPkg returns true if a package imported in a file registers routes
+func (w *routeWalker) isRouterPkg(file *File, pkgName string) bool {
+	for _, fr := range w.funcRoutes {
+		if matchesPkg(file, pkgName, fr.Pkg) {
+			return true
+		}
+	}
+	for _, g := range w.groups {
+		if matchesPkg(file, pkgName, g.Pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPkg returns true if a package imported in a file with a name is the package of a
+// criteria, which is either the name of the package or its import path
+func matchesPkg(file *File, name, criteriaPkg string) bool {
+	if strings.Contains(criteriaPkg, "/") {
+		return file.ImportPath(name) == criteriaPkg
+	}
+	if mapped, ok := file.importMappings[name]; ok {
+		name = mapped
+	}
+	return name == criteriaPkg
+}
+
+func (w *routeWalker) walk(env *routeEnv) {
+	env.stack[env.fun] = true
+	defer delete(env.stack, env.fun)
+	ast.Inspect(env.fun.block, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			w.assign(env, x)
+		case *ast.CallExpr:
+			w.call(env, x)
+		}
+		return true
+	})
+}
+
+// assign tracks routers, groups, middlewares and the type of the assigned variables
+func (w *routeWalker) assign(env *routeEnv, assign *ast.AssignStmt) {
+	if len(assign.Lhs) == 0 || len(assign.Rhs) != 1 {
+		return
+	}
+	if field, ok := w.routerField(env, assign.Lhs[0]); ok {
+		if w.fieldsResolved {
+			return
+		}
+		if scope := w.assignedScope(env, assign.Rhs[0]); scope != nil {
+			scope.field = true
+			w.fields[field] = scope
+		}
+		return
+	}
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return
+	}
+	if scope := w.assignedScope(env, assign.Rhs[0]); scope != nil {
+		env.routers[ident.Name] = scope
+		return
+	}
+	file := env.fun.File
+	switch rhs := assign.Rhs[0].(type) {
+	case *ast.CallExpr:
+		env.middlewares[ident.Name] = w.middlewareExpr(env, rhs)
+		callee := w.resolveCallee(env, rhs)
+		if callee != nil && len(callee.Return) > 0 {
+			env.varTypes[ident.Name] = strings.TrimPrefix(callee.Return[0], "*")
+		}
+	case *ast.CompositeLit, *ast.UnaryExpr:
+		env.varTypes[ident.Name] = strings.TrimPrefix(flattenType(rhs, file.Pkg.Name, file.importMappings), "*")
+	case *ast.Ident:
+		if mw, ok := env.middlewares[rhs.Name]; ok {
+			env.middlewares[ident.Name] = mw
+		}
+	}
+}
+
+// assignedScope returns the router or the group assigned by an expression, which is a
+// group, a router constructor or another router
+func (w *routeWalker) assignedScope(env *routeEnv, expr ast.Expr) *routeScope {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return w.scopeOf(env, expr)
+	}
+	if scope, g, ok := w.groupCall(env, call); ok {
+		prefix := ""
+		if len(call.Args) > g.PathIndex {
+			prefix = env.fun.File.stringValue(call.Args[g.PathIndex])
+		}
+		middlewares := make([]string, 0)
+		if g.MiddlewareIndex > 0 {
+			middlewares = w.middlewareArgs(env, call.Args, g.MiddlewareIndex)
+		}
+		return scope.group(prefix, middlewares)
+	}
+	if w.isRouterConstructor(env, call) {
+		return &routeScope{}
+	}
+	return nil
+}
+
+// call registers routes, applies middlewares to groups and follows the calls
+// where a router is passed as an argument
+func (w *routeWalker) call(env *routeEnv, call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if ok {
+		scope := w.scopeOf(env, sel.X)
+		for _, fr := range w.funcRoutes {
+			if sel.Sel.Name != fr.FuncName {
+				continue
+			}
+			if scope == nil && !w.isPkgIdent(env, sel.X, fr.Pkg) {
+				continue
+			}
+			if scope == nil {
+				scope = &routeScope{}
+			}
+			w.addRoute(env, call, scope, fr)
+			return
+		}
+		if scope != nil {
+			for _, g := range w.groups {
+				if len(g.UseFuncName) > 0 && sel.Sel.Name == g.UseFuncName {
+					// the middlewares of the fields were applied while resolving them
+					if !scope.field || !w.fieldsResolved {
+						scope.middlewares = append(scope.middlewares, w.middlewareArgs(env, call.Args, 0)...)
+					}
+					return
+				}
+			}
+		}
+	}
+	if env.depth >= maxRouteWalkDepth {
+		return
+	}
+	// routers are passed as variables, fields or groups as in register(e.Group("/api"))
+	scopes := make([]*routeScope, len(call.Args))
+	passesRouter := false
+	for i, a := range call.Args {
+		scopes[i] = w.assignedScope(env, a)
+		passesRouter = passesRouter || scopes[i] != nil
+	}
+	if !passesRouter {
+		return
+	}
+	callee := w.resolveCallee(env, call)
+	if callee == nil || callee.block == nil || env.stack[callee] {
+		return
+	}
+	w.called[callee] = true
+	calleeEnv := w.newEnv(callee, env.depth+1, env.stack)
+	for i, param := range callee.Args {
+		if i >= len(call.Args) {
+			break
+		}
+		if scopes[i] != nil {
+			calleeEnv.routers[param.Name] = scopes[i]
+		} else {
+			calleeEnv.middlewares[param.Name] = w.middlewareExpr(env, call.Args[i])
+		}
+	}
+	w.walk(calleeEnv)
+}
+
+func (w *routeWalker) addRoute(env *routeEnv, call *ast.CallExpr, scope *routeScope, fr criteria.FuncRoute) {
+	file := env.fun.File
+	path := ""
+	if len(call.Args) > fr.PathIndex {
+		path = env.fun.File.stringValue(call.Args[fr.PathIndex])
+	}
+	path = scope.prefix + path
+	if fr.NamedPathVarExtractorRegexp != nil {
+		// swagger paths declare their variables as {name}
+		path = fr.NamedPathVarExtractorRegexp.ReplaceAllString(path, "{${1}}")
+	}
+	httpMethod := fr.HTTPMethod
+	if len(httpMethod) == 0 {
+		httpMethod = fr.FuncName
+	}
+	route := Route{
+		Pkg:                        file.Pkg.Name,
+		File:                       file.Name,
+		Position:                   file.FSet.Position(call.Pos()),
+		Criteria:                   "funcRoute " + fr.Pkg + "." + fr.FuncName,
+		Path:                       path,
+		HTTPMethod:                 criteria.MatchHTTPMethod(httpMethod),
+		NamedPathVarExtractor:      criteria.DefaultURLNamedPathVarExtractor,
+		Middlewares:                append([]string{}, scope.middlewares...),
+		MatchedParameters:          make(map[string]bool),
+		MatchedSecurityDefinitions: make(map[string]bool),
+	}
+	if fr.MiddlewareIndex > 0 {
+		route.Middlewares = append(route.Middlewares, w.middlewareArgs(env, call.Args, fr.MiddlewareIndex)...)
+	}
+	if len(call.Args) > fr.HandlerIndex {
+		route.HandlerType = w.handlerType(env, call.Args[fr.HandlerIndex])
+		route.HandlerPkgPath = w.project.handlerPkgPath(env.fun.File, route.HandlerType)
+	}
+	w.routes = append(w.routes, route)
+}
+
+// groupCall returns the scope and the group criteria of a call that creates a group
+func (w *routeWalker) groupCall(env *routeEnv, call *ast.CallExpr) (*routeScope, criteria.RouteGroup, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, criteria.RouteGroup{}, false
+	}
+	scope := w.scopeOf(env, sel.X)
+	if scope == nil {
+		return nil, criteria.RouteGroup{}, false
+	}
+	for _, g := range w.groups {
+		if sel.Sel.Name == g.FuncName {
+			return scope, g, true
+		}
+	}
+	return nil, criteria.RouteGroup{}, false
+}
+
+// isRouterConstructor returns true for calls to a function of a package that registers
+// routes, as in echo.New()
+func (w *routeWalker) isRouterConstructor(env *routeEnv, call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if _, isVar := env.varTypes[x.Name]; isVar {
+		return false
+	}
+	if _, imported := env.fun.File.importMappings[x.Name]; !imported {
+		return false
+	}
+	return w.isRouterPkg(env.fun.File, x.Name)
+}
+
+// scopeOf returns the router of a variable or of a field as in s.router
+func (w *routeWalker) scopeOf(env *routeEnv, expr ast.Expr) *routeScope {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return env.routers[x.Name]
+	case *ast.SelectorExpr:
+		field, ok := w.routerField(env, x)
+		if !ok {
+			return nil
+		}
+		scope, ok := w.fields[field]
+		if !ok {
+			// a router field that is never assigned is set outside the project
+			scope = &routeScope{field: true}
+			w.fields[field] = scope
+		}
+		return scope
+	}
+	return nil
+}
+
+// routerField returns the type and the name of a field that holds a router as in
+// handler.Server.router, the field is either assigned a router or declared as one
+func (w *routeWalker) routerField(env *routeEnv, expr ast.Expr) (string, bool) {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	varType, isVar := env.varTypes[x.Name]
+	if !isVar {
+		return "", false
+	}
+	field := varType + "." + sel.Sel.Name
+	if _, ok := w.fields[field]; ok {
+		return field, true
+	}
+	isRouter, cached := w.routerFields[field]
+	if !cached {
+		isRouter = w.isRouterField(env.fun.File, varType, sel.Sel.Name)
+		w.routerFields[field] = isRouter
+	}
+	return field, isRouter
+}
+
+// isRouterField returns true if a field of a struct is declared as a router
+func (w *routeWalker) isRouterField(file *File, structType, fieldName string) bool {
+	pkgName, typeName := TypeParts(structType)
+	for _, p := range w.project.pkgsNamed(file, pkgName) {
+		str := Struct{PkgName: pkgName, Name: typeName}
+		if p.FindStruct(&str) != nil {
+			continue
+		}
+		for _, f := range str.Fields {
+			if f.Name == fieldName {
+				return w.isRouterType(str.File, f.Type)
+			}
+		}
+	}
+	return false
+}
+
+// isPkgIdent returns true if an expression is an imported package named pkgName
+func (w *routeWalker) isPkgIdent(env *routeEnv, expr ast.Expr, pkgName string) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if _, isVar := env.varTypes[ident.Name]; isVar {
+		return false
+	}
+	_, ok = env.fun.File.importMappings[ident.Name]
+	return ok && matchesPkg(env.fun.File, ident.Name, pkgName)
+}
+
+// middlewareArgs returns the middleware expressions of the arguments from an index
+func (w *routeWalker) middlewareArgs(env *routeEnv, args []ast.Expr, from int) []string {
+	middlewares := make([]string, 0)
+	for i := from; i < len(args); i++ {
+		middlewares = append(middlewares, w.middlewareExpr(env, args[i]))
+	}
+	return middlewares
+}
+
+// middlewareExpr returns the expression that creates a middleware, variables
+// are replaced by the expression that was assigned to them
+func (w *routeWalker) middlewareExpr(env *routeEnv, expr ast.Expr) string {
+	file := env.fun.File
+	switch x := expr.(type) {
+	case *ast.Ident:
+		if mw, ok := env.middlewares[x.Name]; ok {
+			return mw
+		}
+		return x.Name
+	case *ast.CallExpr:
+		args := make([]string, 0, len(x.Args))
+		for _, a := range x.Args {
+			args = append(args, w.middlewareExpr(env, a))
+		}
+		return flattenType(x.Fun, file.Pkg.Name, file.importMappings) + "(" + strings.Join(args, ", ") + ")"
+	case *ast.SelectorExpr:
+		if w.isPkgIdent(env, x.X, rawFlattenType(x.X, file.importMappings)) {
+			return flattenType(x, file.Pkg.Name, file.importMappings)
+		}
+		return printExpr(file.FSet, x)
+	default:
+		return printExpr(file.FSet, x)
+	}
+}
+
+// handlerType returns the package and name of a route handler
+func (w *routeWalker) handlerType(env *routeEnv, expr ast.Expr) string {
+	file := env.fun.File
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return file.Pkg.Name + "." + x.Name
+	case *ast.SelectorExpr:
+		if ident, ok := x.X.(*ast.Ident); ok {
+			if varType, isVar := env.varTypes[ident.Name]; isVar {
+				// a method value as in h.listUsers keeps the receiver type
+				pkgName, typeName := TypeParts(varType)
+				if len(pkgName) == 0 {
+					pkgName = file.Pkg.Name
+				}
+				return pkgName + "." + typeName + "." + x.Sel.Name
+			}
+		}
+		return flattenType(x, file.Pkg.Name, file.importMappings)
+	case *ast.CallExpr:
+		// conversions as in http.HandlerFunc(handler)
+		if len(x.Args) == 1 {
+			return w.handlerType(env, x.Args[0])
+		}
+	}
+	return ""
+}
+
+// resolveCallee finds the function being called
+func (w *routeWalker) resolveCallee(env *routeEnv, call *ast.CallExpr) *Function {
+	file := env.fun.File
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return findFunc([]*Pkg{file.Pkg}, "", fun.Name)
+	case *ast.SelectorExpr:
+		ident, ok := fun.X.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		if varType, isVar := env.varTypes[ident.Name]; isVar {
+			pkgName, typeName := TypeParts(varType)
+			return findFunc(w.project.pkgsNamed(file, pkgName), typeName, fun.Sel.Name)
+		}
+		if _, ok := file.importMappings[ident.Name]; ok {
+			return findFunc(w.project.pkgsNamed(file, ident.Name), "", fun.Sel.Name)
+		}
+	}
+	return nil
+}
+
+// pkgsNamed returns the packages of the project that a file refers to with a name, an
+// import of the module of the project is resolved to the directory of its package
+func (p *Project) pkgsNamed(file *File, name string) []*Pkg {
+	if name == file.Pkg.Name {
+		return []*Pkg{file.Pkg}
+	}
+	importPath := file.ImportPath(name)
+	if len(p.Module) > 0 && (importPath == p.Module || strings.HasPrefix(importPath, p.Module+"/")) {
+		dir := filepath.Join(p.RootPath, filepath.FromSlash(strings.TrimPrefix(importPath, p.Module)))
+		for _, pkg := range p.Pkgs {
+			if filepath.Clean(pkg.Path) == dir {
+				return []*Pkg{pkg}
+			}
+		}
+	}
+	if mapped, ok := file.importMappings[name]; ok {
+		name = mapped
+	}
+	pkgs := make([]*Pkg, 0)
+	for _, pkg := range p.Pkgs {
+		if pkg.Name == name {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	return pkgs
+}
+
+// handlerPkgPath returns the directory of the package of a handler referred to in a file,
+// it is empty when no package or more than one package of the project has its name
+func (p *Project) handlerPkgPath(file *File, handlerType string) string {
+	pkgName, _, _ := HandlerParts(handlerType)
+	if len(pkgName) == 0 {
+		return ""
+	}
+	pkgs := p.pkgsNamed(file, pkgName)
+	if len(pkgs) != 1 {
+		return ""
+	}
+	return pkgs[0].Path
+}
+
+// findFunc finds a function in the packages, when receiver is not empty it finds a method
+// of the receiver type. A method called through an interface is found when a single type
+// of the packages implements it.
+func findFunc(pkgs []*Pkg, receiver, name string) *Function {
+	isInterface := false
+	if len(receiver) > 0 {
+		for _, p := range pkgs {
+			isInterface = isInterface || p.isInterface(receiver)
+		}
+	}
+	implementations := make([]*Function, 0)
+	for _, p := range pkgs {
+		for i := range p.Files {
+			for j := range p.Files[i].Functions {
+				fun := &p.Files[i].Functions[j]
+				if fun.Name != name {
+					continue
+				}
+				memberOf := strings.TrimPrefix(fun.MemberOf, "*")
+				if memberOf == receiver {
+					return fun
+				}
+				if isInterface && len(memberOf) > 0 {
+					implementations = append(implementations, fun)
+				}
+			}
+		}
+	}
+	if len(implementations) == 1 {
+		return implementations[0]
+	}
+	return nil
+}
+
+func printExpr(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	err := printer.Fprint(&buf, fset, expr)
+	if err != nil {
+		return ""
+	}
+	return buf.String()
+}
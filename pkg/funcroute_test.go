@@ -0,0 +1,364 @@
+package pkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/javiercbk/swago/criteria"
+	"github.com/stretchr/testify/assert"
+)
+
+const echoImport = `"github.com/labstack/echo/v4"`
+
+// writeTestProject writes the files of a project to a temporary directory
+func writeTestProject(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "swago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSearchForFuncRoutes(t *testing.T) {
+	funcRoute := func(pkg, funcName string) criteria.FuncRoute {
+		return criteria.FuncRoute{Pkg: pkg, FuncName: funcName, PathIndex: 0, HandlerIndex: 1, MiddlewareIndex: 2}
+	}
+	echoRoutes := []criteria.FuncRoute{funcRoute("echo", "GET"), funcRoute("echo", "POST")}
+	echoGroups := []criteria.RouteGroup{{Pkg: "echo", FuncName: "Group", PathIndex: 0, MiddlewareIndex: 1, UseFuncName: "Use"}}
+	type params struct {
+		files      map[string]string
+		funcRoutes []criteria.FuncRoute
+		groups     []criteria.RouteGroup
+	}
+	type expected struct {
+		routes []string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name: "should prefix the routes of a group with its path and middlewares",
+			params: params{files: map[string]string{
+				"main.go": `package main
+
+import ` + echoImport + `
+
+func main() {
+	e := echo.New()
+	api := e.Group("/api", auth)
+	users := api.Group("/users")
+	users.GET("/:id", getUser, admin)
+	e.GET("/health", health)
+}
+`,
+			}},
+			expected: expected{routes: []string{
+				"GET /api/users/:id main.getUser [auth admin]",
+				"GET /health main.health []",
+			}},
+		},
+		{
+			name: "should apply the middlewares of use to the routes registered after it",
+			params: params{files: map[string]string{
+				"main.go": `package main
+
+import ` + echoImport + `
+
+func main() {
+	e := echo.New()
+	v1 := e.Group("/v1")
+	v1.Use(logger, recover)
+	v1.POST("/users", createUser)
+}
+`,
+			}},
+			expected: expected{routes: []string{
+				"POST /v1/users main.createUser [logger recover]",
+			}},
+		},
+		{
+			name: "should follow a router passed to a function of another package",
+			params: params{files: map[string]string{
+				"main.go": `package main
+
+import (
+	` + echoImport + `
+	"example.com/api/handler"
+)
+
+func main() {
+	e := echo.New()
+	api := e.Group("/api", auth)
+	h := handler.New()
+	h.Routes(api)
+}
+`,
+				"handler/handler.go": `package handler
+
+import ` + echoImport + `
+
+type Handler struct{}
+
+func New() Handler {
+	return Handler{}
+}
+
+func (h Handler) Routes(g *echo.Group) {
+	g.GET("/users", h.listUsers)
+}
+`,
+			}},
+			expected: expected{routes: []string{
+				"GET /api/users handler.Handler.listUsers [auth]",
+			}},
+		},
+		{
+			name: "should not follow a method of another receiver",
+			params: params{files: map[string]string{
+				"main.go": `package main
+
+import ` + echoImport + `
+
+type Admin struct{}
+
+type Public struct{}
+
+func (p Public) Routes(g *echo.Group) {
+	g.GET("/users", listUsers)
+}
+
+func main() {
+	e := echo.New()
+	a := Admin{}
+	a.Routes(e.Group("/admin"))
+}
+`,
+			}},
+			expected: expected{routes: []string{
+				"GET /users main.listUsers []",
+			}},
+		},
+		{
+			name: "should follow a method called through an interface with a single implementation",
+			params: params{files: map[string]string{
+				"main.go": `package main
+
+import ` + echoImport + `
+
+type Router interface {
+	Routes(g *echo.Group)
+}
+
+type handler struct{}
+
+func (h handler) Routes(g *echo.Group) {
+	g.GET("/users", h.listUsers)
+}
+
+func newRouter() Router {
+	return handler{}
+}
+
+func main() {
+	e := echo.New()
+	api := e.Group("/api")
+	r := newRouter()
+	r.Routes(api)
+}
+`,
+			}},
+			expected: expected{routes: []string{
+				"GET /api/users main.handler.listUsers []",
+			}},
+		},
+		{
+			name: "should follow the routers held in struct fields",
+			params: params{files: map[string]string{
+				"server.go": `package main
+
+import ` + echoImport + `
+
+type Server struct {
+	router *echo.Echo
+	api    *echo.Group
+}
+
+func (s *Server) routes() {
+	s.api.GET("/users", s.listUsers)
+	s.router.GET("/health", health)
+}
+
+func NewServer() *Server {
+	s := &Server{}
+	s.router = echo.New()
+	s.api = s.router.Group("/api", auth)
+	s.api.Use(logger)
+	return s
+}
+`,
+			}},
+			expected: expected{routes: []string{
+				"GET /api/users main.Server.listUsers [auth logger]",
+				"GET /health main.health []",
+			}},
+		},
+		{
+			name: "should match the package of a criteria by its import path",
+			params: params{
+				files: map[string]string{
+					"main.go": `package main
+
+import (
+	` + echoImport + `
+	fake "example.com/api/echo"
+)
+
+func main() {
+	e := echo.New()
+	e.GET("/users", listUsers)
+	f := fake.New()
+	f.GET("/fake", fakeHandler)
+}
+`,
+					"echo/echo.go": `package echo
+
+type Fake struct{}
+
+func New() Fake {
+	return Fake{}
+}
+`,
+				},
+				funcRoutes: []criteria.FuncRoute{funcRoute("github.com/labstack/echo/v4", "GET")},
+				groups:     []criteria.RouteGroup{{Pkg: "github.com/labstack/echo/v4", FuncName: "Group"}},
+			},
+			expected: expected{routes: []string{
+				"GET /users main.listUsers []",
+			}},
+		},
+		{
+			name: "should resolve packages with the same name by their import path",
+			params: params{files: map[string]string{
+				"main.go": `package main
+
+import (
+	` + echoImport + `
+	"example.com/api/v1/handler"
+	v2handler "example.com/api/v2/handler"
+)
+
+func main() {
+	e := echo.New()
+	handler.Register(e.Group("/v1"))
+	v2handler.Register(e.Group("/v2"))
+}
+`,
+				"v1/handler/handler.go": `package handler
+
+import ` + echoImport + `
+
+func Register(g *echo.Group) {
+	g.GET("/users", listUsers)
+}
+`,
+				"v2/handler/handler.go": `package handler
+
+import ` + echoImport + `
+
+func Register(g *echo.Group) {
+	g.GET("/accounts", listAccounts)
+}
+`,
+			}},
+			expected: expected{routes: []string{
+				"GET /v1/users handler.listUsers []",
+				"GET /v2/accounts handler.listAccounts []",
+			}},
+		},
+	}
+	logger := log.New(ioutil.Discard, "", 0)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := writeTestProject(t, test.params.files)
+			defer os.RemoveAll(dir)
+			pkgs, err := AnalizeProject(dir, logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			project := &Project{Pkgs: pkgs, RootPath: dir, Module: "example.com/api"}
+			funcRoutes := test.params.funcRoutes
+			if funcRoutes == nil {
+				funcRoutes = echoRoutes
+			}
+			groups := test.params.groups
+			if groups == nil {
+				groups = echoGroups
+			}
+			routes := make([]string, 0)
+			for _, r := range project.SearchForFuncRoutes(funcRoutes, groups) {
+				routes = append(routes, fmt.Sprintf("%s %s %s [%s]", r.HTTPMethod, r.Path, r.HandlerType, strings.Join(r.Middlewares, " ")))
+			}
+			sort.Strings(routes)
+			assert.Equal(t, test.expected.routes, routes)
+		})
+	}
+}
+
+func TestHandlerParts(t *testing.T) {
+	type expected struct {
+		pkgName  string
+		receiver string
+		name     string
+	}
+	tests := []struct {
+		name     string
+		params   string
+		expected expected
+	}{
+		{
+			name:     "should split a function",
+			params:   "handler.ListUsers",
+			expected: expected{pkgName: "handler", name: "ListUsers"},
+		},
+		{
+			name:     "should split a method",
+			params:   "handler.UserHandler.List",
+			expected: expected{pkgName: "handler", receiver: "UserHandler", name: "List"},
+		},
+		{
+			name:     "should split a method of a generic type",
+			params:   "handler.Crud[models.User].List",
+			expected: expected{pkgName: "handler", receiver: "Crud[models.User]", name: "List"},
+		},
+		{
+			name:     "should split a function without a package",
+			params:   "ListUsers",
+			expected: expected{name: "ListUsers"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pkgName, receiver, name := HandlerParts(test.params)
+			assert.Equal(t, test.expected.pkgName, pkgName)
+			assert.Equal(t, test.expected.receiver, receiver)
+			assert.Equal(t, test.expected.name, name)
+		})
+	}
+}
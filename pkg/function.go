@@ -3,6 +3,7 @@ package pkg
 import (
 	"go/ast"
 	"go/token"
+	"strings"
 
 	"github.com/javiercbk/swago/criteria"
 	swagoErrors "github.com/javiercbk/swago/errors"
@@ -10,13 +11,50 @@ import (
 
 // Function is a function in a package
 type Function struct {
-	File     *File
-	Name     string
-	MemberOf string
-	Args     []Variable
-	Return   []string
-	block    *ast.BlockStmt
-	callExpr *ast.CallExpr
+	File        *File
+	Name        string
+	Doc         string
+	Annotations []Annotation
+	MemberOf    string
+	Receiver    string
+	Args        []Variable
+	Return      []string
+	Pos         token.Pos
+	block       *ast.BlockStmt
+	callExpr    *ast.CallExpr
+}
+
+// OperationName returns the name of the operation of a function, which is its package,
+// the receiver type of a method and its name as in usersHandlerList
+func (f Function) OperationName() string {
+	pkgName := ""
+	if f.File != nil && f.File.Pkg != nil {
+		pkgName = f.File.Pkg.Name
+	}
+	return OperationName(pkgName, f.MemberOf, f.Name)
+}
+
+// OperationName joins the package, the receiver type and the name of a function in camel
+// case, the first part starts with a lower case letter and the rest with an upper case one
+func OperationName(pkgName, receiver, name string) string {
+	receiver, _ = splitTypeArgs(strings.TrimPrefix(receiver, "*"))
+	id := ""
+	for _, part := range []string{pkgName, receiver, name} {
+		if len(part) == 0 {
+			continue
+		}
+		if len(id) == 0 {
+			id = lowerFirst(part)
+		} else {
+			id += upperFirst(part)
+		}
+	}
+	return id
+}
+
+// Body returns the statements of a function
+func (f Function) Body() *ast.BlockStmt {
+	return f.block
 }
 
 // ListVariablesUntil returns a list of all the variables until a position
@@ -59,54 +97,91 @@ func (f Function) ListVariablesUntil(until token.Pos) []Variable {
 
 // FindArgTypeCallExpression given a call expression it finds the type of the argument
 func (f Function) FindArgTypeCallExpression(callCriteria criteria.CallCriteria) (string, error) {
+	return f.findArgTypeCallExpression(callCriteria, nil)
+}
+
+// findArgTypeCallExpression finds the type of the argument of the first call that matches a
+// call criteria with a variable as its argument, trace receives every call considered
+func (f Function) findArgTypeCallExpression(callCriteria criteria.CallCriteria, trace func(CallTrace)) (string, error) {
 	var foundAt token.Pos = -1
 	var ident *ast.Ident
+	if f.block == nil {
+		return "", swagoErrors.ErrNotFound
+	}
 	ast.Inspect(f.block, func(n ast.Node) bool {
-		if n != nil {
-			switch x := n.(type) {
-			case *ast.CallExpr:
-				fullName := flattenType(x.Fun, f.File.Pkg.Name, f.File.importMappings)
-				pkg, name := TypeParts(fullName)
-				if foundAt == -1 && pkg == callCriteria.Pkg && name == callCriteria.FuncName && len(x.Args) > callCriteria.ModelExtractor.ParamIndex {
-					varAST := x.Args[callCriteria.ModelExtractor.ParamIndex]
-					switch varExpr := varAST.(type) {
-					case *ast.UnaryExpr:
-						var ok bool
-						foundAt = x.Pos()
-						ident, ok = varExpr.X.(*ast.Ident)
-						if !ok {
-							foundAt = -1
-							ident = nil
-						}
-					case *ast.Ident:
-						foundAt = x.Pos()
-						ident = varExpr
-					}
+		x, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fullName, matched := f.matchesCallCriteria(x, callCriteria)
+		ct := CallTrace{
+			Position: f.PositionOf(x.Pos()),
+			Call:     fullName,
+			Matched:  matched,
+		}
+		if matched && len(x.Args) > callCriteria.ModelExtractor.ParamIndex {
+			varAST := x.Args[callCriteria.ModelExtractor.ParamIndex]
+			var varIdent *ast.Ident
+			switch varExpr := varAST.(type) {
+			case *ast.UnaryExpr:
+				varIdent, _ = varExpr.X.(*ast.Ident)
+			case *ast.Ident:
+				varIdent = varExpr
+			}
+			if varIdent != nil {
+				ct.Variable = varIdent.Name
+				if foundAt == -1 {
+					foundAt = x.Pos()
+					ident = varIdent
+					ct.Used = true
 				}
-				return false
-			default:
-				return true
+			}
+			if trace != nil {
+				ct.Arg = strings.Join(strings.Fields(printExpr(f.File.FSet, varAST)), " ")
 			}
 		}
-		return true
+		if trace != nil {
+			if len(ct.Variable) > 0 {
+				ct.VariableType, _ = f.variableType(ct.Variable, x.Pos())
+			}
+			trace(ct)
+		}
+		return false
 	})
 	if foundAt == -1 {
 		return "", swagoErrors.ErrNotFound
 	}
-	variables := f.ListVariablesUntil(foundAt)
-	for _, v := range variables {
-		if v.Name == ident.Name {
-			return v.GoType, nil
-		}
+	if goType, ok := f.variableType(ident.Name, foundAt); ok {
+		return goType, nil
 	}
 	return "", swagoErrors.ErrNotFound
 }
 
+// matchesCallCriteria returns the called function and true when it is the function of a call criteria
+func (f Function) matchesCallCriteria(call *ast.CallExpr, callCriteria criteria.CallCriteria) (string, bool) {
+	fullName := f.File.CallName(call)
+	pkg, name := TypeParts(fullName)
+	return fullName, pkg == callCriteria.Pkg && name == callCriteria.FuncName
+}
+
+// variableType returns the type of a variable declared before a position
+func (f Function) variableType(name string, until token.Pos) (string, bool) {
+	for _, v := range f.ListVariablesUntil(until) {
+		if v.Name == name {
+			return v.GoType, true
+		}
+	}
+	return "", false
+}
+
 // ModelResponse is a response model
 type ModelResponse struct {
-	Type string
-	Pos  token.Pos
-	Code string
+	Type    string
+	Pos     token.Pos
+	Code    string
+	Headers []string
+	// Inline is the model of an anonymous struct or a map literal
+	Inline *Struct
 }
 
 // FindResponseCallExpressionAfter given a call expression it finds the type of the argument past a position
@@ -114,16 +189,16 @@ func (f Function) FindResponseCallExpressionAfter(callCriteria criteria.CallCrit
 	//FIXME: need to re/write this and FindArgTypeCallExpression function
 	var foundAt token.Pos = -1
 	var ident *ast.Ident
+	var lit *ast.CompositeLit
 	var code string
 	ast.Inspect(f.block, func(n ast.Node) bool {
 		if n != nil {
 			if n.Pos() > *pos {
 				switch x := n.(type) {
 				case *ast.CallExpr:
-					fullName := flattenType(x.Fun, f.File.Pkg.Name, f.File.importMappings)
-					pkg, name := TypeParts(fullName)
+					_, matched := f.matchesCallCriteria(x, callCriteria)
 					// FIXME: should be allowed to continue and return a slice
-					if foundAt == -1 && pkg == callCriteria.Pkg && name == callCriteria.FuncName && len(callCriteria.ModelExtractor.Name) == 0 && len(x.Args) > callCriteria.ModelExtractor.ParamIndex {
+					if foundAt == -1 && matched && len(callCriteria.ModelExtractor.Name) == 0 && len(x.Args) > callCriteria.ModelExtractor.ParamIndex {
 						varAST := x.Args[callCriteria.ModelExtractor.ParamIndex]
 						switch varExpr := varAST.(type) {
 						case *ast.UnaryExpr:
@@ -131,12 +206,17 @@ func (f Function) FindResponseCallExpressionAfter(callCriteria criteria.CallCrit
 							foundAt = x.Pos()
 							ident, ok = varExpr.X.(*ast.Ident)
 							if !ok {
-								foundAt = -1
 								ident = nil
+								if lit, ok = varExpr.X.(*ast.CompositeLit); !ok {
+									foundAt = -1
+								}
 							}
 						case *ast.Ident:
 							foundAt = x.Pos()
 							ident = varExpr
+						case *ast.CompositeLit:
+							foundAt = x.Pos()
+							lit = varExpr
 						}
 						codeAST := x.Args[callCriteria.CodeIndex]
 						switch codeExpr := codeAST.(type) {
@@ -160,12 +240,22 @@ func (f Function) FindResponseCallExpressionAfter(callCriteria criteria.CallCrit
 		return swagoErrors.ErrNotFound
 	}
 	*pos = foundAt
+	modelResponse.Pos = foundAt
+	modelResponse.Code = code
+	modelResponse.Headers = f.FindHeadersBefore(foundAt)
+	if lit != nil {
+		modelResponse.Type, modelResponse.Inline = f.literalModel(lit, foundAt)
+		return nil
+	}
 	variables := f.ListVariablesUntil(foundAt)
 	for _, v := range variables {
 		if v.Name == ident.Name {
 			modelResponse.Type = v.GoType
-			modelResponse.Pos = foundAt
-			modelResponse.Code = code
+			if len(v.GoType) == 0 || v.GoType == AnonymousStruct {
+				if assigned := f.assignedLiteral(ident.Name, foundAt); assigned != nil {
+					modelResponse.Type, modelResponse.Inline = f.literalModel(assigned, foundAt)
+				}
+			}
 			return nil
 		}
 	}
@@ -182,10 +272,9 @@ func (f Function) FindErrorResponseCallExpressionAfter(callCriteria criteria.Cal
 			if n.Pos() > *pos {
 				switch x := n.(type) {
 				case *ast.CallExpr:
-					fullName := flattenType(x.Fun, f.File.Pkg.Name, f.File.importMappings)
-					pkg, name := TypeParts(fullName)
+					_, matched := f.matchesCallCriteria(x, callCriteria)
 					// FIXME: should be allowed to continue and return a slice
-					if foundAt == -1 && pkg == callCriteria.Pkg && name == callCriteria.FuncName && len(x.Args) > callCriteria.CodeIndex {
+					if foundAt == -1 && matched && len(x.Args) > callCriteria.CodeIndex {
 						foundAt = x.Pos()
 						codeAST := x.Args[callCriteria.CodeIndex]
 						switch codeExpr := codeAST.(type) {
@@ -211,5 +300,101 @@ func (f Function) FindErrorResponseCallExpressionAfter(callCriteria criteria.Cal
 	*pos = foundAt
 	modelResponse.Pos = foundAt
 	modelResponse.Code = code
+	modelResponse.Headers = f.FindHeadersBefore(foundAt)
 	return nil
 }
+
+// FindHeadersBefore returns the response headers set before a position, as in
+// w.Header().Set("Location", url). Only the headers set in the blocks enclosing
+// the position are returned, so a header set in a branch that already returned
+// a response does not apply.
+func (f Function) FindHeadersBefore(pos token.Pos) []string {
+	headers := make([]string, 0)
+	blocks := make([]ast.Node, 0)
+	ast.Inspect(f.block, func(n ast.Node) bool {
+		if n == nil {
+			return true
+		}
+		if n.Pos() >= pos {
+			return false
+		}
+		// inspect visits nodes in order, so blocks ending before n are not enclosing it
+		for len(blocks) > 0 && blocks[len(blocks)-1].End() <= n.Pos() {
+			blocks = blocks[:len(blocks)-1]
+		}
+		switch x := n.(type) {
+		case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+			blocks = append(blocks, x)
+		case *ast.CallExpr:
+			name := f.headerName(x)
+			if len(name) == 0 || len(blocks) == 0 {
+				return true
+			}
+			block := blocks[len(blocks)-1]
+			if block.Pos() <= pos && pos < block.End() {
+				found := false
+				for _, h := range headers {
+					if h == name {
+						found = true
+						break
+					}
+				}
+				if !found {
+					headers = append(headers, name)
+				}
+			}
+		}
+		return true
+	})
+	return headers
+}
+
+// headerName returns the name of the header set by a call as in w.Header().Set("Location", url)
+func (f Function) headerName(call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Set" && sel.Sel.Name != "Add") || len(call.Args) != 2 {
+		return ""
+	}
+	headerCall, ok := sel.X.(*ast.CallExpr)
+	if !ok || len(headerCall.Args) > 0 {
+		return ""
+	}
+	headerSel, ok := headerCall.Fun.(*ast.SelectorExpr)
+	if !ok || headerSel.Sel.Name != "Header" {
+		return ""
+	}
+	return f.File.stringValue(call.Args[0])
+}
+
+// handlerSignatures are the parameters and results of the handlers of echo, net/http and gin
+var handlerSignatures = []struct {
+	params  []string
+	results []string
+}{
+	{params: []string{"echo.Context"}, results: []string{"error"}},
+	{params: []string{"http.ResponseWriter", "*http.Request"}},
+	{params: []string{"*gin.Context"}},
+}
+
+// IsHandler returns true when a function has the signature of an echo.HandlerFunc,
+// an http.HandlerFunc or a gin.HandlerFunc
+func (f Function) IsHandler() bool {
+	for _, signature := range handlerSignatures {
+		if len(f.Args) != len(signature.params) || len(f.Return) != len(signature.results) {
+			continue
+		}
+		matches := true
+		for i := range f.Args {
+			matches = matches && f.Args[i].GoType == signature.params[i]
+		}
+		for i := range f.Return {
+			// builtin types are qualified with the package of the function
+			result := strings.TrimPrefix(f.Return[i], f.File.Pkg.Name+".")
+			matches = matches && result == signature.results[i]
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const headersSrc = `package handler
+
+const totalCountHeader = "X-Total-Count"
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Location", "/users/1")
+	if err != nil {
+		w.Header().Set("X-Error", "1")
+		send(w, http.StatusBadRequest)
+		return
+	}
+	if list {
+		w.Header().Add(totalCountHeader, "10")
+		send(w, http.StatusOK)
+		return
+	}
+	send(w, http.StatusCreated)
+}
+`
+
+func TestFindHeadersBefore(t *testing.T) {
+	file := parseTestFile(t, headersSrc)
+	sendCalls := make([]token.Pos, 0)
+	ast.Inspect(file.File, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "send" {
+				sendCalls = append(sendCalls, call.Pos())
+			}
+		}
+		return true
+	})
+	type params struct {
+		sendCall int
+	}
+	type expected struct {
+		headers []string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "headers set in the same block",
+			params:   params{sendCall: 0},
+			expected: expected{headers: []string{"Location", "X-Error"}},
+		},
+		{
+			name:     "headers set with a constant name",
+			params:   params{sendCall: 1},
+			expected: expected{headers: []string{"Location", "X-Total-Count"}},
+		},
+		{
+			name:     "headers set in other branches are ignored",
+			params:   params{sendCall: 2},
+			expected: expected{headers: []string{"Location"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			headers := file.Functions[0].FindHeadersBefore(sendCalls[test.params.sendCall])
+			assert.Equal(t, test.expected.headers, headers)
+		})
+	}
+}
+
+const handlersSrc = `package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/labstack/echo/v4"
+)
+
+func EchoHandler(c echo.Context) error {
+	return nil
+}
+
+func HTTPHandler(http.ResponseWriter, *http.Request) {
+}
+
+func GinHandler(c *gin.Context) {
+}
+
+func NotAHandler(c echo.Context) {
+}
+
+func Middleware(next http.Handler) http.Handler {
+	return next
+}
+`
+
+func TestIsHandler(t *testing.T) {
+	file := parseTestFile(t, handlersSrc)
+	type expected struct {
+		isHandler bool
+	}
+	tests := []struct {
+		name     string
+		expected expected
+	}{
+		{name: "EchoHandler", expected: expected{isHandler: true}},
+		{name: "HTTPHandler", expected: expected{isHandler: true}},
+		{name: "GinHandler", expected: expected{isHandler: true}},
+		{name: "NotAHandler", expected: expected{isHandler: false}},
+		{name: "Middleware", expected: expected{isHandler: false}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fun := Function{Name: test.name}
+			if assert.Nil(t, file.FindFunc(&fun)) {
+				assert.Equal(t, test.expected.isHandler, fun.IsHandler())
+			}
+		})
+	}
+}
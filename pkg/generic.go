@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	pointerPrefix = "*"
+	arrayPrefix   = "[]"
+)
+
+var (
+	qualifiedIdentRegExp = regexp.MustCompile(`[A-Za-z0-9_.]+`)
+)
+
+// qualifyType adds a package to every type that does not have one, as in
+// Envelope[[]User] that is pkg.Envelope[[]pkg.User]
+func qualifyType(t string, fallbackPkg string) string {
+	prefix, base := splitTypePrefix(t)
+	if len(base) == 0 {
+		return t
+	}
+	name, args := splitTypeArgs(base)
+	if !strings.Contains(name, ".") && !isGoType(name) && name != EmptyInterface && name != AnonymousStruct {
+		name = fallbackPkg + "." + name
+	}
+	if len(args) == 0 {
+		return prefix + name
+	}
+	qualifiedArgs := make([]string, len(args))
+	for i := range args {
+		qualifiedArgs[i] = qualifyType(args[i], fallbackPkg)
+	}
+	return prefix + name + "[" + strings.Join(qualifiedArgs, ",") + "]"
+}
+
+// splitTypePrefix splits the pointer and array prefixes of a type from its base type
+func splitTypePrefix(t string) (string, string) {
+	i := 0
+	for i < len(t) {
+		if strings.HasPrefix(t[i:], pointerPrefix) {
+			i += len(pointerPrefix)
+		} else if strings.HasPrefix(t[i:], arrayPrefix) {
+			i += len(arrayPrefix)
+		} else {
+			break
+		}
+	}
+	return t[:i], t[i:]
+}
+
+// splitTypeArgs splits the name of a generic type from its type arguments, as
+// in Pair[pkg.User,int] that is Pair and [pkg.User int]
+func splitTypeArgs(t string) (string, []string) {
+	start := strings.Index(t, "[")
+	if start == -1 || !strings.HasSuffix(t, "]") {
+		return t, nil
+	}
+	args := make([]string, 0)
+	depth := 0
+	argStart := start + 1
+	for i := argStart; i < len(t)-1; i++ {
+		switch t[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(t[argStart:i]))
+				argStart = i + 1
+			}
+		}
+	}
+	args = append(args, strings.TrimSpace(t[argStart:len(t)-1]))
+	return t[:start], args
+}
+
+// substituteTypeParams replaces the type parameters in a type with their arguments
+func substituteTypeParams(t string, typeArgs map[string]string) string {
+	return qualifiedIdentRegExp.ReplaceAllStringFunc(t, func(ident string) string {
+		if arg, ok := typeArgs[ident]; ok {
+			return arg
+		}
+		return ident
+	})
+}
+
+// genericDefinitionName returns the name of an instantiated generic type, as in
+// Envelope[[]pkg.User] that is EnvelopeOfUserList
+func genericDefinitionName(name string, typeArgs []string) string {
+	if len(typeArgs) == 0 {
+		return name
+	}
+	argNames := make([]string, len(typeArgs))
+	for i, arg := range typeArgs {
+		prefix, base := splitTypePrefix(arg)
+		argName, argArgs := splitTypeArgs(base)
+		_, argName = TypeParts(argName)
+		argNames[i] = upperFirst(genericDefinitionName(argName, argArgs))
+		if strings.Contains(prefix, arrayPrefix) {
+			argNames[i] += "List"
+		}
+	}
+	return name + "Of" + strings.Join(argNames, "And")
+}
+
+// instantiate substitutes the type parameters of a generic struct with type arguments
+func (s *Struct) instantiate(typeArgs []string) {
+	if len(s.TypeParams) == 0 || len(typeArgs) == 0 {
+		return
+	}
+	params := make(map[string]string, len(s.TypeParams))
+	for i, p := range s.TypeParams {
+		if i < len(typeArgs) {
+			// field types are qualified with the package of the struct
+			params[s.File.Pkg.Name+"."+p] = typeArgs[i]
+			params[p] = typeArgs[i]
+		}
+	}
+	fields := make([]Field, len(s.Fields))
+	for i, f := range s.Fields {
+		f.Type = substituteTypeParams(f.Type, params)
+		fields[i] = f
+	}
+	s.Fields = fields
+	s.TypeArgs = typeArgs
+}
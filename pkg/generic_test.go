@@ -0,0 +1,153 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenericTypes(t *testing.T) {
+	type params struct {
+		t string
+	}
+	type expected struct {
+		qualified      string
+		pkg            string
+		name           string
+		definitionName string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:   "plain type",
+			params: params{t: "User"},
+			expected: expected{
+				qualified:      "handler.User",
+				pkg:            "handler",
+				name:           "User",
+				definitionName: "User",
+			},
+		},
+		{
+			name:   "pointer to an imported type",
+			params: params{t: "*models.User"},
+			expected: expected{
+				qualified:      "*models.User",
+				pkg:            "models",
+				name:           "User",
+				definitionName: "User",
+			},
+		},
+		{
+			name:   "generic type",
+			params: params{t: "Envelope[models.User]"},
+			expected: expected{
+				qualified:      "handler.Envelope[models.User]",
+				pkg:            "handler",
+				name:           "Envelope[models.User]",
+				definitionName: "EnvelopeOfUser",
+			},
+		},
+		{
+			name:   "nested generic types",
+			params: params{t: "response.Envelope[Page[[]User,int]]"},
+			expected: expected{
+				qualified:      "response.Envelope[handler.Page[[]handler.User,int]]",
+				pkg:            "response",
+				name:           "Envelope[handler.Page[[]handler.User,int]]",
+				definitionName: "EnvelopeOfPageOfUserListAndInt",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			qualified := qualifyType(test.params.t, "handler")
+			assert.Equal(t, test.expected.qualified, qualified)
+			pkg, name := TypeParts(qualified)
+			assert.Equal(t, test.expected.pkg, pkg)
+			assert.Equal(t, test.expected.name, name)
+			structName, typeArgs := splitTypeArgs(name)
+			assert.Equal(t, test.expected.definitionName, genericDefinitionName(structName, typeArgs))
+		})
+	}
+}
+
+const genericSrc = `package handler
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Stamp[T any] struct {
+	Value T
+}
+
+func (s Stamp[T]) MarshalText() ([]byte, error) {
+	return nil, nil
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K ` + "`json:\"key\"`" + `
+	Value V ` + "`json:\"value\"`" + `
+}
+
+func (p Pair[K, V]) First() K {
+	return p.Key
+}
+
+type Envelope[T any] struct {
+	Data  T          ` + "`json:\"data\"`" + `
+	Items []T        ` + "`json:\"items\"`" + `
+	Stamp Stamp[T]   ` + "`json:\"stamp\"`" + `
+	Pair  Pair[string, T] ` + "`json:\"pair\"`" + `
+}
+
+func (e *Envelope[T]) Unwrap() T {
+	return e.Data
+}
+`
+
+func TestGenericStructSchema(t *testing.T) {
+	file := parseTestFile(t, genericSrc)
+	memberOf := make(map[string]string)
+	for _, f := range file.Functions {
+		memberOf[f.Name] = f.MemberOf
+	}
+	assert.Equal(t, map[string]string{
+		"MarshalText": "Stamp",
+		"First":       "Pair",
+		"Unwrap":      "*Envelope",
+	}, memberOf)
+	envelope := Struct{PkgName: "handler", Name: "Envelope[handler.User]"}
+	err := file.Pkg.Project.FindStruct(&envelope)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "EnvelopeOfUser", envelope.DefinitionName())
+	err = envelope.ToSwaggerSchema()
+	if !assert.Nil(t, err) {
+		return
+	}
+	props := envelope.Schema.Properties
+	if assert.Contains(t, props, "data") {
+		assert.Equal(t, "object", props["data"].Value.Type)
+		assert.Contains(t, props["data"].Value.Properties, "name")
+	}
+	if assert.Contains(t, props, "items") {
+		assert.Equal(t, "array", props["items"].Value.Type)
+		assert.Contains(t, props["items"].Value.Items.Value.Properties, "name")
+	}
+	if assert.Contains(t, props, "stamp") {
+		// the instantiated type implements encoding.TextMarshaler
+		assert.Equal(t, "string", props["stamp"].Value.Type)
+	}
+	if assert.Contains(t, props, "pair") {
+		pair := props["pair"].Value.Properties
+		assert.Equal(t, "string", pair["key"].Value.Type)
+		assert.Contains(t, pair["value"].Value.Properties, "name")
+	}
+	assert.Equal(t, 0, file.Pkg.Project.Diagnostics.Len())
+}
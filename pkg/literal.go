@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// literalModel returns the type of a composite literal, anonymous structs and maps
+// as in map[string]interface{}{"ok": true} are returned as an inline struct
+func (f Function) literalModel(lit *ast.CompositeLit, until token.Pos) (string, *Struct) {
+	switch x := lit.Type.(type) {
+	case *ast.StructType:
+		return AnonymousStruct, f.File.inlineStruct(x)
+	case *ast.MapType:
+		return AnonymousStruct, f.mapLiteralStruct(x, lit.Elts, until)
+	}
+	return flattenType(lit.Type, f.File.Pkg.Name, f.File.importMappings), nil
+}
+
+// mapLiteralStruct returns a struct with a field for every key of a map literal,
+// the type of the fields is inferred from their values
+func (f Function) mapLiteralStruct(mapType *ast.MapType, elts []ast.Expr, until token.Pos) *Struct {
+	s := &Struct{
+		File:   f.File,
+		Fields: make([]Field, 0, len(elts)),
+	}
+	valueType := flattenType(mapType.Value, f.File.Pkg.Name, f.File.importMappings)
+	for _, el := range elts {
+		kv, ok := el.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key := f.File.stringValue(kv.Key)
+		if len(key) == 0 {
+			continue
+		}
+		field := Field{
+			Name: key,
+			Tag:  `json:"` + key + `"`,
+		}
+		field.Type, field.Inline = f.exprType(kv.Value, until)
+		if len(field.Type) == 0 && valueType != EmptyInterface {
+			field.Type = valueType
+		}
+		if len(field.Type) == 0 {
+			// the value might be anything, it is not documented as an object
+			field.Type = UntypedValue
+		}
+		s.Fields = append(s.Fields, field)
+	}
+	return s
+}
+
+// exprType infers the type of an expression found before a position, it is empty
+// when the type is not inferred
+func (f Function) exprType(expr ast.Expr, until token.Pos) (string, *Struct) {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		switch x.Kind {
+		case token.INT:
+			return goTypeInt, nil
+		case token.FLOAT:
+			return goTypeFloat64, nil
+		case token.STRING, token.CHAR:
+			return goTypeString, nil
+		}
+	case *ast.Ident:
+		if x.Name == "true" || x.Name == "false" {
+			return goTypeBool, nil
+		}
+		for _, v := range f.ListVariablesUntil(until) {
+			if v.Name == x.Name && len(v.GoType) > 0 {
+				return v.GoType, nil
+			}
+		}
+		// the type of a variable as in n := 3 is the type of the value assigned
+		if value, pos := f.lastAssigned(x.Name, until, func(ast.Expr) bool { return true }); value != nil {
+			return f.exprType(value, pos)
+		}
+	case *ast.CompositeLit:
+		return f.literalModel(x, until)
+	case *ast.UnaryExpr:
+		if x.Op == token.AND {
+			t, inline := f.exprType(x.X, until)
+			if len(t) == 0 {
+				return "", nil
+			}
+			return pointerPrefix + t, inline
+		}
+	}
+	return "", nil
+}
+
+// assignedLiteral returns the last composite literal assigned to a variable before a position
+func (f Function) assignedLiteral(name string, until token.Pos) *ast.CompositeLit {
+	found, _ := f.lastAssigned(name, until, func(value ast.Expr) bool {
+		if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			value = unary.X
+		}
+		_, ok := value.(*ast.CompositeLit)
+		return ok
+	})
+	if unary, ok := found.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		found = unary.X
+	}
+	lit, _ := found.(*ast.CompositeLit)
+	return lit
+}
+
+// lastAssigned returns the last value accepted that is assigned or declared to a variable
+// before a position and the position of its assignment
+func (f Function) lastAssigned(name string, until token.Pos, accept func(ast.Expr) bool) (ast.Expr, token.Pos) {
+	var found ast.Expr
+	var foundAt token.Pos
+	match := func(names []ast.Expr, values []ast.Expr, at token.Pos) {
+		for i, l := range names {
+			ident, ok := l.(*ast.Ident)
+			if !ok || ident.Name != name || i >= len(values) {
+				continue
+			}
+			if accept(values[i]) {
+				found = values[i]
+				foundAt = at
+			}
+		}
+	}
+	ast.Inspect(f.block, func(n ast.Node) bool {
+		if n == nil || n.Pos() >= until {
+			return false
+		}
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			match(x.Lhs, x.Rhs, x.Pos())
+		case *ast.ValueSpec:
+			names := make([]ast.Expr, len(x.Names))
+			for i := range x.Names {
+				names[i] = x.Names[i]
+			}
+			match(names, x.Values, x.Pos())
+		}
+		return true
+	})
+	return found, foundAt
+}
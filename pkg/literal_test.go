@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/javiercbk/swago/criteria"
+	"github.com/stretchr/testify/assert"
+)
+
+const literalsSrc = `package handler
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	api.Send(w, http.StatusOK, User{})
+	api.Send(w, http.StatusCreated, &struct {
+		ID   int    ` + "`json:\"id\"`" + `
+		Tags []struct {
+			Name string
+		}
+	}{})
+	user := User{}
+	n := 3
+	var ratio = 0.5
+	total := n
+	stats := map[string]interface{}{"ok": true, "count": 3, "user": user, "meta": map[string]string{"a": "b"}, "n": n, "ratio": ratio, "total": total, "body": r.Body}
+	api.Send(w, http.StatusAccepted, stats)
+}
+`
+
+func TestFindLiteralResponses(t *testing.T) {
+	file := parseTestFile(t, literalsSrc)
+	callCriteria := criteria.CallCriteria{
+		Pkg:            "api",
+		FuncName:       "Send",
+		ModelExtractor: criteria.ModelExtractor{ParamIndex: 2},
+		CodeIndex:      1,
+	}
+	type expected struct {
+		t      string
+		fields map[string]string
+	}
+	tests := []struct {
+		name     string
+		expected expected
+	}{
+		{
+			name:     "named struct literal",
+			expected: expected{t: "handler.User"},
+		},
+		{
+			name: "anonymous struct literal",
+			expected: expected{
+				t:      AnonymousStruct,
+				fields: map[string]string{"ID": "int", "Tags": "[]struct{}"},
+			},
+		},
+		{
+			name: "map literal",
+			expected: expected{
+				t:      AnonymousStruct,
+				fields: map[string]string{"ok": "bool", "count": "int", "user": "handler.User", "meta": AnonymousStruct, "n": "int", "ratio": "float64", "total": "int", "body": UntypedValue},
+			},
+		},
+	}
+	fun := file.Functions[0]
+	var pos token.Pos = -1
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modelResponse := ModelResponse{}
+			err := fun.FindResponseCallExpressionAfter(callCriteria, &pos, &modelResponse)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected.t, modelResponse.Type)
+			if test.expected.fields == nil {
+				assert.Nil(t, modelResponse.Inline)
+				return
+			}
+			fields := make(map[string]string)
+			for _, f := range modelResponse.Inline.Fields {
+				fields[f.Name] = f.Type
+			}
+			assert.Equal(t, test.expected.fields, fields)
+		})
+	}
+}
+
+func TestUntypedValueSchema(t *testing.T) {
+	file := parseTestFile(t, literalsSrc)
+	s := Struct{
+		File: file,
+		Fields: []Field{
+			{Name: "body", Type: UntypedValue, Tag: `json:"body"`},
+			{Name: "meta", Type: EmptyInterface, Tag: `json:"meta"`},
+		},
+	}
+	assert.Nil(t, s.ToSwaggerSchema())
+	assert.Equal(t, "", s.Schema.Properties["body"].Value.Type, "an untyped value might be anything")
+	assert.Equal(t, "object", s.Schema.Properties["meta"].Value.Type)
+	assert.Equal(t, 0, file.Pkg.Project.Diagnostics.Len())
+}
@@ -12,9 +12,11 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
 	swagoErrors "github.com/javiercbk/swago/errors"
 
 	"github.com/javiercbk/swago/folder"
@@ -30,7 +32,12 @@ const (
 	// TypeKeyWord is the keyword "type"
 	TypeKeyWord string = "type"
 	// EmptyInterface is the empty interface keyword
-	EmptyInterface    = "interface{}"
+	EmptyInterface = "interface{}"
+	// UntypedValue is the type of a value whose type is not inferred, it is documented without a type
+	UntypedValue = "untyped{}"
+	// AnonymousStruct is the type of an anonymous struct
+	AnonymousStruct   = "struct{}"
+	goTypeAny         = "any"
 	goTypeBool        = "bool"
 	goTypeString      = "string"
 	goTypeInt         = "int"
@@ -56,8 +63,10 @@ const (
 )
 
 var (
-	errFileNotInPackage error = errors.New("file does not belong to the package")
-	defaultBlackList          = []*regexp.Regexp{
+	errFileNotInPackage      error = errors.New("file does not belong to the package")
+	majorVersionRegExp             = regexp.MustCompile(`^v[0-9]+$`)
+	majorVersionSuffixRegExp       = regexp.MustCompile(`\.v[0-9]+$`)
+	defaultBlackList               = []*regexp.Regexp{
 		regexp.MustCompile(".*_test\\.go"),
 		regexp.MustCompile(".*" + string(os.PathSeparator) + "testdata" + string(os.PathSeparator) + ".*"),
 		regexp.MustCompile(".*" + string(os.PathSeparator) + "vendor" + string(os.PathSeparator) + ".*"),
@@ -94,6 +103,8 @@ func (file *File) SearchForStructRoutes(structRoute criteria.StructRoute) []Rout
 				foundRoute := Route{
 					Pkg:                   file.Pkg.Name,
 					File:                  file.Name,
+					Position:              file.FSet.Position(x.Pos()),
+					Criteria:              "structRoute " + structRoute.Pkg + "." + structRoute.Name,
 					NamedPathVarExtractor: structRoute.NamedPathVarExtractorRegexp,
 				}
 				file.compositeLitToRoute(x, &foundRoute, structRoute)
@@ -118,49 +129,124 @@ func (file *File) FindFunc(fun *Function) error {
 
 // FindStruct find a struct in a file
 func (file *File) FindStruct(str *Struct) error {
+	name, typeArgs := splitTypeArgs(str.Name)
 	for _, s := range file.Structs {
-		if s.Name == str.Name {
+		if s.Name == name {
 			*str = s
 			str.File = file
+			str.instantiate(typeArgs)
 			return nil
 		}
 	}
 	return swagoErrors.ErrNotFound
 }
 
+// stringValue returns the value of a string literal or a string constant
+func (file *File) stringValue(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		if x.Kind == token.STRING {
+			value, err := strconv.Unquote(x.Value)
+			if err == nil {
+				return value
+			}
+		}
+	case *ast.Ident:
+		for _, f := range file.Pkg.Files {
+			for _, c := range f.GlobalConst {
+				if c.Name == x.Name {
+					return c.StrValue
+				}
+			}
+			for _, v := range f.GlobalVars {
+				if v.Name == x.Name {
+					return v.StrValue
+				}
+			}
+		}
+	case *ast.BinaryExpr:
+		if x.Op == token.ADD {
+			return file.stringValue(x.X) + file.stringValue(x.Y)
+		}
+	}
+	return ""
+}
+
 func (file *File) extractType(genDecl *ast.GenDecl) {
 	for _, spec := range genDecl.Specs {
 		x := spec.(*ast.TypeSpec)
 		st, ok := x.Type.(*ast.StructType)
 		if ok {
+			doc := x.Doc
+			if doc == nil && len(genDecl.Specs) == 1 {
+				// type Name struct{} has its doc in the declaration
+				doc = genDecl.Doc
+			}
 			s := Struct{
 				Name:   x.Name.Name,
+				Doc:    doc.Text(),
 				Fields: make([]Field, 0),
+				Pos:    x.Pos(),
 			}
-			for _, f := range st.Fields.List {
-				typeStr := flattenType(f.Type, file.Pkg.Name, file.importMappings)
-				tag := ""
-				if f.Tag != nil {
-					tag = strings.Trim(f.Tag.Value, "`")
-					tag = strings.ReplaceAll(tag, "\\\"", "\"")
-				}
-				// if field name is empty then it this is an embed struct
-				fieldName := ""
-				if len(f.Names) > 0 {
-					fieldName = f.Names[0].Name
-				}
-				newField := Field{
-					Tag:  tag,
-					Name: fieldName,
-					Type: typeStr,
+			if x.TypeParams != nil {
+				for _, tp := range x.TypeParams.List {
+					for _, n := range tp.Names {
+						s.TypeParams = append(s.TypeParams, n.Name)
+					}
 				}
-				s.Fields = append(s.Fields, newField)
 			}
+			s.Fields = file.extractFields(st)
 			file.Structs = append(file.Structs, s)
 		}
 	}
 }
 
+func (file *File) extractFields(st *ast.StructType) []Field {
+	fields := make([]Field, 0)
+	for _, f := range st.Fields.List {
+		typeStr := flattenType(f.Type, file.Pkg.Name, file.importMappings)
+		tag := ""
+		if f.Tag != nil {
+			tag = strings.Trim(f.Tag.Value, "`")
+			tag = strings.ReplaceAll(tag, "\\\"", "\"")
+		}
+		// if field name is empty then it this is an embed struct
+		fieldName := ""
+		if len(f.Names) > 0 {
+			fieldName = f.Names[0].Name
+		}
+		fieldDoc := f.Doc
+		if fieldDoc == nil {
+			fieldDoc = f.Comment
+		}
+		newField := Field{
+			Tag:    tag,
+			Name:   fieldName,
+			Type:   typeStr,
+			Doc:    fieldDoc.Text(),
+			Inline: file.inlineStruct(f.Type),
+		}
+		fields = append(fields, newField)
+	}
+	return fields
+}
+
+// inlineStruct returns the anonymous struct declared in a type as in []struct{ Name string }
+func (file *File) inlineStruct(n ast.Expr) *Struct {
+	switch x := n.(type) {
+	case *ast.StarExpr:
+		return file.inlineStruct(x.X)
+	case *ast.ArrayType:
+		return file.inlineStruct(x.Elt)
+	case *ast.StructType:
+		return &Struct{
+			File:   file,
+			Fields: file.extractFields(x),
+		}
+	}
+	return nil
+}
+
 func (file *File) matchesStructRoute(com *ast.CompositeLit, structRoute criteria.StructRoute) bool {
 	flattenedType := flattenType(com, file.Pkg.Name, file.importMappings)
 	return flattenedType == structRoute.Pkg+"."+structRoute.Name
@@ -200,6 +286,9 @@ func (file *File) compositeLitToRoute(com *ast.CompositeLit, route *Route, struc
 					route.Path = val
 				case structRoute.HandlerField:
 					route.HandlerType = val
+					if file.Pkg.Project != nil {
+						route.HandlerPkgPath = file.Pkg.Project.handlerPkgPath(file, val)
+					}
 				case structRoute.HTTPMethodField:
 					route.HTTPMethod = criteria.MatchHTTPMethod(val)
 				}
@@ -212,22 +301,36 @@ func (file *File) extractFunction(x *ast.FuncDecl) {
 	f := Function{
 		File:  file,
 		Name:  x.Name.Name,
+		Pos:   x.Pos(),
 		block: x.Body,
 	}
+	f.Annotations, f.Doc = ParseAnnotations(x.Doc.Text())
+	setAnnotationPositions(x.Doc, f.Annotations)
 	if x.Recv != nil && x.Recv.List != nil {
 		if len(x.Recv.List) > 0 {
+			if len(x.Recv.List[0].Names) > 0 {
+				f.Receiver = x.Recv.List[0].Names[0].Name
+			}
 			switch ft := x.Recv.List[0].Type.(type) {
-			case *ast.Ident:
-				f.MemberOf = ft.Name
 			case *ast.StarExpr:
-				typeIdent := ft.X.(*ast.Ident)
-				f.MemberOf = "*" + typeIdent.Name
+				if name := receiverTypeName(ft.X); len(name) > 0 {
+					f.MemberOf = "*" + name
+				}
+			default:
+				f.MemberOf = receiverTypeName(ft)
 			}
 		}
 	}
 	if x.Type.Params != nil && len(x.Type.Params.List) > 0 {
 		for _, pl := range x.Type.Params.List {
 			goType := flattenType(pl.Type, file.Pkg.Name, file.importMappings)
+			if len(pl.Names) == 0 {
+				// unnamed parameters are kept so the arguments keep their index
+				f.Args = append(f.Args, Variable{
+					Name:   "_",
+					GoType: goType,
+				})
+			}
 			for _, n := range pl.Names {
 				f.Args = append(f.Args, Variable{
 					Name:   n.Name,
@@ -245,6 +348,20 @@ func (file *File) extractFunction(x *ast.FuncDecl) {
 	file.Functions = append(file.Functions, f)
 }
 
+// receiverTypeName returns the name of the type of a method receiver, the type
+// parameters of a generic receiver as in Envelope[T] are dropped
+func receiverTypeName(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.IndexExpr:
+		return receiverTypeName(x.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(x.X)
+	}
+	return ""
+}
+
 // AnalizeProject reads a project and returns a list of packages
 func AnalizeProject(path string, logger *log.Logger) ([]*Pkg, error) {
 	return AnalizeProjectWithBlacklist(path, logger, defaultBlackList)
@@ -252,11 +369,30 @@ func AnalizeProject(path string, logger *log.Logger) ([]*Pkg, error) {
 
 // AnalizeProjectWithBlacklist reads a project excluding some files and returns a list of packages
 func AnalizeProjectWithBlacklist(path string, logger *log.Logger, blacklist []*regexp.Regexp) ([]*Pkg, error) {
-	pkgs := make([]*Pkg, 0)
 	goFiles, err := folder.ListGoFilesRecursively(path, blacklist)
 	if err != nil {
-		return pkgs, err
+		return make([]*Pkg, 0), err
+	}
+	return analyzeGoFiles(goFiles, logger, blacklist)
+}
+
+// AnalyzeDirWithBlacklist reads the packages of a directory, not of its subdirectories,
+// excluding some files
+func AnalyzeDirWithBlacklist(dir string, logger *log.Logger, blacklist []*regexp.Regexp) ([]*Pkg, error) {
+	fileNames, err := folder.ListGoFiles(dir, blacklist)
+	if err != nil {
+		return make([]*Pkg, 0), err
 	}
+	goFiles := make([]string, 0, len(fileNames))
+	for _, name := range fileNames {
+		goFiles = append(goFiles, filepath.Join(dir, name))
+	}
+	return analyzeGoFiles(goFiles, logger, blacklist)
+}
+
+// analyzeGoFiles returns the packages of a list of go files
+func analyzeGoFiles(goFiles []string, logger *log.Logger, blacklist []*regexp.Regexp) ([]*Pkg, error) {
+	pkgs := make([]*Pkg, 0)
 	for _, goFile := range goFiles {
 		goFilePath := filepath.Dir(goFile)
 		fset := token.NewFileSet()
@@ -293,9 +429,13 @@ func NewPkgWithoutTest(name, path string, logger *log.Logger) *Pkg {
 
 // Project is a golang project
 type Project struct {
-	RootPath  string
+	RootPath string
+	// Module is the path of the module of the project, it resolves the imports of its packages
+	Module    string
 	Blacklist []*regexp.Regexp
 	Pkgs      []*Pkg
+	// Diagnostics collects the problems found while documenting the structs
+	Diagnostics *diagnostic.List
 }
 
 // SearchForStructRoutes searches for struct routes
@@ -322,7 +462,19 @@ func (p *Project) FindFunc(fun *Function) error {
 
 // FindStruct find a struct in a package
 func (p *Project) FindStruct(str *Struct) error {
-	for _, p := range p.Pkgs {
+	// the package of the struct is searched first
+	pkgs := make([]*Pkg, 0, len(p.Pkgs))
+	for _, pkg := range p.Pkgs {
+		if pkg.Name == str.PkgName {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	for _, pkg := range p.Pkgs {
+		if pkg.Name != str.PkgName {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	for _, p := range pkgs {
 		err := p.FindStruct(str)
 		if err == nil {
 			return nil
@@ -334,10 +486,30 @@ func (p *Project) FindStruct(str *Struct) error {
 	return swagoErrors.ErrNotFound
 }
 
+// hasMethod returns true if a type declared in the project has a method
+func (p *Project) hasMethod(pkgName, typeName, method string) bool {
+	// methods are declared on the generic type, not on its instantiations
+	name, _ := splitTypeArgs(typeName)
+	for _, pkg := range p.Pkgs {
+		if pkg.Name != pkgName {
+			continue
+		}
+		for _, f := range pkg.Files {
+			for _, fun := range f.Functions {
+				if fun.Name == method && strings.TrimPrefix(fun.MemberOf, "*") == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 // Pkg is a package
 type Pkg struct {
 	Project   *Project
 	Name      string
+	Doc       string
 	Path      string
 	Files     []File
 	Logger    *log.Logger
@@ -378,17 +550,15 @@ func (p *Pkg) SearchForStructRoutes(structRoute criteria.StructRoute) []Route {
 	return routes
 }
 
-// FindFunc attempts to find a function in every file of the package
+// FindFunc attempts to find a function in every file of the package, a function with a
+// MemberOf is found among the methods of that receiver type
 func (p *Pkg) FindFunc(fun *Function) error {
-	for _, f := range p.Files {
-		err := f.FindFunc(fun)
-		if err == nil {
-			return nil
-		} else if err != swagoErrors.ErrNotFound {
-			return err
-		}
+	found := findFunc([]*Pkg{p}, strings.TrimPrefix(fun.MemberOf, "*"), fun.Name)
+	if found == nil {
+		return swagoErrors.ErrNotFound
 	}
-	return swagoErrors.ErrNotFound
+	*fun = *found
+	return nil
 }
 
 // FindStruct find a struct in a package
@@ -405,6 +575,28 @@ func (p *Pkg) FindStruct(str *Struct) error {
 	return swagoErrors.ErrNotFound
 }
 
+// isInterface returns true if an interface type is declared in the package
+func (p *Pkg) isInterface(name string) bool {
+	for _, f := range p.Files {
+		if f.File == nil {
+			continue
+		}
+		for _, d := range f.File.Decls {
+			gen, ok := d.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == name {
+					_, ok = ts.Type.(*ast.InterfaceType)
+					return ok
+				}
+			}
+		}
+	}
+	return false
+}
+
 func (p *Pkg) analyzeFile(file *File) error {
 	var err error
 	file.FSet, file.File, err = p.astForFile(file.Name)
@@ -414,6 +606,16 @@ func (p *Pkg) analyzeFile(file *File) error {
 	if p.Name != readFilePackage(file.File) {
 		return errFileNotInPackage
 	}
+	if len(p.Doc) == 0 && file.File.Doc != nil {
+		p.Doc = DocText("Package "+p.Name, file.File.Doc.Text())
+	}
+	file.extractDecls()
+	return nil
+}
+
+// extractDecls extracts the imports, the variables, the constants, the types and the
+// functions declared in a file
+func (file *File) extractDecls() {
 	// First parse imports
 	for _, d := range file.File.Decls {
 		switch x := d.(type) {
@@ -440,7 +642,6 @@ func (p *Pkg) analyzeFile(file *File) error {
 			file.extractFunction(x)
 		}
 	}
-	return nil
 }
 
 func (p *Pkg) astForFile(filePath string) (*token.FileSet, *ast.File, error) {
@@ -495,8 +696,7 @@ func generateImportMappings(f *File) {
 	if f.importMappings == nil {
 		f.importMappings = make(map[string]string)
 		for _, i := range f.Imports {
-			splitted := strings.Split(i.Pkg, "/")
-			packageName := splitted[len(splitted)-1]
+			packageName := importPkgName(i.Pkg)
 			if len(i.Name) > 0 {
 				f.importMappings[i.Name] = packageName
 			} else {
@@ -506,6 +706,45 @@ func generateImportMappings(f *File) {
 	}
 }
 
+// importPkgName returns the name of an imported package, versions are not part of
+// it as in github.com/labstack/echo/v4 or gopkg.in/guregu/null.v4
+func importPkgName(importPath string) string {
+	splitted := strings.Split(importPath, "/")
+	packageName := splitted[len(splitted)-1]
+	if len(splitted) > 1 && majorVersionRegExp.MatchString(packageName) {
+		packageName = splitted[len(splitted)-2]
+	}
+	packageName = majorVersionSuffixRegExp.ReplaceAllString(packageName, "")
+	return strings.TrimPrefix(packageName, "go.")
+}
+
+// TypeName returns a type as in pkg.Type, imported packages are named as in the criteria
+func (file *File) TypeName(expr ast.Expr) string {
+	return flattenType(expr, file.Pkg.Name, file.importMappings)
+}
+
+// CallName returns the function called as in pkg.Func or c.JSON, the package and the function
+// name of a call criteria are matched with it
+func (file *File) CallName(call *ast.CallExpr) string {
+	return flattenType(call.Fun, file.Pkg.Name, file.importMappings)
+}
+
+// ImportPath returns the path of an imported package given its name, which is the name
+// it is imported as or the name of the package
+func (file *File) ImportPath(pkgName string) string {
+	for _, i := range file.Imports {
+		if i.Name == pkgName || (len(i.Name) == 0 && importPkgName(i.Pkg) == pkgName) {
+			return i.Pkg
+		}
+	}
+	for _, i := range file.Imports {
+		if importPkgName(i.Pkg) == pkgName {
+			return i.Pkg
+		}
+	}
+	return ""
+}
+
 func astForFile(filePath string, fset *token.FileSet) (*ast.File, error) {
 	f, err := os.Open(filePath)
 	if err != nil {
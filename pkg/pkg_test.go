@@ -0,0 +1,26 @@
+package pkg
+
+import (
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/javiercbk/swago/diagnostic"
+)
+
+// parseTestFile analyzes the source of a file the way the files of a package are analyzed,
+// the package is the only package of a project
+func parseTestFile(t *testing.T, src string) *File {
+	t.Helper()
+	fset := token.NewFileSet()
+	astFile, err := astForReader("handler.go", strings.NewReader(src), fset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Pkg{Name: readFilePackage(astFile), Project: &Project{Diagnostics: &diagnostic.List{}}}
+	p.Project.Pkgs = []*Pkg{p}
+	p.Files = []File{{Pkg: p, Name: "handler.go", FSet: fset, File: astFile}}
+	file := &p.Files[0]
+	file.extractDecls()
+	return file
+}
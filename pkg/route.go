@@ -1,18 +1,24 @@
 package pkg
 
 import (
+	"go/token"
 	"regexp"
+	"strings"
 
 	"github.com/javiercbk/swago/criteria"
 )
 
 // Route is web route found in the folder
 type Route struct {
-	Pkg                        string
-	File                       string
-	Path                       string
-	HTTPMethod                 string
-	HandlerType                string
+	Pkg         string
+	File        string
+	Path        string
+	HTTPMethod  string
+	HandlerType string
+	// HandlerPkgPath is the directory of the package of the handler, it is empty when the
+	// package is not part of the project
+	HandlerPkgPath             string
+	Handler                    *Function
 	NamedPathVarExtractor      *regexp.Regexp
 	ChildRoutes                []*Route
 	Middlewares                []string
@@ -21,6 +27,29 @@ type Route struct {
 	Struct                     map[string]string
 	MatchedParameters          map[string]bool
 	MatchedSecurityDefinitions map[string]bool
+	// SecurityScopes are the scopes required for each matched security definition
+	SecurityScopes map[string][]string
+	// SecurityAlternatives are the security definitions that can replace a matched one
+	SecurityAlternatives map[string][]string
+	// Position is where the route is registered
+	Position token.Position
+	// Criteria describes the route criteria that matched the route
+	Criteria string
+}
+
+// HandlerParts returns the package, the receiver type and the name of a handler type as in
+// handler.UserHandler.List, the receiver is empty for functions
+func HandlerParts(handlerType string) (string, string, string) {
+	pkgName, name := TypeParts(handlerType)
+	if dot := strings.LastIndex(name, "."); dot > strings.LastIndex(name, "]") {
+		return pkgName, name[:dot], name[dot+1:]
+	}
+	return pkgName, "", name
+}
+
+// Endpoint returns the method and the path of a route as in POST /auth
+func (r Route) Endpoint() string {
+	return r.HTTPMethod + " " + r.Path
 }
 
 // ServiceResponse is the response from a service
@@ -28,4 +57,7 @@ type ServiceResponse struct {
 	Code           string
 	ModelExtractor criteria.ModelExtractor
 	Model          Struct
+	Headers        []string
+	// Position is where the response is written
+	Position token.Position
 }
@@ -1,16 +1,28 @@
 package pkg
 
 import (
+	"go/token"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/javiercbk/swago/criteria"
+	swagoErrors "github.com/javiercbk/swago/errors"
+)
+
+const (
+	marshalJSONMethod     = "MarshalJSON"
+	marshalTextMethod     = "MarshalText"
+	customJSONDescription = "Value with a custom JSON encoding"
+	// responseDefinitionSuffix names the definitions of the responses with required fields
+	responseDefinitionSuffix = "Response"
+	definitionsRefPrefix     = "#/definitions/"
 )
 
 var (
-	jsonNameExtractor = regexp.MustCompile(`json:"([a-zA-Z0-9_]+)[,"]`)
+	jsonNameExtractor  = regexp.MustCompile(`json:"([a-zA-Z0-9_]+)[,"]`)
+	omitEmptyExtractor = regexp.MustCompile(`json:"[^"]*,omitempty`)
 )
 
 func extractParamName(fieldName string, fieldTag string) string {
@@ -28,6 +40,32 @@ type Field struct {
 	Name string
 	Type string
 	Tag  string
+	Doc  string
+	// Inline is the anonymous struct of a field as in Meta struct{ Page int }
+	Inline *Struct
+}
+
+// SchemaOptions are the settings used to document a struct, the structs of its
+// fields are documented with the same options
+type SchemaOptions struct {
+	// Validations are the project wide validations, they do not depend on the
+	// call criteria so a struct has the same schema wherever it is used
+	Validations map[string]criteria.ValidationExtractor
+	// TypeOverrides are the schemas of the types that are not documented from their declaration
+	TypeOverrides map[string]criteria.TypeOverride
+	// Nullability is the policy used to document nullable and required fields
+	Nullability criteria.Nullability
+	// Response is true when the struct is sent in a response
+	Response bool
+	// ExamplePolicy defines how the examples of the fields are generated
+	ExamplePolicy criteria.Examples
+	// Examples are the values found in composite literals
+	Examples LiteralExamples
+	// Definitions receives the schemas of the structs of the fields, the fields reference
+	// them so a struct is documented once. The structs are inlined when it is nil
+	Definitions map[string]*openapi3.SchemaRef
+	// DefinitionPrefix prefixes the names of the definitions
+	DefinitionPrefix string
 }
 
 // Struct is a struct
@@ -35,28 +73,63 @@ type Struct struct {
 	File         *File
 	PkgName      string
 	Name         string
+	Doc          string
 	Fields       []Field
 	CallCriteria criteria.CallCriteria
-	Schema       *openapi3.Schema
+	// Options are the settings used to document the struct
+	Options SchemaOptions
+	Schema  *openapi3.Schema
+	Pos     token.Pos
+	// TypeParams are the type parameters of a generic struct
+	TypeParams []string
+	// TypeArgs are the type arguments of an instantiated generic struct
+	TypeArgs []string
+}
+
+// DefinitionName returns the name of the struct in the swagger definitions,
+// instantiated generic structs are named after their type arguments as in EnvelopeOfUser.
+// When the fields of a response are required the response is a definition of its own
+// named with a Response suffix, so a struct used in requests keeps its optional fields
+func (s *Struct) DefinitionName() string {
+	name := genericDefinitionName(s.Name, s.TypeArgs)
+	if s.Options.Response && s.Options.Nullability.RequiredResponseFields {
+		return name + responseDefinitionSuffix
+	}
+	return name
+}
+
+// QualifiedName returns the package and the name of the struct as in handler.User,
+// it is empty for anonymous structs
+func (s *Struct) QualifiedName() string {
+	if len(s.Name) == 0 {
+		return ""
+	}
+	structPkg := s.PkgName
+	if s.File != nil {
+		structPkg = s.File.Pkg.Name
+	}
+	return structPkg + "." + s.Name
 }
 
 // ToSwaggerSchema populates a given swagger schema with the data from the struct
 func (s *Struct) ToSwaggerSchema() error {
 	properties := make(map[string]*openapi3.SchemaRef)
 	requiredProps := make([]string, 0)
-	s.addEmbeddedStruct()
+	err := s.addEmbeddedStruct()
+	if err != nil {
+		return err
+	}
+	literalExamples := s.Options.Examples[s.QualifiedName()]
 	for _, f := range s.Fields {
 		paramName := extractParamName(f.Name, f.Tag)
 		t, format := swaggerType(f.Type)
-		if extractBooleanValidation(criteria.RequiredValidation, f.Tag, s.CallCriteria) {
-			requiredProps = append(requiredProps, paramName)
-		}
+		var sch *openapi3.Schema
+		ref := &openapi3.SchemaRef{}
 		if len(t) > 0 {
-			sch := &openapi3.Schema{
+			sch = &openapi3.Schema{
 				Type:         t,
-				ExclusiveMin: extractBooleanValidation(criteria.ExclusiveMinValidation, f.Tag, s.CallCriteria),
-				ExclusiveMax: extractBooleanValidation(criteria.ExclusiveMaxValidation, f.Tag, s.CallCriteria),
-				Enum:         matchesInterfaceSlice(criteria.EnumValidation, f.Tag, s.CallCriteria),
+				ExclusiveMin: extractBooleanValidation(criteria.ExclusiveMinValidation, f.Tag, s.Options.Validations),
+				ExclusiveMax: extractBooleanValidation(criteria.ExclusiveMaxValidation, f.Tag, s.Options.Validations),
 			}
 			if t != "array" {
 				sch.Format = format
@@ -67,96 +140,311 @@ func (s *Struct) ToSwaggerSchema() error {
 					},
 				}
 			}
-			schRef := &openapi3.SchemaRef{
-				Value: sch,
-			}
-			min, minOk := extractFloat64(criteria.MinimumValidation, f.Tag, s.CallCriteria)
-			max, maxOk := extractFloat64(criteria.MaximumValidation, f.Tag, s.CallCriteria)
-			minLength, minLengthOk := extractUint64(criteria.MinLengthValidation, f.Tag, s.CallCriteria)
-			maxLength, maxLengthOk := extractUint64(criteria.MaxLengthValidation, f.Tag, s.CallCriteria)
-			pattern, patternOk := extractString(criteria.PatternValidation, f.Tag, s.CallCriteria)
-			if minOk {
-				sch.Min = &min
-			}
-			if maxOk {
-				sch.Max = &max
-			}
-			if minLengthOk {
-				sch.MinLength = minLength
-			}
-			if maxLengthOk {
-				sch.MaxLength = &maxLength
-			}
-			if patternOk {
-				sch.Pattern = pattern
-			}
-			properties[paramName] = schRef
+			sch.Description = DocText(f.Name, f.Doc)
+			s.applyValidations(sch, f.Tag)
 		} else {
-			structPkg, structName := TypeParts(f.Type)
-			subStruct := Struct{
-				PkgName: structPkg,
-				Name:    structName,
+			var err error
+			if f.Type == UntypedValue {
+				sch = &openapi3.Schema{}
+			} else if f.Inline != nil {
+				sch, err = s.inlineSchema(f.Type, f.Inline)
+			} else {
+				ref, err = s.structSchema(f.Type, f.Name)
+				if err == nil {
+					sch = ref.Value
+				}
 			}
-			err := s.File.Pkg.Project.FindStruct(&subStruct)
 			if err != nil {
 				return err
 			}
-			subStruct.CallCriteria = s.CallCriteria
-			err = subStruct.ToSwaggerSchema()
-			if err != nil {
-				return err
+			if len(ref.Ref) > 0 {
+				// the field is described beside the reference to its struct
+				sch = &openapi3.Schema{}
 			}
-			schRef := &openapi3.SchemaRef{
-				Value: subStruct.Schema,
+			if len(f.Doc) > 0 {
+				sch.Description = DocText(f.Name, f.Doc)
 			}
-			properties[paramName] = schRef
 		}
+		if sch.Example == nil && sch.Type != swaggerObjectType && len(ref.Ref) == 0 {
+			if example, ok := literalExamples[f.Name]; ok {
+				sch.Example = example
+			} else if s.Options.ExamplePolicy.Synthesize {
+				sch.Example = synthesizedExample(sch)
+			}
+		}
+		if IsDeprecated(f.Doc) {
+			if sch.Extensions == nil {
+				sch.Extensions = make(map[string]interface{})
+			}
+			sch.Extensions[DeprecatedExtension] = true
+		}
+		pointer := strings.HasPrefix(f.Type, pointerPrefix)
+		omitEmpty := omitEmptyExtractor.MatchString(f.Tag)
+		if pointer && !omitEmpty && !s.Options.Nullability.IgnorePointers {
+			// a nil pointer is encoded as null unless it is omitted
+			sch.Nullable = true
+		}
+		if extractBooleanValidation(criteria.RequiredValidation, f.Tag, s.Options.Validations) {
+			requiredProps = append(requiredProps, paramName)
+		} else if s.Options.Response && s.Options.Nullability.RequiredResponseFields && !pointer && !omitEmpty {
+			requiredProps = append(requiredProps, paramName)
+		}
+		properties[paramName] = fieldSchemaRef(sch, ref)
 	}
 	s.Schema = &openapi3.Schema{}
 	s.Schema.Type = swaggerObjectType
+	s.Schema.Description = DocText(s.Name, s.Doc)
 	s.Schema.Required = requiredProps
 	s.Schema.Properties = properties
 	return nil
 }
 
-type embeddedStruct struct {
-	index int
-	pkg   string
-	name  string
+// structSchema returns the schema of a struct type or an array of structs, the struct is
+// referenced from the definitions when the options have definitions
+func (s *Struct) structSchema(t, fieldName string) (*openapi3.SchemaRef, error) {
+	t = strings.TrimPrefix(t, pointerPrefix)
+	if strings.HasPrefix(t, arrayPrefix) {
+		items, err := s.structSchema(t[len(arrayPrefix):], fieldName)
+		if err != nil {
+			return nil, err
+		}
+		return &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				Type:  "array",
+				Items: items,
+			},
+		}, nil
+	}
+	if swaggerT, format := swaggerType(t); len(swaggerT) > 0 {
+		return &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				Type:   swaggerT,
+				Format: format,
+			},
+		}, nil
+	}
+	if sch, ok := s.overriddenSchema(t); ok {
+		return &openapi3.SchemaRef{
+			Value: sch,
+		}, nil
+	}
+	structPkg, structName := TypeParts(t)
+	subStruct := Struct{
+		PkgName: structPkg,
+		Name:    structName,
+	}
+	err := s.File.Pkg.Project.FindStruct(&subStruct)
+	if err == swagoErrors.ErrNotFound {
+		s.File.Pkg.Project.Diagnostics.Warnf(s.Position(), "type %s of field %s.%s not found, it is documented as an object", t, s.QualifiedName(), fieldName)
+		return &openapi3.SchemaRef{
+			Value: &openapi3.Schema{
+				Type: swaggerObjectType,
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	subStruct.Options = s.Options
+	if s.Options.Definitions == nil {
+		err = subStruct.ToSwaggerSchema()
+		if err != nil {
+			return nil, err
+		}
+		return &openapi3.SchemaRef{
+			Value: subStruct.Schema,
+		}, nil
+	}
+	definitionName := s.Options.DefinitionPrefix + subStruct.DefinitionName()
+	if _, ok := s.Options.Definitions[definitionName]; !ok {
+		// the definition is added before its fields are documented so a struct
+		// that contains itself references its own definition
+		definition := &openapi3.SchemaRef{
+			Value: &openapi3.Schema{},
+		}
+		s.Options.Definitions[definitionName] = definition
+		err = subStruct.ToSwaggerSchema()
+		if err != nil {
+			return nil, err
+		}
+		definition.Value = subStruct.Schema
+	}
+	return &openapi3.SchemaRef{
+		Ref: definitionsRefPrefix + definitionName,
+	}, nil
 }
 
-func (s *Struct) addEmbeddedStruct() error {
-	embeddedStructs := make([]embeddedStruct, 0)
-	for i := range s.Fields {
-		if len(s.Fields[i].Name) == 0 {
-			pkg, name := TypeParts(s.Fields[i].Type)
-			embeddedStructs = append(embeddedStructs, embeddedStruct{
-				index: i,
-				pkg:   pkg,
-				name:  name,
-			})
+// fieldSchemaRef returns the schema of a field, a field that references the definition of
+// its struct and is described, nullable or deprecated wraps the reference in allOf
+func fieldSchemaRef(sch *openapi3.Schema, ref *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if len(ref.Ref) == 0 {
+		return &openapi3.SchemaRef{
+			Value: sch,
+		}
+	}
+	if len(sch.Description) == 0 && !sch.Nullable && len(sch.Extensions) == 0 {
+		return ref
+	}
+	sch.AllOf = []*openapi3.SchemaRef{ref}
+	return &openapi3.SchemaRef{
+		Value: sch,
+	}
+}
+
+// inlineSchema returns the schema of an anonymous struct or an array of anonymous structs
+func (s *Struct) inlineSchema(t string, inline *Struct) (*openapi3.Schema, error) {
+	t = strings.TrimPrefix(t, pointerPrefix)
+	if strings.HasPrefix(t, arrayPrefix) {
+		items, err := s.inlineSchema(t[len(arrayPrefix):], inline)
+		if err != nil {
+			return nil, err
 		}
+		return &openapi3.Schema{
+			Type: "array",
+			Items: &openapi3.SchemaRef{
+				Value: items,
+			},
+		}, nil
+	}
+	inline.Options = s.Options
+	err := inline.ToSwaggerSchema()
+	if err != nil {
+		return nil, err
+	}
+	return inline.Schema, nil
+}
+
+// overriddenSchema returns the schema of a type found in the type overrides, types
+// that implement json.Marshaler or encoding.TextMarshaler are not documented from
+// their fields, so they must be overridden or they are documented as strings
+func (s *Struct) overriddenSchema(t string) (*openapi3.Schema, bool) {
+	typePkg, typeName := TypeParts(t)
+	keys := []string{t}
+	if s.File != nil {
+		if importPath := s.File.ImportPath(typePkg); len(importPath) > 0 {
+			keys = append([]string{importPath + "." + typeName}, keys...)
+		}
+	}
+	for _, k := range keys {
+		if override, ok := s.Options.TypeOverrides[k]; ok {
+			return &openapi3.Schema{
+				Type:     override.Type,
+				Format:   override.Format,
+				Pattern:  override.Pattern,
+				Nullable: override.Nullable,
+			}, true
+		}
+	}
+	if s.File == nil || s.File.Pkg.Project == nil {
+		return nil, false
 	}
-	for i := len(embeddedStructs) - 1; i > 0; i-- {
-		idx := embeddedStructs[i].index
-		s.Fields = append(s.Fields[:idx], s.Fields[idx+1:]...)
+	project := s.File.Pkg.Project
+	if project.hasMethod(typePkg, typeName, marshalTextMethod) {
+		return &openapi3.Schema{
+			Type: "string",
+		}, true
 	}
-	for _, es := range embeddedStructs {
-		embeddedStruct := Struct{
-			Name: es.name,
+	if project.hasMethod(typePkg, typeName, marshalJSONMethod) {
+		// any value, the type decides its own json representation
+		return &openapi3.Schema{
+			Description: customJSONDescription,
+		}, true
+	}
+	return nil, false
+}
+
+// applyValidations populates a schema with every validation found in a tag
+func (s *Struct) applyValidations(sch *openapi3.Schema, tag string) {
+	enum := matchesInterfaceSlice(criteria.EnumValidation, tag, s.Options.Validations)
+	for _, e := range enum {
+		sch.Enum = append(sch.Enum, typedValue(sch.Type, e.(string)))
+	}
+	min, minOk := extractFloat64(criteria.MinimumValidation, tag, s.Options.Validations)
+	max, maxOk := extractFloat64(criteria.MaximumValidation, tag, s.Options.Validations)
+	multipleOf, multipleOfOk := extractFloat64(criteria.MultipleOfValidation, tag, s.Options.Validations)
+	minLength, minLengthOk := extractUint64(criteria.MinLengthValidation, tag, s.Options.Validations)
+	maxLength, maxLengthOk := extractUint64(criteria.MaxLengthValidation, tag, s.Options.Validations)
+	minItems, minItemsOk := extractUint64(criteria.MinItemsValidation, tag, s.Options.Validations)
+	maxItems, maxItemsOk := extractUint64(criteria.MaxItemsValidation, tag, s.Options.Validations)
+	pattern, patternOk := extractString(criteria.PatternValidation, tag, s.Options.Validations)
+	format, formatOk := extractString(criteria.FormatValidation, tag, s.Options.Validations)
+	defaultValue, defaultOk := extractString(criteria.DefaultValidation, tag, s.Options.Validations)
+	example, exampleOk := extractString(criteria.ExampleValidation, tag, s.Options.Validations)
+	switch sch.Type {
+	case "integer", "number":
+		if minOk {
+			sch.Min = &min
+		}
+		if maxOk {
+			sch.Max = &max
+		}
+		if multipleOfOk {
+			sch.MultipleOf = &multipleOf
+		}
+	case "string":
+		if minLengthOk {
+			sch.MinLength = minLength
+		}
+		if maxLengthOk {
+			sch.MaxLength = &maxLength
+		}
+		if patternOk {
+			sch.Pattern = pattern
+		}
+	}
+	if sch.Type == "array" {
+		if minItemsOk {
+			sch.MinItems = minItems
+		}
+		if maxItemsOk {
+			sch.MaxItems = &maxItems
+		}
+		sch.UniqueItems = extractBooleanValidation(criteria.UniqueItemsValidation, tag, s.Options.Validations)
+	} else if formatOk {
+		sch.Format = format
+	}
+	if defaultOk {
+		sch.Default = typedValue(sch.Type, defaultValue)
+	}
+	if exampleOk {
+		sch.Example = typedValue(sch.Type, example)
+	}
+}
+
+// addEmbeddedStruct replaces the embedded fields with the fields of the embedded structs
+func (s *Struct) addEmbeddedStruct() error {
+	fields := make([]Field, 0, len(s.Fields))
+	embeddedFields := make([]Field, 0)
+	for _, f := range s.Fields {
+		if len(f.Name) > 0 {
+			fields = append(fields, f)
+			continue
+		}
+		embeddedStruct := Struct{}
+		embeddedStruct.PkgName, embeddedStruct.Name = TypeParts(f.Type)
+		if s.File == nil {
+			continue
 		}
 		err := s.File.Pkg.Project.FindStruct(&embeddedStruct)
+		if err == swagoErrors.ErrNotFound {
+			s.File.Pkg.Project.Diagnostics.Warnf(s.Position(), "embedded struct %s of %s not found, its fields are not documented", f.Type, s.QualifiedName())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		err = embeddedStruct.addEmbeddedStruct()
 		if err != nil {
 			return err
 		}
-		embeddedStruct.addEmbeddedStruct()
-		s.Fields = append(s.Fields, embeddedStruct.Fields...)
+		embeddedFields = append(embeddedFields, embeddedStruct.Fields...)
 	}
+	s.Fields = append(fields, embeddedFields...)
 	return nil
 }
 
-func extractBooleanValidation(validationName string, tag string, callCriteria criteria.CallCriteria) bool {
-	e, ok := callCriteria.Validations[validationName]
+func extractBooleanValidation(validationName string, tag string, validations map[string]criteria.ValidationExtractor) bool {
+	e, ok := validations[validationName]
 	if ok {
 		for _, r := range e.TagRegexp {
 			if r.MatchString(tag) {
@@ -167,27 +455,36 @@ func extractBooleanValidation(validationName string, tag string, callCriteria cr
 	return false
 }
 
-func matchesInterfaceSlice(validationName string, tag string, callCriteria criteria.CallCriteria) []interface{} {
+func matchesInterfaceSlice(validationName string, tag string, validations map[string]criteria.ValidationExtractor) []interface{} {
 	enumItems := make([]interface{}, 0)
-	e, ok := callCriteria.Validations[validationName]
+	e, ok := validations[validationName]
 	if ok {
 		for _, r := range e.TagRegexp {
 			found := r.FindAllStringSubmatch(tag, -1)
 			for _, f := range found {
-				enumItems = append(enumItems, f[1])
+				value := capturedValue(r, f)
+				if len(e.Separator) == 0 {
+					enumItems = append(enumItems, value)
+					continue
+				}
+				for _, item := range strings.Split(value, e.Separator) {
+					if len(item) > 0 {
+						enumItems = append(enumItems, item)
+					}
+				}
 			}
 		}
 	}
 	return enumItems
 }
 
-func extractFloat64(validationName string, tag string, callCriteria criteria.CallCriteria) (float64, bool) {
-	e, ok := callCriteria.Validations[validationName]
+func extractFloat64(validationName string, tag string, validations map[string]criteria.ValidationExtractor) (float64, bool) {
+	e, ok := validations[validationName]
 	if ok {
 		for _, r := range e.TagRegexp {
 			found := r.FindStringSubmatch(tag)
 			if len(found) > 0 {
-				parsed, err := strconv.ParseFloat(found[0], 64)
+				parsed, err := strconv.ParseFloat(capturedValue(r, found), 64)
 				if err == nil {
 					return parsed, true
 				}
@@ -197,13 +494,13 @@ func extractFloat64(validationName string, tag string, callCriteria criteria.Cal
 	return 0, false
 }
 
-func extractUint64(validationName string, tag string, callCriteria criteria.CallCriteria) (uint64, bool) {
-	e, ok := callCriteria.Validations[validationName]
+func extractUint64(validationName string, tag string, validations map[string]criteria.ValidationExtractor) (uint64, bool) {
+	e, ok := validations[validationName]
 	if ok {
 		for _, r := range e.TagRegexp {
 			found := r.FindStringSubmatch(tag)
 			if len(found) > 0 {
-				parsed, err := strconv.ParseUint(found[0], 10, 64)
+				parsed, err := strconv.ParseUint(capturedValue(r, found), 10, 64)
 				if err == nil {
 					return parsed, true
 				}
@@ -213,15 +510,51 @@ func extractUint64(validationName string, tag string, callCriteria criteria.Call
 	return 0, false
 }
 
-func extractString(validationName string, tag string, callCriteria criteria.CallCriteria) (string, bool) {
-	e, ok := callCriteria.Validations[validationName]
+func extractString(validationName string, tag string, validations map[string]criteria.ValidationExtractor) (string, bool) {
+	e, ok := validations[validationName]
 	if ok {
 		for _, r := range e.TagRegexp {
 			found := r.FindStringSubmatch(tag)
 			if len(found) > 0 {
-				return found[0], true
+				return capturedValue(r, found), true
 			}
 		}
 	}
 	return "", false
 }
+
+// capturedValue returns the value of a validation match, which is the named
+// group "value" if present, then the first capture group and then the whole match
+func capturedValue(r *regexp.Regexp, found []string) string {
+	for i, name := range r.SubexpNames() {
+		if name == criteria.ValidationValueGroup && i < len(found) {
+			return found[i]
+		}
+	}
+	if len(found) > 1 {
+		return found[1]
+	}
+	return found[0]
+}
+
+// typedValue converts a value found in a tag to the go value matching a swagger type
+func typedValue(swaggerType string, value string) interface{} {
+	switch swaggerType {
+	case "integer":
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			return parsed
+		}
+	case "number":
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return parsed
+		}
+	case "boolean":
+		parsed, err := strconv.ParseBool(value)
+		if err == nil {
+			return parsed
+		}
+	}
+	return value
+}
@@ -0,0 +1,420 @@
+package pkg
+
+import (
+	"io/ioutil"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyValidations(t *testing.T) {
+	validations := map[string]criteria.ValidationExtractor{
+		criteria.MinimumValidation: {
+			TagRegexp: []*regexp.Regexp{regexp.MustCompile(`validate:"[^"]*min=(\d+)`)},
+		},
+		criteria.MaxLengthValidation: {
+			TagRegexp: []*regexp.Regexp{
+				regexp.MustCompile(`validate:"[^"]*max=(?P<value>\d+)`),
+				regexp.MustCompile(`validate:"[^"]*lte=(?P<value>\d+)`),
+			},
+		},
+		criteria.PatternValidation: {
+			TagRegexp: []*regexp.Regexp{regexp.MustCompile(`pattern:"([^"]+)"`)},
+		},
+		criteria.EnumValidation: {
+			Separator: " ",
+			TagRegexp: []*regexp.Regexp{regexp.MustCompile(`validate:"[^"]*oneof=(?P<value>[^,"]+)`)},
+		},
+		criteria.DefaultValidation: {
+			TagRegexp: []*regexp.Regexp{regexp.MustCompile(`default:"([^"]*)"`)},
+		},
+		criteria.MinItemsValidation: {
+			TagRegexp: []*regexp.Regexp{regexp.MustCompile(`validate:"[^"]*min=(\d+)`)},
+		},
+		criteria.UniqueItemsValidation: {
+			TagRegexp: []*regexp.Regexp{regexp.MustCompile(`validate:"[^"]*unique`)},
+		},
+	}
+	type params struct {
+		swaggerType string
+		tag         string
+	}
+	type expected struct {
+		min        *float64
+		maxLength  *uint64
+		pattern    string
+		enum       []interface{}
+		def        interface{}
+		minItems   uint64
+		uniqueItem bool
+	}
+	ten := float64(10)
+	thirtyTwo := uint64(32)
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name: "should use the first capture group",
+			params: params{
+				swaggerType: "integer",
+				tag:         `json:"age" validate:"min=10" default:"18"`,
+			},
+			expected: expected{
+				min: &ten,
+				def: int64(18),
+			},
+		},
+		{
+			name: "should use the named capture group from any regexp",
+			params: params{
+				swaggerType: "string",
+				tag:         `json:"name" validate:"required,lte=32" pattern:"^[a-z]+$"`,
+			},
+			expected: expected{
+				maxLength: &thirtyTwo,
+				pattern:   "^[a-z]+$",
+			},
+		},
+		{
+			name: "should split enum values",
+			params: params{
+				swaggerType: "string",
+				tag:         `json:"color" validate:"oneof=red green blue"`,
+			},
+			expected: expected{
+				enum: []interface{}{"red", "green", "blue"},
+			},
+		},
+		{
+			name: "should only extract array validations on arrays",
+			params: params{
+				swaggerType: "array",
+				tag:         `json:"ids" validate:"min=1,unique"`,
+			},
+			expected: expected{
+				min:        nil,
+				minItems:   1,
+				uniqueItem: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Struct{Options: SchemaOptions{Validations: validations}}
+			sch := &openapi3.Schema{Type: tt.params.swaggerType}
+			s.applyValidations(sch, tt.params.tag)
+			assert.Equal(t, tt.expected.min, sch.Min)
+			assert.Equal(t, tt.expected.maxLength, sch.MaxLength)
+			assert.Equal(t, tt.expected.pattern, sch.Pattern)
+			assert.Equal(t, tt.expected.enum, sch.Enum)
+			assert.Equal(t, tt.expected.def, sch.Default)
+			assert.Equal(t, tt.expected.minItems, sch.MinItems)
+			assert.Equal(t, tt.expected.uniqueItem, sch.UniqueItems)
+		})
+	}
+}
+
+func TestOverriddenSchema(t *testing.T) {
+	file := &File{
+		Pkg: &Pkg{Name: "handler"},
+		Imports: []Import{
+			{Pkg: "github.com/google/uuid"},
+			{Pkg: "gopkg.in/guregu/null.v4"},
+			{Name: "dec", Pkg: "github.com/shopspring/decimal"},
+		},
+	}
+	s := &Struct{
+		File:    file,
+		Options: SchemaOptions{TypeOverrides: criteria.DefaultTypeOverrides()},
+	}
+	type params struct {
+		t string
+	}
+	type expected struct {
+		found    bool
+		typ      string
+		format   string
+		nullable bool
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should find a type by its import path",
+			params:   params{t: "uuid.UUID"},
+			expected: expected{found: true, typ: "string", format: "uuid"},
+		},
+		{
+			name:     "should ignore the version of a package",
+			params:   params{t: "null.String"},
+			expected: expected{found: true, typ: "string", nullable: true},
+		},
+		{
+			name:     "should find an aliased package",
+			params:   params{t: "dec.Decimal"},
+			expected: expected{found: true, typ: "string", format: "decimal"},
+		},
+		{
+			name:   "should not find types without overrides",
+			params: params{t: "handler.User"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sch, found := s.overriddenSchema(test.params.t)
+			assert.Equal(t, test.expected.found, found)
+			if found {
+				assert.Equal(t, test.expected.typ, sch.Type)
+				assert.Equal(t, test.expected.format, sch.Format)
+				assert.Equal(t, test.expected.nullable, sch.Nullable)
+			}
+		})
+	}
+}
+
+func TestNullability(t *testing.T) {
+	fields := []Field{
+		{Name: "ID", Type: "int", Tag: `json:"id"`},
+		{Name: "Name", Type: "*string", Tag: `json:"name"`},
+		{Name: "Nick", Type: "*string", Tag: `json:"nick,omitempty"`},
+		{Name: "Age", Type: "int", Tag: `json:"age,omitempty"`},
+	}
+	type params struct {
+		response    bool
+		nullability criteria.Nullability
+	}
+	type expected struct {
+		nullable       []string
+		required       []string
+		definitionName string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:   "should mark the pointers that are not omitted as nullable",
+			params: params{response: true},
+			expected: expected{
+				nullable:       []string{"name"},
+				required:       []string{},
+				definitionName: "User",
+			},
+		},
+		{
+			name: "should ignore pointers",
+			params: params{
+				response:    true,
+				nullability: criteria.Nullability{IgnorePointers: true},
+			},
+			expected: expected{
+				nullable:       []string{},
+				required:       []string{},
+				definitionName: "User",
+			},
+		},
+		{
+			name: "should require the fields that are always present in a response and name its definition apart",
+			params: params{
+				response:    true,
+				nullability: criteria.Nullability{RequiredResponseFields: true},
+			},
+			expected: expected{
+				nullable:       []string{"name"},
+				required:       []string{"id"},
+				definitionName: "UserResponse",
+			},
+		},
+		{
+			name: "should not require the fields of a request",
+			params: params{
+				nullability: criteria.Nullability{RequiredResponseFields: true},
+			},
+			expected: expected{
+				nullable:       []string{"name"},
+				required:       []string{},
+				definitionName: "User",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := Struct{
+				Name:   "User",
+				Fields: fields,
+				Options: SchemaOptions{
+					Nullability: test.params.nullability,
+					Response:    test.params.response,
+				},
+			}
+			err := s.ToSwaggerSchema()
+			assert.Nil(t, err)
+			nullable := make([]string, 0)
+			for _, f := range fields {
+				name := extractParamName(f.Name, f.Tag)
+				if s.Schema.Properties[name].Value.Nullable {
+					nullable = append(nullable, name)
+				}
+			}
+			assert.Equal(t, test.expected.nullable, nullable)
+			assert.Equal(t, test.expected.required, s.Schema.Required)
+			assert.Equal(t, test.expected.definitionName, s.DefinitionName())
+		})
+	}
+}
+
+const embeddedSrc = `package handler
+
+type Base struct {
+	ID int ` + "`json:\"id\"`" + `
+}
+
+type Audit struct {
+	Base
+	By string ` + "`json:\"by\"`" + `
+}
+
+type User struct {
+	*Audit
+	Missing
+	Name string ` + "`json:\"name\"`" + `
+}
+`
+
+func TestAddEmbeddedStruct(t *testing.T) {
+	file := parseTestFile(t, embeddedSrc)
+	p := file.Pkg
+	diagnostics := p.Project.Diagnostics
+	user := Struct{PkgName: "handler", Name: "User"}
+	err := p.FindStruct(&user)
+	assert.Nil(t, err)
+	err = user.addEmbeddedStruct()
+	assert.Nil(t, err)
+	names := make([]string, 0, len(user.Fields))
+	for _, f := range user.Fields {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"Name", "By", "ID"}, names)
+	if assert.Equal(t, 1, diagnostics.Len()) {
+		d := diagnostics.Diagnostics()[0]
+		assert.Equal(t, diagnostic.Warning, d.Severity)
+		assert.Equal(t, 12, d.Position.Line)
+		assert.Contains(t, d.Message, "handler.Missing")
+	}
+}
+
+const topLevelValidationsSrc = `package handler
+
+type Address struct {
+	Country string ` + "`json:\"country\" validate:\"required,oneof=ar uy\"`" + `
+	Zip     string ` + "`json:\"zip\" pattern:\"^[0-9]+$\"`" + `
+}
+
+type User struct {
+	Name    string  ` + "`json:\"name\" validate:\"required\"`" + `
+	Role    string  ` + "`json:\"role\" validate:\"oneof=admin user\" pattern:\"^[a-z]+$\"`" + `
+	Address Address ` + "`json:\"address\"`" + `
+}
+`
+
+const topLevelValidationsCriteria = `
+response:
+  - pkg: api
+    funcName: JSON
+    modelExtractor:
+      paramIndex: 2
+validations:
+  required:
+    tag: ['validate:"[^"]*required']
+  enum:
+    separator: " "
+    tag: ['validate:"[^"]*oneof=(?P<value>[^,"]+)']
+  pattern:
+    tag: ['pattern:"([^"]+)"']
+`
+
+func TestTopLevelValidations(t *testing.T) {
+	c := criteria.Criteria{}
+	if err := criteria.NewCriteriaDecoder(log.New(ioutil.Discard, "", 0)).ParseCriteriaFromYAML(strings.NewReader(topLevelValidationsCriteria), &c); err != nil {
+		t.Fatal(err)
+	}
+	type params struct {
+		nested      bool
+		definitions bool
+		field       string
+	}
+	type expected struct {
+		required []string
+		enum     []interface{}
+		pattern  string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:   "should apply the top level validations to a response model",
+			params: params{field: "role"},
+			expected: expected{
+				required: []string{"name"},
+				enum:     []interface{}{"admin", "user"},
+				pattern:  "^[a-z]+$",
+			},
+		},
+		{
+			name:   "should apply the top level validations to an inlined nested struct",
+			params: params{nested: true, field: "zip"},
+			expected: expected{
+				required: []string{"country"},
+				pattern:  "^[0-9]+$",
+			},
+		},
+		{
+			name:   "should apply the top level validations to the definition of a nested struct",
+			params: params{nested: true, definitions: true, field: "country"},
+			expected: expected{
+				required: []string{"country"},
+				enum:     []interface{}{"ar", "uy"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := parseTestFile(t, topLevelValidationsSrc)
+			user := Struct{PkgName: "handler", Name: "User"}
+			if err := file.Pkg.FindStruct(&user); err != nil {
+				t.Fatal(err)
+			}
+			user.Options = SchemaOptions{Validations: c.Validations, Response: true}
+			if test.params.definitions {
+				user.Options.Definitions = make(map[string]*openapi3.SchemaRef)
+			}
+			if !assert.Nil(t, user.ToSwaggerSchema()) {
+				return
+			}
+			sch := user.Schema
+			if test.params.definitions {
+				assert.Equal(t, "#/definitions/Address", sch.Properties["address"].Ref)
+				sch = user.Options.Definitions["Address"].Value
+			} else if test.params.nested {
+				sch = sch.Properties["address"].Value
+			}
+			assert.Equal(t, test.expected.required, sch.Required)
+			field := sch.Properties[test.params.field].Value
+			assert.Equal(t, test.expected.enum, field.Enum)
+			assert.Equal(t, test.expected.pattern, field.Pattern)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"go/token"
+
+	"github.com/javiercbk/swago/criteria"
+)
+
+// CallTrace is a call expression of a function as it is considered by a call criteria
+type CallTrace struct {
+	Position token.Position
+	// Call is the called function as in c.Bind
+	Call string
+	// Matched is true when the call matches the package and the name of the criteria
+	Matched bool
+	// Arg is the model argument of a matched call
+	Arg string
+	// Variable is the variable passed as the model, it is empty when the argument is not a variable
+	Variable string
+	// VariableType is the inferred type of the variable, it is empty when it can not be inferred
+	VariableType string
+	// Used is true for the call the model is taken from, which is the first matched call
+	// with a variable as its argument
+	Used bool
+}
+
+// Position returns where a function is declared
+func (f Function) Position() token.Position {
+	return f.PositionOf(f.Pos)
+}
+
+// PositionOf returns the position of a node of a function
+func (f Function) PositionOf(pos token.Pos) token.Position {
+	if f.File == nil || f.File.FSet == nil {
+		return token.Position{}
+	}
+	return f.File.FSet.Position(pos)
+}
+
+// Position returns where a struct is declared
+func (s *Struct) Position() token.Position {
+	if s.File == nil || s.File.FSet == nil {
+		return token.Position{}
+	}
+	return s.File.FSet.Position(s.Pos)
+}
+
+// TraceCalls returns the call expressions of a function as FindArgTypeCallExpression considers
+// them, calls nested in the arguments of another call are not considered
+func (f Function) TraceCalls(callCriteria criteria.CallCriteria) []CallTrace {
+	traces := make([]CallTrace, 0)
+	f.findArgTypeCallExpression(callCriteria, func(ct CallTrace) {
+		traces = append(traces, ct)
+	})
+	return traces
+}
@@ -0,0 +1,87 @@
+package pkg
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/javiercbk/swago/criteria"
+	"github.com/stretchr/testify/assert"
+)
+
+const traceSrc = `package handler
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	request := Request{}
+	api.Decode(r, &request)
+	api.Decode(r, decode(body))
+	api.Decode(r, other)
+	api.Decode(r)
+}
+
+func handleLater(w http.ResponseWriter, r *http.Request) {
+	log.Print(r)
+	api.Decode(r, decode(body))
+	request := Request{}
+	api.Decode(r, &request)
+}
+`
+
+func TestTraceCalls(t *testing.T) {
+	file := parseTestFile(t, traceSrc)
+	callCriteria := criteria.CallCriteria{
+		Pkg:            "api",
+		FuncName:       "Decode",
+		ModelExtractor: criteria.ModelExtractor{ParamIndex: 1},
+	}
+	type params struct {
+		function int
+	}
+	type expected struct {
+		traces  []CallTrace
+		argType string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:   "should use the first call with a variable",
+			params: params{function: 0},
+			expected: expected{
+				traces: []CallTrace{
+					{Position: token.Position{Line: 5}, Call: "api.Decode", Matched: true, Arg: "&request", Variable: "request", VariableType: "handler.Request", Used: true},
+					{Position: token.Position{Line: 6}, Call: "api.Decode", Matched: true, Arg: "decode(body)"},
+					{Position: token.Position{Line: 7}, Call: "api.Decode", Matched: true, Arg: "other", Variable: "other"},
+					{Position: token.Position{Line: 8}, Call: "api.Decode", Matched: true},
+				},
+				argType: "handler.Request",
+			},
+		},
+		{
+			name:   "should skip the calls without a variable",
+			params: params{function: 1},
+			expected: expected{
+				traces: []CallTrace{
+					{Position: token.Position{Line: 12}, Call: "log.Print"},
+					{Position: token.Position{Line: 13}, Call: "api.Decode", Matched: true, Arg: "decode(body)"},
+					{Position: token.Position{Line: 15}, Call: "api.Decode", Matched: true, Arg: "&request", Variable: "request", VariableType: "handler.Request", Used: true},
+				},
+				argType: "handler.Request",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := file.Functions[test.params.function]
+			traces := f.TraceCalls(callCriteria)
+			for i := range traces {
+				traces[i].Position = token.Position{Line: traces[i].Position.Line}
+			}
+			assert.Equal(t, test.expected.traces, traces)
+			argType, err := f.FindArgTypeCallExpression(callCriteria)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected.argType, argType)
+		})
+	}
+}
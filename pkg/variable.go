@@ -187,11 +187,7 @@ func isGoType(t string) bool {
 }
 
 func flattenType(n ast.Node, fallbackPkg string, importMappings map[string]string) string {
-	flattenedType := rawFlattenType(n, importMappings)
-	if !strings.Contains(flattenedType, ".") && !isGoType(flattenedType) {
-		flattenedType = fallbackPkg + "." + flattenedType
-	}
-	return flattenedType
+	return qualifyType(rawFlattenType(n, importMappings), fallbackPkg)
 }
 
 func rawFlattenType(n ast.Node, importMappings map[string]string) string {
@@ -203,7 +199,15 @@ func rawFlattenType(n ast.Node, importMappings map[string]string) string {
 		if ok {
 			return mapped
 		}
+		if x.Name == goTypeAny {
+			return EmptyInterface
+		}
 		return x.Name
+	case *ast.InterfaceType:
+		if x.Methods == nil || len(x.Methods.List) == 0 {
+			return EmptyInterface
+		}
+		return ""
 	case *ast.UnaryExpr:
 		if x.Op == token.AND {
 			return "*" + rawFlattenType(x.X, importMappings)
@@ -215,20 +219,41 @@ func rawFlattenType(n ast.Node, importMappings map[string]string) string {
 		return rawFlattenType(x.Type, importMappings)
 	case *ast.ArrayType:
 		return "[]" + rawFlattenType(x.Elt, importMappings)
+	case *ast.StructType:
+		return AnonymousStruct
+	case *ast.IndexExpr:
+		// generic type instantiation as in Envelope[User]
+		return rawFlattenType(x.X, importMappings) + "[" + rawFlattenType(x.Index, importMappings) + "]"
+	case *ast.IndexListExpr:
+		args := make([]string, len(x.Indices))
+		for i := range x.Indices {
+			args[i] = rawFlattenType(x.Indices[i], importMappings)
+		}
+		return rawFlattenType(x.X, importMappings) + "[" + strings.Join(args, ",") + "]"
 	default:
 		return ""
 	}
 }
 
-// TypeParts returns the package and the name of a flattened type
+// TypeParts returns the package and the name of a flattened type, the package is what is
+// before the first dot so the prefix of a slice or a map stays with it as in []pkg and T.
+// Generic types are split before their type arguments as long as they are qualified, as
+// in pkg.Envelope[pkg.User], which qualifyType does
 func TypeParts(selector string) (string, string) {
-	parts := strings.Split(selector, ".")
-	if len(parts) > 1 {
-		return parts[0], parts[1]
+	selector = strings.TrimLeft(selector, pointerPrefix)
+	dot := strings.Index(selector, ".")
+	if dot != -1 {
+		return selector[:dot], selector[dot+1:]
 	}
 	return "", selector
 }
 
+// SwaggerType returns the swagger type and format of a go type, when the type
+// is an array the format is the type of its items
+func SwaggerType(t string) (string, string) {
+	return swaggerType(t)
+}
+
 func swaggerType(t string) (string, string) {
 	if strings.HasPrefix(t, "*") {
 		t = t[1:]
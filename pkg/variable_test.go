@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeParts(t *testing.T) {
+	type expected struct {
+		pkg  string
+		name string
+	}
+	tests := []struct {
+		name     string
+		params   string
+		expected expected
+	}{
+		{
+			name:     "should split a type",
+			params:   "pkg.T",
+			expected: expected{pkg: "pkg", name: "T"},
+		},
+		{
+			name:     "should keep the slice prefix with the package",
+			params:   "[]pkg.T",
+			expected: expected{pkg: "[]pkg", name: "T"},
+		},
+		{
+			name:     "should dereference a pointer",
+			params:   "*pkg.T",
+			expected: expected{pkg: "pkg", name: "T"},
+		},
+		{
+			name:     "should split a generic type before its type arguments",
+			params:   "pkg.G[pkg.T]",
+			expected: expected{pkg: "pkg", name: "G[pkg.T]"},
+		},
+		{
+			name:     "should keep the map prefix with the package",
+			params:   "map[string]pkg.T",
+			expected: expected{pkg: "map[string]pkg", name: "T"},
+		},
+		{
+			name:     "should not split a type without a package",
+			params:   "int",
+			expected: expected{name: "int"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pkg, name := TypeParts(test.params)
+			assert.Equal(t, test.expected.pkg, pkg)
+			assert.Equal(t, test.expected.name, name)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package swago
+
+import (
+	"go/token"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	swagoErrors "github.com/javiercbk/swago/errors"
+	"github.com/javiercbk/swago/pkg"
+)
+
+const (
+	defaultResponseDescription = "Unexpected error"
+	definitionsRefPrefix       = "#/definitions/"
+	// configCriteria names the diagnostics about the models declared in the config
+	configCriteria = "config"
+)
+
+// addGlobalResponses declares the global responses in the swagger document and
+// returns the reference to each of them by status code, the responses without a
+// model reference the error model
+func (s *SwaggerGenerator) addGlobalResponses(projectCriterias criteria.Criteria, errorModel *openapi3.SchemaRef, swaggerDoc *openapi2.Swagger) (map[string]string, error) {
+	refs := make(map[string]string, len(projectCriterias.GlobalResponses))
+	for code, gr := range projectCriterias.GlobalResponses {
+		name := gr.Name
+		if len(name) == 0 {
+			name = globalResponseName(code)
+		}
+		description := gr.Description
+		if len(description) == 0 {
+			description = statusDescription(code)
+		}
+		resp := &openapi2.Response{
+			Description: description,
+			Schema:      errorModel,
+		}
+		if len(gr.Model) > 0 {
+			schemaRef, err := s.configModelRef(gr.Model, "globalResponses."+code+".model", len(projectCriterias.DefinitionPrefix) > 0, projectCriterias, swaggerDoc)
+			if err != nil {
+				s.logger.Printf("error finding model %s of global response %s: %v\n", gr.Model, code, err)
+				return refs, err
+			}
+			resp.Schema = schemaRef
+		}
+		if swaggerDoc.Responses == nil {
+			swaggerDoc.Responses = make(map[string]*openapi2.Response)
+		}
+		swaggerDoc.Responses[name] = resp
+		refs[code] = "#/responses/" + name
+	}
+	if projectCriterias.Examples.Responses {
+		addResponseExamples(swaggerDoc.Responses, criteria.MIMEApplicationJSON, swaggerDoc)
+	}
+	return refs, nil
+}
+
+// addErrorModel documents the error model once as a definition, whatever the definition
+// prefix is, and returns the reference to it. It is nil when there is no error model
+func (s *SwaggerGenerator) addErrorModel(projectCriterias criteria.Criteria, swaggerDoc *openapi2.Swagger) (*openapi3.SchemaRef, error) {
+	if len(projectCriterias.ErrorModel) == 0 {
+		return nil, nil
+	}
+	schemaRef, err := s.configModelRef(projectCriterias.ErrorModel, "errorModel", true, projectCriterias, swaggerDoc)
+	if err != nil {
+		s.logger.Printf("error finding error model %s: %v\n", projectCriterias.ErrorModel, err)
+	}
+	return schemaRef, err
+}
+
+// configModelRef returns the schema of a model declared in the config, a model that
+// is not found is reported and has no schema
+func (s *SwaggerGenerator) configModelRef(model, key string, definition bool, projectCriterias criteria.Criteria, swaggerDoc *openapi2.Swagger) (*openapi3.SchemaRef, error) {
+	schemaRef, err := s.modelSchemaRef(model, true, definition, projectCriterias, swaggerDoc)
+	if err == swagoErrors.ErrNotFound {
+		s.report(diagnostic.Warning, nil, configCriteria+" "+key, token.Position{}, "model %s not found, the responses that use it have no schema", model)
+		return nil, nil
+	}
+	return schemaRef, err
+}
+
+// addResponseExamples adds an example of the whole model to the responses that have a schema
+func addResponseExamples(responses map[string]*openapi2.Response, mime string, swaggerDoc *openapi2.Swagger) {
+	if len(mime) == 0 {
+		mime = criteria.MIMEApplicationJSON
+	}
+	for _, resp := range responses {
+		if len(resp.Ref) > 0 || resp.Schema == nil || len(resp.Examples) > 0 {
+			continue
+		}
+		example := pkg.SchemaExample(definitionSchema(resp.Schema, swaggerDoc))
+		if example != nil {
+			resp.Examples = map[string]interface{}{
+				mime: example,
+			}
+		}
+	}
+}
+
+// definitionSchema returns the schema of a schema reference, following references to the definitions
+func definitionSchema(schemaRef *openapi3.SchemaRef, swaggerDoc *openapi2.Swagger) *openapi3.Schema {
+	if len(schemaRef.Ref) == 0 {
+		return schemaRef.Value
+	}
+	definition, ok := swaggerDoc.Definitions[strings.TrimPrefix(schemaRef.Ref, definitionsRefPrefix)]
+	if !ok {
+		return nil
+	}
+	return definition.Value
+}
+
+// errorResponse returns the response of an error status code, global responses
+// are referenced and the rest use the error model
+func errorResponse(code string, globalRefs map[string]string, errorModel *openapi3.SchemaRef) *openapi2.Response {
+	if ref, ok := globalRefs[code]; ok {
+		return &openapi2.Response{
+			Ref: ref,
+		}
+	}
+	if errorModel == nil {
+		return nil
+	}
+	return &openapi2.Response{
+		Description: statusDescription(code),
+		Schema:      errorModel,
+	}
+}
+
+// addGlobalResponseRefs references the global responses that an operation does not declare
+func addGlobalResponseRefs(operation *openapi2.Operation, globalRefs map[string]string, projectCriterias criteria.Criteria) {
+	secured := operation.Security != nil && len(*operation.Security) > 0
+	for code, ref := range globalRefs {
+		if projectCriterias.GlobalResponses[code].SecuredOnly && !secured {
+			continue
+		}
+		if operation.Responses == nil {
+			operation.Responses = make(map[string]*openapi2.Response)
+		}
+		if _, ok := operation.Responses[code]; !ok {
+			operation.Responses[code] = &openapi2.Response{
+				Ref: ref,
+			}
+		}
+	}
+}
+
+// globalResponseName returns the name of a response from its status text, as in InternalServerError
+func globalResponseName(code string) string {
+	if code == criteria.DefaultResponseCode {
+		return "DefaultError"
+	}
+	statusCode, _ := strconv.Atoi(code)
+	return strings.NewReplacer(" ", "", "-", "", "'", "").Replace(http.StatusText(statusCode))
+}
+
+func statusDescription(code string) string {
+	if code == criteria.DefaultResponseCode {
+		return defaultResponseDescription
+	}
+	statusCode, _ := strconv.Atoi(code)
+	return http.StatusText(statusCode)
+}
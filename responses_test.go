@@ -0,0 +1,266 @@
+package swago
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/stretchr/testify/assert"
+)
+
+const responsesCriteria = `
+routes:
+  - funcRoute:
+      pkg: router
+      funcName: POST
+      pathIndex: 0
+      handlerIndex: 1
+request:
+  - pkg: api
+    funcName: Bind
+    modelExtractor:
+      paramIndex: 1
+response:
+  - pkg: api
+    funcName: JSON
+    modelExtractor:
+      paramIndex: 2
+    codeIndex: 1
+  - pkg: api
+    funcName: Error
+    modelExtractor:
+      name: Error
+    codeIndex: 1
+errorModel: handler.Failure
+globalResponses:
+  "500": {}
+  "404":
+    model: handler.Nope
+  "401":
+    securedOnly: true
+examples:
+  responses: true
+`
+
+var responsesProject = map[string]string{
+	"go.mod": "module example.com/api\n",
+	"main.go": `package main
+
+import (
+	"example.com/api/handler"
+	"example.com/api/router"
+)
+
+func main() {
+	router.POST("/users", handler.CreateUser)
+}
+`,
+	"router/router.go": `package router
+
+import "net/http"
+
+func POST(path string, handler http.HandlerFunc) {}
+`,
+	"handler/handler.go": `package handler
+
+import (
+	"net/http"
+
+	"example.com/api/api"
+)
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Failure struct {
+	Message string ` + "`json:\"message\" example:\"user not found\"`" + `
+}
+
+func CreateUser(w http.ResponseWriter, r *http.Request) {
+	user := User{}
+	api.Bind(r, &user)
+	if len(user.Name) == 0 {
+		api.Error(w, 400)
+		return
+	}
+	if user.Name == "root" {
+		api.Error(w, 500)
+		return
+	}
+	api.JSON(w, 201, &user)
+}
+`,
+	"api/api.go": `package api
+
+import "net/http"
+
+func Bind(r *http.Request, v interface{}) error { return nil }
+
+func JSON(w http.ResponseWriter, code int, v interface{}) {}
+
+func Error(w http.ResponseWriter, code int) {}
+`,
+}
+
+func TestErrorResponses(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	root := writeTestProject(t, responsesProject)
+	defer os.RemoveAll(root)
+	c := criteria.Criteria{}
+	if err := criteria.NewCriteriaDecoder(logger).ParseCriteriaFromYAML(strings.NewReader(responsesCriteria), &c); err != nil {
+		t.Fatal(err)
+	}
+	sg, err := NewSwaggerGeneratorWithBlacklist(root, root, nil, logger, []*regexp.Regexp{regexp.MustCompile(`_test\.go$`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := openapi2.Swagger{}
+	if err := sg.GenerateSwaggerDoc(c, &doc); err != nil {
+		t.Fatal(err)
+	}
+	operation := doc.Paths["/users"].Post
+	if !assert.NotNil(t, operation) {
+		return
+	}
+	t.Run("should reference the error model from the definitions without a definition prefix", func(t *testing.T) {
+		assert.Contains(t, doc.Definitions, "Failure")
+		if assert.NotNil(t, operation.Responses["400"]) && assert.NotNil(t, operation.Responses["400"].Schema) {
+			assert.Equal(t, "#/definitions/Failure", operation.Responses["400"].Schema.Ref)
+			assert.Nil(t, operation.Responses["400"].Schema.Value)
+		}
+	})
+	t.Run("should reference the global responses", func(t *testing.T) {
+		if assert.NotNil(t, operation.Responses["500"]) {
+			assert.Equal(t, "#/responses/InternalServerError", operation.Responses["500"].Ref)
+		}
+		if assert.NotNil(t, operation.Responses["404"]) {
+			assert.Equal(t, "#/responses/NotFound", operation.Responses["404"].Ref)
+		}
+		assert.NotContains(t, operation.Responses, "401", "the operation is not secured")
+		internal := doc.Responses["InternalServerError"]
+		if assert.NotNil(t, internal) && assert.NotNil(t, internal.Schema) {
+			assert.Equal(t, "#/definitions/Failure", internal.Schema.Ref, "a global response without a model uses the error model")
+		}
+	})
+	t.Run("should add an example to the global responses", func(t *testing.T) {
+		internal := doc.Responses["InternalServerError"]
+		if assert.NotNil(t, internal) {
+			assert.Equal(t, map[string]interface{}{"message": "user not found"}, internal.Examples[criteria.MIMEApplicationJSON])
+		}
+	})
+	t.Run("should report a global response model that is not found", func(t *testing.T) {
+		notFound := doc.Responses["NotFound"]
+		if assert.NotNil(t, notFound) {
+			assert.Nil(t, notFound.Schema)
+		}
+		messages := make([]string, 0)
+		for _, d := range sg.Diagnostics.Diagnostics() {
+			if d.Criteria == "config globalResponses.404.model" {
+				messages = append(messages, d.Message)
+			}
+		}
+		assert.Equal(t, []string{"model handler.Nope not found, the responses that use it have no schema"}, messages)
+	})
+}
+
+func TestAddGlobalResponseRefs(t *testing.T) {
+	projectCriterias := criteria.Criteria{
+		GlobalResponses: map[string]criteria.GlobalResponse{
+			"500": {},
+			"401": {SecuredOnly: true},
+		},
+	}
+	globalRefs := map[string]string{
+		"500": "#/responses/InternalServerError",
+		"401": "#/responses/Unauthorized",
+	}
+	type expected struct {
+		refs map[string]string
+	}
+	tests := []struct {
+		name      string
+		operation *openapi2.Operation
+		expected  expected
+	}{
+		{
+			name:      "should not add the secured only responses to an unsecured operation",
+			operation: &openapi2.Operation{},
+			expected: expected{
+				refs: map[string]string{"500": "#/responses/InternalServerError"},
+			},
+		},
+		{
+			name: "should add the secured only responses to a secured operation",
+			operation: &openapi2.Operation{
+				Security: &openapi2.SecurityRequirements{{"jwt": []string{}}},
+			},
+			expected: expected{
+				refs: map[string]string{
+					"500": "#/responses/InternalServerError",
+					"401": "#/responses/Unauthorized",
+				},
+			},
+		},
+		{
+			name: "should keep the responses declared by the operation",
+			operation: &openapi2.Operation{
+				Responses: map[string]*openapi2.Response{
+					"500": {Description: "Database error"},
+				},
+			},
+			expected: expected{
+				refs: map[string]string{"500": ""},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addGlobalResponseRefs(test.operation, globalRefs, projectCriterias)
+			refs := make(map[string]string, len(test.operation.Responses))
+			for code, resp := range test.operation.Responses {
+				refs[code] = resp.Ref
+			}
+			assert.Equal(t, test.expected.refs, refs)
+		})
+	}
+}
+
+func TestGlobalResponseName(t *testing.T) {
+	type expected struct {
+		name        string
+		description string
+	}
+	tests := []struct {
+		name     string
+		code     string
+		expected expected
+	}{
+		{
+			name:     "should name a response after its status text",
+			code:     "500",
+			expected: expected{name: "InternalServerError", description: "Internal Server Error"},
+		},
+		{
+			name:     "should remove the dashes and apostrophes of the status text",
+			code:     "418",
+			expected: expected{name: "Imateapot", description: "I'm a teapot"},
+		},
+		{
+			name:     "should name the default response",
+			code:     criteria.DefaultResponseCode,
+			expected: expected{name: "DefaultError", description: defaultResponseDescription},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected.name, globalResponseName(test.code))
+			assert.Equal(t, test.expected.description, statusDescription(test.code))
+		})
+	}
+}
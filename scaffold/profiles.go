@@ -0,0 +1,94 @@
+package scaffold
+
+const (
+	colonPathVarExtractor = ":([a-zA-Z0-9]+)"
+)
+
+// profile describes how a framework registers routes, binds requests and writes responses
+type profile struct {
+	// pkg is the name of the package of the router
+	pkg string
+	// routeFuncs register a route as in e.GET("/users", handler)
+	routeFuncs []string
+	// httpMethod is the method of the routes registered with a function that does not name it
+	httpMethod string
+	// colonPathVars is true when the path variables are declared as /users/:id
+	colonPathVars   bool
+	middlewareIndex int
+	groupFunc       string
+	// groupMiddlewareIndex is the index of the first middleware of a group, 0 means none
+	groupMiddlewareIndex int
+	useFunc              string
+	// bindFuncs are methods of the request context that bind a request as in c.Bind(&request)
+	bindFuncs      []string
+	bindModelIndex int
+	// jsonFuncs are methods of the request context that write a response as in c.JSON(http.StatusOK, response)
+	jsonFuncs      []string
+	jsonModelIndex int
+	jsonCodeIndex  int
+	// contextVar is the usual name of the request context variable
+	contextVar string
+}
+
+var (
+	profiles = map[string]profile{
+		Echo: {
+			pkg:                  "echo",
+			routeFuncs:           []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			colonPathVars:        true,
+			middlewareIndex:      2,
+			groupFunc:            "Group",
+			groupMiddlewareIndex: 1,
+			useFunc:              "Use",
+			bindFuncs:            []string{"Bind"},
+			jsonFuncs:            []string{"JSON", "JSONPretty"},
+			jsonModelIndex:       1,
+			contextVar:           "c",
+		},
+		Gin: {
+			pkg:                  "gin",
+			routeFuncs:           []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			colonPathVars:        true,
+			groupFunc:            "Group",
+			groupMiddlewareIndex: 1,
+			useFunc:              "Use",
+			bindFuncs:            []string{"ShouldBindJSON", "BindJSON", "ShouldBind", "Bind"},
+			jsonFuncs:            []string{"JSON", "IndentedJSON"},
+			jsonModelIndex:       1,
+			contextVar:           "c",
+		},
+		Chi: {
+			pkg:        "chi",
+			routeFuncs: []string{"Get", "Post", "Put", "Patch", "Delete"},
+			groupFunc:  "Route",
+			useFunc:    "Use",
+		},
+		GorillaMux: {
+			pkg:        "mux",
+			routeFuncs: []string{"HandleFunc"},
+			httpMethod: "GET",
+		},
+		NetHTTP: {
+			pkg:        "http",
+			routeFuncs: []string{"HandleFunc"},
+			httpMethod: "GET",
+		},
+	}
+)
+
+// profileOf returns the profile of a framework, it defaults to net/http
+func profileOf(framework string) profile {
+	if p, ok := profiles[framework]; ok {
+		return p
+	}
+	return profiles[NetHTTP]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package scaffold
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/javiercbk/swago/criteria"
+	v2pkg "github.com/javiercbk/swago/v2/pkg"
+)
+
+const (
+	// Echo is the github.com/labstack/echo framework
+	Echo = "echo"
+	// Gin is the github.com/gin-gonic/gin framework
+	Gin = "gin"
+	// Chi is the github.com/go-chi/chi router
+	Chi = "chi"
+	// GorillaMux is the github.com/gorilla/mux router
+	GorillaMux = "gorilla/mux"
+	// NetHTTP is the standard library net/http package
+	NetHTTP = "net/http"
+)
+
+type criteriaFile struct {
+	Title       string
+	Framework   string
+	Notes       []string
+	StructRoute *structRouteEntry
+	FuncRoutes  []funcRouteEntry
+	Group       *groupEntry
+	Request     []callEntry
+	Response    []callEntry
+}
+
+type structRouteEntry struct {
+	criteria.StructRoute
+	Comment string
+}
+
+type funcRouteEntry struct {
+	Comment               string
+	Pkg                   string
+	FuncName              string
+	HTTPMethod            string
+	NamedPathVarExtractor string
+	MiddlewareIndex       int
+}
+
+type groupEntry struct {
+	Comment         string
+	Pkg             string
+	FuncName        string
+	MiddlewareIndex int
+	UseFuncName     string
+}
+
+type callEntry struct {
+	Comment    string
+	Pkg        string
+	FuncName   string
+	ModelIndex int
+	CodeIndex  int
+}
+
+var (
+	// frameworkModules are the module paths of every framework, in detection order
+	frameworkModules = []struct {
+		framework string
+		module    string
+	}{
+		{framework: Echo, module: "github.com/labstack/echo"},
+		{framework: Gin, module: "github.com/gin-gonic/gin"},
+		{framework: Chi, module: "github.com/go-chi/chi"},
+		{framework: GorillaMux, module: "github.com/gorilla/mux"},
+	}
+)
+
+// DetectFramework returns the web framework required by a module, it defaults to net/http
+func DetectFramework(mod v2pkg.Module) string {
+	for _, fm := range frameworkModules {
+		for required := range mod.Require {
+			if required == fm.module || strings.HasPrefix(required, fm.module+"/") {
+				return fm.framework
+			}
+		}
+	}
+	return NetHTTP
+}
+
+// WriteCriteria writes a starter criteria file for the usage found in a project, the
+// usual criteria of the framework is written for what was not found
+func WriteCriteria(w io.Writer, usage Usage, title string) error {
+	framework := usage.Framework
+	if _, ok := profiles[framework]; !ok {
+		framework = NetHTTP
+	}
+	p := profileOf(framework)
+	usual := fmt.Sprintf("nothing was found, this is how %s is usually used", framework)
+	cf := criteriaFile{
+		Title:     strconv.Quote(title),
+		Framework: framework,
+		Notes:     append([]string{}, usage.Notes...),
+	}
+	if sr := usage.StructRoute; sr != nil {
+		cf.StructRoute = &structRouteEntry{
+			Comment:     foundComment(sr.Count, "literal", sr.Position),
+			StructRoute: sr.StructRoute,
+		}
+	}
+	defaultRoutes := !usage.foundRoutes()
+	routes := usage.Routes
+	if defaultRoutes {
+		for _, name := range p.routeFuncs {
+			routes = append(routes, Call{Pkg: p.pkg, FuncName: name})
+		}
+	}
+	httpMethod := p.httpMethod
+	if len(httpMethod) > 0 && len(usage.RouteMethods) > 0 {
+		httpMethod = usage.RouteMethods[0]
+		if len(usage.RouteMethods) > 1 {
+			cf.Notes = append(cf.Notes, fmt.Sprintf("routes are registered with the methods %s but only one can be set, every route is documented as %s", strings.Join(usage.RouteMethods, ", "), httpMethod))
+		}
+	}
+	for _, r := range routes {
+		e := funcRouteEntry{
+			Comment:    callComment(r, usual),
+			Pkg:        r.Pkg,
+			FuncName:   r.FuncName,
+			HTTPMethod: httpMethod,
+		}
+		if p.colonPathVars || usage.ColonPathVars {
+			e.NamedPathVarExtractor = colonPathVarExtractor
+		}
+		if p.middlewareIndex > 0 && (usage.RouteMiddlewares || defaultRoutes) {
+			e.MiddlewareIndex = p.middlewareIndex
+		}
+		cf.FuncRoutes = append(cf.FuncRoutes, e)
+	}
+	if len(p.groupFunc) > 0 && (usage.Group != nil || usage.Use || defaultRoutes) {
+		g := &groupEntry{
+			Comment:  usual,
+			Pkg:      p.pkg,
+			FuncName: p.groupFunc,
+		}
+		if usage.Group != nil {
+			g.Comment = callComment(*usage.Group, usual)
+		}
+		if p.groupMiddlewareIndex > 0 && (usage.GroupMiddlewares || usage.Group == nil) {
+			g.MiddlewareIndex = p.groupMiddlewareIndex
+		}
+		if usage.Use || usage.Group == nil {
+			g.UseFuncName = p.useFunc
+		}
+		cf.Group = g
+	}
+	for _, c := range usage.Request {
+		cf.Request = append(cf.Request, callEntry{Comment: callComment(c, usual), Pkg: c.Pkg, FuncName: c.FuncName, ModelIndex: c.ModelIndex})
+	}
+	if len(cf.Request) == 0 {
+		if len(p.bindFuncs) > 0 {
+			cf.Request = append(cf.Request, callEntry{Comment: usual, Pkg: p.contextVar, FuncName: p.bindFuncs[0], ModelIndex: p.bindModelIndex})
+		} else {
+			cf.Request = append(cf.Request, callEntry{
+				Comment:    "nothing was found, replace api.DecodeJSON(r, &request) with the function that decodes the requests",
+				Pkg:        "api",
+				FuncName:   "DecodeJSON",
+				ModelIndex: 1,
+			})
+		}
+	}
+	for _, c := range usage.Response {
+		cf.Response = append(cf.Response, callEntry{Comment: callComment(c, usual), Pkg: c.Pkg, FuncName: c.FuncName, ModelIndex: c.ModelIndex, CodeIndex: c.CodeIndex})
+	}
+	if len(cf.Response) == 0 {
+		if len(p.jsonFuncs) > 0 {
+			cf.Response = append(cf.Response, callEntry{Comment: usual, Pkg: p.contextVar, FuncName: p.jsonFuncs[0], ModelIndex: p.jsonModelIndex, CodeIndex: p.jsonCodeIndex})
+		} else {
+			cf.Response = append(cf.Response, callEntry{
+				Comment:    "nothing was found, replace api.WriteJSON(w, http.StatusOK, response) with the function that writes the responses",
+				Pkg:        "api",
+				FuncName:   "WriteJSON",
+				ModelIndex: 2,
+				CodeIndex:  1,
+			})
+		}
+	}
+	return criteriaTemplate.Execute(w, cf)
+}
+
+// callComment describes where a call was found, calls without uses are the usual criteria
+func callComment(c Call, usual string) string {
+	if c.Count == 0 {
+		return usual
+	}
+	return foundComment(c.Count, "call", c.Position)
+}
+
+func foundComment(count int, what, position string) string {
+	if count != 1 {
+		what += "s"
+	}
+	return fmt.Sprintf("found %d %s as in %s", count, what, position)
+}
@@ -0,0 +1,246 @@
+package scaffold
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago"
+	"github.com/javiercbk/swago/criteria"
+	v2pkg "github.com/javiercbk/swago/v2/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectFramework(t *testing.T) {
+	type params struct {
+		require map[string]string
+	}
+	type expected struct {
+		framework string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "echo major version",
+			params:   params{require: map[string]string{"github.com/labstack/echo/v4": "v4.9.0"}},
+			expected: expected{framework: Echo},
+		},
+		{
+			name:     "gin",
+			params:   params{require: map[string]string{"github.com/gin-gonic/gin": "v1.8.1"}},
+			expected: expected{framework: Gin},
+		},
+		{
+			name:     "chi",
+			params:   params{require: map[string]string{"github.com/go-chi/chi/v5": "v5.0.7"}},
+			expected: expected{framework: Chi},
+		},
+		{
+			name:     "gorilla mux",
+			params:   params{require: map[string]string{"github.com/gorilla/mux": "v1.8.0"}},
+			expected: expected{framework: GorillaMux},
+		},
+		{
+			name:     "module with a similar prefix",
+			params:   params{require: map[string]string{"github.com/gorilla/muxer": "v1.0.0"}},
+			expected: expected{framework: NetHTTP},
+		},
+		{
+			name:     "no framework",
+			expected: expected{framework: NetHTTP},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mod := v2pkg.Module{Name: "example.com/api", Require: test.params.require}
+			assert.Equal(t, test.expected.framework, DetectFramework(mod))
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	type params struct {
+		root      string
+		framework string
+	}
+	type expected struct {
+		routes      []string
+		structRoute *criteria.StructRoute
+		request     []Call
+		response    []Call
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:   "echo routes with bind and a response helper",
+			params: params{root: "../testdata/mod-project", framework: Echo},
+			expected: expected{
+				routes:  []string{"GET", "POST", "PUT"},
+				request: []Call{{Pkg: "c", FuncName: "Bind", CodeIndex: noParam}},
+				response: []Call{
+					{Pkg: "response", FuncName: "NewSuccessResponseWithCode", ModelIndex: 2, CodeIndex: 1},
+					{Pkg: "c", FuncName: "JSON", ModelIndex: 1},
+				},
+			},
+		},
+		{
+			name:   "struct routes with request and response helpers",
+			params: params{root: "../testdata/struct-project", framework: NetHTTP},
+			expected: expected{
+				structRoute: &criteria.StructRoute{
+					Name:            "Route",
+					Pkg:             "routes",
+					PathField:       "Pattern",
+					HandlerField:    "HandlerFunc",
+					HTTPMethodField: "Method",
+				},
+				request:  []Call{{Pkg: "api", FuncName: "DecodeJsonRequest", ModelIndex: 1, CodeIndex: noParam}},
+				response: []Call{{Pkg: "api", FuncName: "SendJSONResponse", ModelIndex: 2, CodeIndex: 3}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			usage, err := Scan(test.params.root, test.params.framework)
+			assert.Nil(t, err)
+			routes := make([]string, 0)
+			for _, r := range usage.Routes {
+				routes = append(routes, r.FuncName)
+			}
+			assert.ElementsMatch(t, test.expected.routes, routes)
+			if test.expected.structRoute == nil {
+				assert.Nil(t, usage.StructRoute)
+			} else if assert.NotNil(t, usage.StructRoute) {
+				assert.Equal(t, *test.expected.structRoute, usage.StructRoute.StructRoute)
+			}
+			assert.Equal(t, test.expected.request, withoutPositions(usage.Request))
+			assert.Equal(t, test.expected.response, withoutPositions(usage.Response))
+		})
+	}
+}
+
+func withoutPositions(calls []Call) []Call {
+	stripped := make([]Call, 0, len(calls))
+	for _, c := range calls {
+		c.Count = 0
+		c.Position = ""
+		stripped = append(stripped, c)
+	}
+	return stripped
+}
+
+func TestWriteCriteria(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	usages := make(map[string]Usage)
+	for framework := range profiles {
+		usages["usual "+framework] = Usage{Framework: framework}
+	}
+	for _, root := range []string{"../testdata/mod-project", "../testdata/struct-project"} {
+		mod, err := v2pkg.ReadMod(filepath.Join(root, "go.mod"))
+		assert.Nil(t, err)
+		usage, err := Scan(root, DetectFramework(mod))
+		assert.Nil(t, err)
+		usages[root] = usage
+	}
+	for name, usage := range usages {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := WriteCriteria(buf, usage, `my "api"`)
+			assert.Nil(t, err)
+			c := criteria.Criteria{}
+			err = criteria.NewCriteriaDecoder(logger).ParseCriteriaFromYAML(buf, &c)
+			assert.Nil(t, err)
+			assert.Equal(t, `my "api"`, c.Info.Title)
+			assert.Empty(t, c.Lint())
+		})
+	}
+}
+
+func TestScaffoldedCriteria(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	// the fixtures are in a testdata directory, which the default blacklist ignores
+	blacklist := []*regexp.Regexp{regexp.MustCompile(`_test\.go$`)}
+	type params struct {
+		root string
+	}
+	type expected struct {
+		operations []string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:   "echo routes with their request models",
+			params: params{root: "../testdata/mod-project"},
+			expected: expected{operations: []string{
+				"GET /api/v1/auth/current",
+				"GET /api/v1/users request QueryInput",
+				"GET /api/v1/users/{userID}",
+				"POST /api/v1/auth request Credentials",
+				"POST /api/v1/users request NewUserInput",
+				"PUT /api/v1/users/{userID}",
+			}},
+		},
+		{
+			name:   "struct routes with their request and response models",
+			params: params{root: "../testdata/struct-project"},
+			expected: expected{operations: []string{
+				"GET /handler2/nice request V1Request2 response 200",
+				"POST /handler/1 request V1Request1 response 200",
+				"PUT /handler/{name}/3 request V1Request3 response 200",
+			}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := test.params.root
+			mod, err := v2pkg.ReadMod(filepath.Join(root, "go.mod"))
+			assert.Nil(t, err)
+			usage, err := Scan(root, DetectFramework(mod))
+			assert.Nil(t, err)
+			buf := &bytes.Buffer{}
+			assert.Nil(t, WriteCriteria(buf, usage, "api"))
+			c := criteria.Criteria{}
+			assert.Nil(t, criteria.NewCriteriaDecoder(logger).ParseCriteriaFromYAML(buf, &c))
+			sg, err := swago.NewSwaggerGeneratorWithBlacklist(root, root, c.VendorFolders, logger, blacklist)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc := openapi2.Swagger{}
+			assert.Nil(t, sg.GenerateSwaggerDoc(c, &doc))
+			operations := make([]string, 0)
+			for path, pathItem := range doc.Paths {
+				for method, operation := range pathItem.Operations() {
+					op := method + " " + path
+					for _, p := range operation.Parameters {
+						if p.In == "body" && p.Schema != nil && p.Schema.Value != nil && len(p.Schema.Value.Properties) > 0 {
+							op += " request " + p.Name
+						}
+					}
+					for code, r := range operation.Responses {
+						if r.Schema != nil && r.Schema.Value != nil && len(r.Schema.Value.Properties) > 0 {
+							op += " response " + code
+						}
+					}
+					operations = append(operations, op)
+				}
+			}
+			sort.Strings(operations)
+			assert.Equal(t, test.expected.operations, operations)
+			assert.Empty(t, sg.Diagnostics.Diagnostics())
+		})
+	}
+}
@@ -0,0 +1,694 @@
+package scaffold
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/pkg"
+)
+
+const (
+	// maxHelperDepth limits how many helpers wrapping other helpers are followed
+	maxHelperDepth = 4
+	noParam        = -1
+)
+
+var (
+	handlerFieldTypes = []string{
+		"http.HandlerFunc",
+		"http.Handler",
+		"func(http.ResponseWriter, *http.Request)",
+		"echo.HandlerFunc",
+		"gin.HandlerFunc",
+	}
+)
+
+// Call is a call found in a project
+type Call struct {
+	Pkg        string
+	FuncName   string
+	ModelIndex int
+	CodeIndex  int
+	// Count is the number of calls found
+	Count int
+	// Position is the file and line of the first call found
+	Position string
+}
+
+// StructRoute is a struct whose literals declare the routes of a project
+type StructRoute struct {
+	criteria.StructRoute
+	// Count is the number of literals found
+	Count    int
+	Position string
+}
+
+// Usage is what Scan found about how a project uses its web framework
+type Usage struct {
+	Framework string
+	// Routes are the calls that register routes, as in e.GET("/users", handler)
+	Routes []Call
+	// RouteMethods are the http methods of routes registered without naming it, as in
+	// r.HandleFunc("/users", handler).Methods(http.MethodPost)
+	RouteMethods     []string
+	ColonPathVars    bool
+	RouteMiddlewares bool
+	Group            *Call
+	GroupMiddlewares bool
+	Use              bool
+	StructRoute      *StructRoute
+	Request          []Call
+	Response         []Call
+	// Notes are the usages found that a criteria can not describe
+	Notes []string
+}
+
+func (u Usage) foundRoutes() bool {
+	return len(u.Routes) > 0 || u.StructRoute != nil
+}
+
+type structCandidate struct {
+	route       StructRoute
+	pathVotes   map[string]int
+	methodVotes map[string]int
+}
+
+type scanner struct {
+	root    string
+	profile profile
+	usage   Usage
+	files   []*pkg.File
+	funcs   []*pkg.Function
+	// requestHelpers and responseHelpers are functions that wrap a bind or a json call
+	requestHelpers  map[string]*Call
+	responseHelpers map[string]*Call
+	// codeless are the response helpers without a status code parameter
+	codeless   map[string]*Call
+	routeCalls map[string]*Call
+	direct     map[string]*Call
+	structs    map[string]*structCandidate
+	// sliceTypes maps a slice type as in routes.Routes to the type of its elements
+	sliceTypes map[string]string
+	methods    map[string]bool
+	notes      map[string]bool
+}
+
+// Scan reads the go files of a project looking for the calls that register routes,
+// bind requests and write responses with a framework. Functions that wrap a bind or
+// a json call with the model as a parameter are reported as helpers. The project is
+// analyzed as it is when a document is generated, so the calls found are named as the
+// criteria matches them
+func Scan(root string, framework string) (Usage, error) {
+	s := &scanner{
+		root:            root,
+		profile:         profileOf(framework),
+		usage:           Usage{Framework: framework},
+		requestHelpers:  make(map[string]*Call),
+		responseHelpers: make(map[string]*Call),
+		codeless:        make(map[string]*Call),
+		routeCalls:      make(map[string]*Call),
+		direct:          make(map[string]*Call),
+		structs:         make(map[string]*structCandidate),
+		sliceTypes:      make(map[string]string),
+		methods:         make(map[string]bool),
+		notes:           make(map[string]bool),
+	}
+	pkgs, err := pkg.AnalizeProjectWithBlacklist(root, log.New(ioutil.Discard, "", 0), scanBlacklist(root))
+	if err != nil {
+		return s.usage, err
+	}
+	for _, p := range pkgs {
+		for i := range p.Files {
+			s.addFile(&p.Files[i])
+		}
+	}
+	for _, f := range s.files {
+		s.scanFile(f)
+	}
+	for _, fun := range s.funcs {
+		s.scanSinks(fun)
+	}
+	s.findWrappingHelpers()
+	s.countHelperCalls()
+	s.collect()
+	return s.usage, nil
+}
+
+// scanBlacklist ignores the test files and the testdata and vendor directories of a project,
+// the directories are matched below the root so a project can be scanned from a testdata directory
+func scanBlacklist(root string) []*regexp.Regexp {
+	separator := regexp.QuoteMeta(string(os.PathSeparator))
+	prefix := ""
+	if cleanRoot := filepath.Clean(root); cleanRoot != "." {
+		prefix = regexp.QuoteMeta(cleanRoot) + separator
+	}
+	return []*regexp.Regexp{
+		regexp.MustCompile(`_test\.go$`),
+		regexp.MustCompile(`^` + prefix + `(.+` + separator + `)?(testdata|vendor)` + separator),
+	}
+}
+
+// addFile registers the functions and the candidate route structs of a file
+func (s *scanner) addFile(f *pkg.File) {
+	s.files = append(s.files, f)
+	for i := range f.Functions {
+		if f.Functions[i].Body() != nil {
+			s.funcs = append(s.funcs, &f.Functions[i])
+		}
+	}
+	for _, d := range f.File.Decls {
+		decl, ok := d.(*ast.GenDecl)
+		if !ok || decl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			s.addType(f, spec.(*ast.TypeSpec))
+		}
+	}
+}
+
+func (s *scanner) addType(f *pkg.File, ts *ast.TypeSpec) {
+	name := f.TypeName(ts.Name)
+	switch t := ts.Type.(type) {
+	case *ast.ArrayType:
+		s.sliceTypes[name] = elemTypeName(f, t.Elt)
+	case *ast.StructType:
+		handlerField := ""
+		for _, field := range t.Fields.List {
+			if contains(handlerFieldTypes, types.ExprString(field.Type)) && len(field.Names) > 0 {
+				handlerField = field.Names[0].Name
+				break
+			}
+		}
+		if len(handlerField) == 0 {
+			return
+		}
+		s.structs[name] = &structCandidate{
+			route: StructRoute{
+				StructRoute: criteria.StructRoute{
+					Name:         ts.Name.Name,
+					Pkg:          f.Pkg.Name,
+					HandlerField: handlerField,
+				},
+			},
+			pathVotes:   make(map[string]int),
+			methodVotes: make(map[string]int),
+		}
+	}
+}
+
+// scanFile finds the route registrations and the route struct literals of a file
+func (s *scanner) scanFile(f *pkg.File) {
+	ast.Inspect(f.File, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.CallExpr:
+			s.routeCall(f, x)
+		case *ast.CompositeLit:
+			s.structLiteral(f, x, f.TypeName(x.Type))
+		}
+		return true
+	})
+}
+
+func (s *scanner) routeCall(f *pkg.File, call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	name := sel.Sel.Name
+	p := s.profile
+	switch {
+	case contains(p.routeFuncs, name) && len(call.Args) >= 2 && isPath(call.Args[0]):
+		s.count(s.routeCalls, p.pkg+"."+name, Call{Pkg: p.pkg, FuncName: name}, f.FSet.Position(call.Pos()))
+		if path, _ := stringLit(call.Args[0]); strings.Contains(path, "/:") {
+			s.usage.ColonPathVars = true
+		}
+		if len(call.Args) > 2 {
+			s.usage.RouteMiddlewares = true
+		}
+	case name == "Methods" && len(p.httpMethod) > 0:
+		inner, ok := sel.X.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		if innerSel, ok := inner.Fun.(*ast.SelectorExpr); !ok || !contains(p.routeFuncs, innerSel.Sel.Name) {
+			return
+		}
+		for _, a := range call.Args {
+			if m := httpMethod(a); len(m) > 0 {
+				s.methods[m] = true
+			}
+		}
+	case len(p.groupFunc) > 0 && name == p.groupFunc && len(call.Args) >= 1 && isPath(call.Args[0]):
+		if s.usage.Group == nil {
+			s.usage.Group = &Call{Pkg: p.pkg, FuncName: name, Position: s.position(f.FSet.Position(call.Pos()))}
+		}
+		s.usage.Group.Count++
+		if p.groupMiddlewareIndex > 0 && len(call.Args) > p.groupMiddlewareIndex {
+			s.usage.GroupMiddlewares = true
+		}
+	case len(p.useFunc) > 0 && name == p.useFunc && isVar(f, sel.X):
+		s.usage.Use = true
+	}
+}
+
+// structLiteral votes for the path and method fields of the literals of a route struct
+func (s *scanner) structLiteral(f *pkg.File, lit *ast.CompositeLit, typeName string) {
+	if elem, ok := s.sliceTypes[typeName]; ok {
+		typeName = elem
+	} else if at, ok := lit.Type.(*ast.ArrayType); ok {
+		typeName = elemTypeName(f, at.Elt)
+	} else if c, ok := s.structs[typeName]; ok {
+		c.route.Count++
+		if len(c.route.Position) == 0 {
+			c.route.Position = s.position(f.FSet.Position(lit.Pos()))
+		}
+		for _, el := range lit.Elts {
+			kv, ok := el.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if value, ok := stringLit(kv.Value); ok && strings.HasPrefix(value, "/") {
+				c.pathVotes[key.Name]++
+			} else if len(httpMethod(kv.Value)) > 0 {
+				c.methodVotes[key.Name]++
+			}
+		}
+		return
+	} else {
+		return
+	}
+	// elements of a slice literal may omit their type
+	for _, el := range lit.Elts {
+		if elLit, ok := el.(*ast.CompositeLit); ok && elLit.Type == nil {
+			s.structLiteral(f, elLit, typeName)
+		}
+	}
+}
+
+// scanSinks finds the bind and json calls of a function, the function is a helper when the
+// model of the call is one of its parameters
+func (s *scanner) scanSinks(fun *pkg.Function) {
+	p := s.profile
+	f := fun.File
+	ast.Inspect(fun.Body(), func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		callName := f.CallName(call)
+		switch {
+		case contains(p.bindFuncs, sel.Sel.Name) && isVar(f, sel.X):
+			s.sink(fun, call, true, p.bindModelIndex, noParam, callName)
+		case contains(p.jsonFuncs, sel.Sel.Name) && isVar(f, sel.X):
+			s.sink(fun, call, false, p.jsonModelIndex, p.jsonCodeIndex, callName)
+		case isJSONStream(f, sel, "NewDecoder", "Decode"):
+			s.sink(fun, call, true, 0, noParam, "")
+		case callName == "json.Unmarshal":
+			s.sink(fun, call, true, 1, noParam, "")
+		case isJSONStream(f, sel, "NewEncoder", "Encode"):
+			s.sink(fun, call, false, 0, noParam, "")
+		case callName == "json.Marshal" || callName == "json.MarshalIndent":
+			s.sink(fun, call, false, 0, noParam, "")
+		}
+		return true
+	})
+}
+
+// sink registers a call that binds a request or writes a response, callName is the name
+// of a framework call as the criteria matches it or empty for a call to encoding/json
+func (s *scanner) sink(fun *pkg.Function, call *ast.CallExpr, request bool, modelIndex, codeIndex int, callName string) {
+	if len(call.Args) <= modelIndex {
+		return
+	}
+	if s.addHelper(fun, call, request, modelIndex, codeIndex) {
+		return
+	}
+	if len(callName) > 0 {
+		callPkg, funcName := pkg.TypeParts(callName)
+		c := Call{Pkg: callPkg, FuncName: funcName, ModelIndex: modelIndex, CodeIndex: codeIndex}
+		s.count(s.direct, kindKey(request, callName), c, fun.PositionOf(call.Pos()))
+		return
+	}
+	if fun.IsHandler() {
+		s.note(fmt.Sprintf("handlers use encoding/json directly as in %s, only calls to functions can be documented so move it to a helper", s.position(fun.PositionOf(call.Pos()))))
+	}
+}
+
+// addHelper registers a function as a helper when the model of a call is one of its parameters
+func (s *scanner) addHelper(fun *pkg.Function, call *ast.CallExpr, request bool, modelIndex, codeIndex int) bool {
+	if len(fun.MemberOf) > 0 {
+		return false
+	}
+	modelParam := modelParam(fun, call.Args[modelIndex])
+	if modelParam == noParam {
+		return false
+	}
+	key := fun.File.Pkg.Name + "." + fun.Name
+	if s.requestHelpers[key] != nil || s.responseHelpers[key] != nil || s.codeless[key] != nil {
+		return true
+	}
+	helper := &Call{Pkg: fun.File.Pkg.Name, FuncName: fun.Name, ModelIndex: modelParam, CodeIndex: noParam}
+	switch {
+	case request:
+		s.requestHelpers[key] = helper
+	default:
+		if codeIndex != noParam && len(call.Args) > codeIndex {
+			helper.CodeIndex = intParam(fun, call.Args[codeIndex])
+		}
+		if helper.CodeIndex == noParam {
+			helper.CodeIndex = writeHeaderParam(fun)
+		}
+		if helper.CodeIndex == noParam {
+			s.codeless[key] = helper
+		} else {
+			s.responseHelpers[key] = helper
+		}
+	}
+	return true
+}
+
+// findWrappingHelpers finds the functions that pass their model parameter to a helper
+func (s *scanner) findWrappingHelpers() {
+	for depth := 0; depth < maxHelperDepth; depth++ {
+		found := len(s.requestHelpers) + len(s.responseHelpers) + len(s.codeless)
+		for _, fun := range s.funcs {
+			ast.Inspect(fun.Body(), func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				key := fun.File.CallName(call)
+				if h, ok := s.requestHelpers[key]; ok && len(call.Args) > h.ModelIndex {
+					s.addHelper(fun, call, true, h.ModelIndex, noParam)
+				} else if h, ok := s.responseHelpers[key]; ok && len(call.Args) > h.ModelIndex {
+					s.addHelper(fun, call, false, h.ModelIndex, h.CodeIndex)
+				} else if h, ok := s.codeless[key]; ok && len(call.Args) > h.ModelIndex {
+					s.addHelper(fun, call, false, h.ModelIndex, noParam)
+				}
+				return true
+			})
+		}
+		if found == len(s.requestHelpers)+len(s.responseHelpers)+len(s.codeless) {
+			return
+		}
+	}
+}
+
+func (s *scanner) countHelperCalls() {
+	for _, fun := range s.funcs {
+		ast.Inspect(fun.Body(), func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			key := fun.File.CallName(call)
+			for _, helpers := range []map[string]*Call{s.requestHelpers, s.responseHelpers, s.codeless} {
+				if h, ok := helpers[key]; ok {
+					h.Count++
+					if len(h.Position) == 0 {
+						h.Position = s.position(fun.PositionOf(call.Pos()))
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// collect fills the usage with the calls found sorted by use
+func (s *scanner) collect() {
+	s.usage.Routes = sortedCalls(s.routeCalls)
+	for m := range s.methods {
+		s.usage.RouteMethods = append(s.usage.RouteMethods, m)
+	}
+	sort.Strings(s.usage.RouteMethods)
+	var best *structCandidate
+	for _, c := range s.structs {
+		if len(c.pathVotes) == 0 {
+			continue
+		}
+		if best == nil || c.route.Count > best.route.Count {
+			best = c
+		}
+	}
+	if best != nil {
+		best.route.PathField = mostVoted(best.pathVotes)
+		best.route.HTTPMethodField = mostVoted(best.methodVotes)
+		s.usage.StructRoute = &best.route
+	}
+	requests := make(map[string]*Call)
+	responses := make(map[string]*Call)
+	for key, c := range s.direct {
+		if strings.HasPrefix(key, requestKind) {
+			requests[key] = c
+		} else {
+			responses[key] = c
+		}
+	}
+	for key, h := range s.requestHelpers {
+		if h.Count > 0 {
+			requests[key] = h
+		}
+	}
+	for key, h := range s.responseHelpers {
+		if h.Count > 0 {
+			responses[key] = h
+		}
+	}
+	s.usage.Request = sortedCalls(requests)
+	s.usage.Response = sortedCalls(responses)
+	for _, h := range sortedCalls(s.codeless) {
+		if h.Count > 0 {
+			s.note(fmt.Sprintf("%s.%s writes a response without a status code parameter, swago can not document its %d calls", h.Pkg, h.FuncName, h.Count))
+		}
+	}
+	if s.usage.RouteMiddlewares && s.profile.middlewareIndex == 0 {
+		s.note("routes pass middlewares before the handler, only the handler at handlerIndex is documented")
+	}
+	for n := range s.notes {
+		s.usage.Notes = append(s.usage.Notes, n)
+	}
+	sort.Strings(s.usage.Notes)
+}
+
+func (s *scanner) count(calls map[string]*Call, key string, c Call, pos token.Position) {
+	found, ok := calls[key]
+	if !ok {
+		c.Position = s.position(pos)
+		found = &c
+		calls[key] = found
+	}
+	found.Count++
+}
+
+func (s *scanner) note(note string) {
+	s.notes[note] = true
+}
+
+func (s *scanner) position(pos token.Position) string {
+	rel, err := filepath.Rel(s.root, pos.Filename)
+	if err != nil {
+		rel = pos.Filename
+	}
+	return fmt.Sprintf("%s:%d", filepath.ToSlash(rel), pos.Line)
+}
+
+const (
+	requestKind  = "request:"
+	responseKind = "response:"
+)
+
+func kindKey(request bool, key string) string {
+	if request {
+		return requestKind + key
+	}
+	return responseKind + key
+}
+
+// sortedCalls returns the calls with more uses first
+func sortedCalls(calls map[string]*Call) []Call {
+	sorted := make([]Call, 0, len(calls))
+	for _, c := range calls {
+		sorted = append(sorted, *c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count == sorted[j].Count {
+			return sorted[i].Pkg+"."+sorted[i].FuncName < sorted[j].Pkg+"."+sorted[j].FuncName
+		}
+		return sorted[i].Count > sorted[j].Count
+	})
+	return sorted
+}
+
+func mostVoted(votes map[string]int) string {
+	best := ""
+	for name, v := range votes {
+		if v > votes[best] || (v == votes[best] && name < best) {
+			best = name
+		}
+	}
+	return best
+}
+
+// modelParam returns the index of the interface{} parameter a model is built from
+func modelParam(fun *pkg.Function, model ast.Expr) int {
+	if u, ok := model.(*ast.UnaryExpr); ok && u.Op == token.AND {
+		model = u.X
+	}
+	if i := referencedParam(fun, model); i != noParam {
+		return i
+	}
+	ident, ok := model.(*ast.Ident)
+	if !ok {
+		return noParam
+	}
+	// a local variable built from a parameter as in resp.Data = data
+	found := noParam
+	ast.Inspect(fun.Body(), func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || found != noParam {
+			return found == noParam
+		}
+		for i, l := range assign.Lhs {
+			if rootIdent(l) != ident.Name || i >= len(assign.Rhs) {
+				continue
+			}
+			if p := referencedParam(fun, assign.Rhs[i]); p != noParam {
+				found = p
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// referencedParam returns the index of the interface{} parameter used in an expression
+func referencedParam(fun *pkg.Function, expr ast.Expr) int {
+	found := noParam
+	ast.Inspect(expr, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || found != noParam {
+			return found == noParam
+		}
+		for i, a := range fun.Args {
+			if a.Name == ident.Name && a.GoType == pkg.EmptyInterface {
+				found = i
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// intParam returns the index of the int parameter an expression is
+func intParam(fun *pkg.Function, expr ast.Expr) int {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return noParam
+	}
+	for i, a := range fun.Args {
+		if a.Name == ident.Name && a.GoType == "int" {
+			return i
+		}
+	}
+	return noParam
+}
+
+// writeHeaderParam returns the index of the parameter written as in w.WriteHeader(code)
+func writeHeaderParam(fun *pkg.Function) int {
+	found := noParam
+	ast.Inspect(fun.Body(), func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || found != noParam {
+			return found == noParam
+		}
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "WriteHeader" && len(call.Args) == 1 {
+			found = intParam(fun, call.Args[0])
+		}
+		return true
+	})
+	return found
+}
+
+// elemTypeName returns the type of the elements of a slice, pointers to a struct are the struct
+func elemTypeName(f *pkg.File, elt ast.Expr) string {
+	return strings.TrimPrefix(f.TypeName(elt), "*")
+}
+
+// isVar returns true if an expression is an identifier that is not an imported package
+func isVar(f *pkg.File, expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && len(f.ImportPath(ident.Name)) == 0
+}
+
+// isJSONStream returns true for calls as in json.NewDecoder(r.Body).Decode(&v)
+func isJSONStream(f *pkg.File, sel *ast.SelectorExpr, constructor, method string) bool {
+	if sel.Sel.Name != method {
+		return false
+	}
+	call, ok := sel.X.(*ast.CallExpr)
+	return ok && f.CallName(call) == "json."+constructor
+}
+
+func rootIdent(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.SelectorExpr:
+		return rootIdent(x.X)
+	case *ast.IndexExpr:
+		return rootIdent(x.X)
+	}
+	return ""
+}
+
+func stringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// isPath returns true for a string literal that is empty or starts with a slash
+func isPath(expr ast.Expr) bool {
+	value, ok := stringLit(expr)
+	return ok && (len(value) == 0 || strings.HasPrefix(value, "/"))
+}
+
+// httpMethod returns the method of a literal as in "GET" or http.MethodGet
+func httpMethod(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		value, _ := stringLit(x)
+		if m := criteria.MatchHTTPMethod(value); m == strings.ToUpper(value) {
+			return m
+		}
+	case *ast.SelectorExpr:
+		if strings.HasPrefix(x.Sel.Name, "Method") {
+			return criteria.MatchHTTPMethod(strings.TrimPrefix(x.Sel.Name, "Method"))
+		}
+	}
+	return ""
+}
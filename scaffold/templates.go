@@ -0,0 +1,77 @@
+package scaffold
+
+import (
+	"text/template"
+)
+
+// criteriaTemplate renders a criteriaFile, every criteria is preceded by a comment
+// with what was found in the project
+var criteriaTemplate = template.Must(template.New("criteria").Parse(`info:
+  title: {{.Title}}
+# generated by swago init for {{.Framework}}, review it and run swago routes to check
+# the routes, handlers and models that are found
+{{- range .Notes}}
+# {{.}}
+{{- end}}
+routes:
+{{- with .StructRoute}}
+  # {{.Comment}}
+  - structRoute:
+      name: {{.Name}}
+      pkg: {{.Pkg}}
+      pathField: {{.PathField}}
+      handlerField: {{.HandlerField}}
+{{- if .HTTPMethodField}}
+      httpMethodField: {{.HTTPMethodField}}
+{{- end}}
+{{- end}}
+{{- range .FuncRoutes}}
+  # {{.Comment}}
+  - funcRoute:
+      pkg: {{.Pkg}}
+      funcName: {{.FuncName}}
+{{- if .HTTPMethod}}
+      httpMethod: {{.HTTPMethod}}
+{{- end}}
+{{- if .NamedPathVarExtractor}}
+      namedPathVarExtractor: "{{.NamedPathVarExtractor}}"
+{{- end}}
+      pathIndex: 0
+      handlerIndex: 1
+{{- if .MiddlewareIndex}}
+      middlewareIndex: {{.MiddlewareIndex}}
+{{- end}}
+{{- end}}
+{{- with .Group}}
+# {{.Comment}}
+routeGroups:
+  - pkg: {{.Pkg}}
+    funcName: {{.FuncName}}
+    pathIndex: 0
+{{- if .MiddlewareIndex}}
+    middlewareIndex: {{.MiddlewareIndex}}
+{{- end}}
+{{- if .UseFuncName}}
+    useFuncName: {{.UseFuncName}}
+{{- end}}
+{{- end}}
+request:
+{{- range .Request}}
+  # {{.Comment}}
+  - pkg: {{.Pkg}}
+    funcName: {{.FuncName}}
+    modelExtractor:
+      paramIndex: {{.ModelIndex}}
+    consumes: application/json
+{{- end}}
+response:
+{{- range .Response}}
+  # {{.Comment}}
+  - pkg: {{.Pkg}}
+    funcName: {{.FuncName}}
+    modelExtractor:
+      paramIndex: {{.ModelIndex}}
+    codeIndex: {{.CodeIndex}}
+    produces: application/json
+{{- end}}
+`))
@@ -0,0 +1,233 @@
+package swago
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/pkg"
+)
+
+const (
+	oauth2SecurityType = "oauth2"
+	// securityAlternativeSeparator separates alternative requirements in a security annotation
+	securityAlternativeSeparator = "|"
+)
+
+var (
+	quotedStringRegExp = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+)
+
+// matchSecurityMiddlewares marks the security definitions whose middleware is applied
+// to a route and collects the scopes required by the route middlewares
+func matchSecurityMiddlewares(r *pkg.Route, securityMiddlewares map[string]criteria.MiddlewareMatcher) {
+	for key, matcher := range securityMiddlewares {
+		for _, m := range r.Middlewares {
+			matched := matcher.MatchesRegExp != nil && matcher.MatchesRegExp.MatchString(m)
+			if matcher.ScopesRegExp != nil {
+				scopes := middlewareScopes(matcher.ScopesRegExp, m)
+				if len(scopes) > 0 {
+					// requiring a scope requires its security definition
+					matched = true
+					if r.SecurityScopes == nil {
+						r.SecurityScopes = make(map[string][]string)
+					}
+					r.SecurityScopes[key] = appendUnique(r.SecurityScopes[key], scopes...)
+				}
+			}
+			if !matched {
+				continue
+			}
+			if r.MatchedSecurityDefinitions == nil {
+				r.MatchedSecurityDefinitions = make(map[string]bool)
+			}
+			r.MatchedSecurityDefinitions[key] = true
+			if len(matcher.Alternatives) > 0 {
+				if r.SecurityAlternatives == nil {
+					r.SecurityAlternatives = make(map[string][]string)
+				}
+				r.SecurityAlternatives[key] = matcher.Alternatives
+			}
+		}
+	}
+}
+
+// middlewareScopes returns the scopes required by a middleware, every quoted string
+// in the captured value is a scope, a captured value with no quotes is a scope itself
+func middlewareScopes(r *regexp.Regexp, middleware string) []string {
+	scopes := make([]string, 0)
+	for _, found := range r.FindAllStringSubmatch(middleware, -1) {
+		value := found[0]
+		if len(found) > 1 {
+			value = found[1]
+		}
+		quoted := quotedStringRegExp.FindAllString(value, -1)
+		if len(quoted) == 0 {
+			value = strings.TrimSpace(value)
+			if len(value) > 0 {
+				scopes = append(scopes, value)
+			}
+			continue
+		}
+		for _, q := range quoted {
+			scope, err := strconv.Unquote(q)
+			if err == nil && len(scope) > 0 {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// matchedSecurity returns the security requirements of a route. Every matched security
+// definition must be satisfied, unless it has alternatives, in which case any of them does.
+func matchedSecurity(securityDefinitions map[string]*openapi2.SecurityScheme, r pkg.Route) openapi2.SecurityRequirements {
+	keys := make([]string, 0, len(r.MatchedSecurityDefinitions))
+	for key, val := range r.MatchedSecurityDefinitions {
+		if _, ok := securityDefinitions[key]; val && ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	security := make(openapi2.SecurityRequirements, 0, len(keys))
+	for _, key := range keys {
+		alternatives := []map[string][]string{
+			{key: appendUnique(nil, r.SecurityScopes[key]...)},
+		}
+		for _, alt := range r.SecurityAlternatives[key] {
+			if _, ok := securityDefinitions[alt]; ok && alt != key {
+				alternatives = append(alternatives, map[string][]string{
+					alt: appendUnique(nil, r.SecurityScopes[alt]...),
+				})
+			}
+		}
+		security = combineSecurity(security, alternatives)
+	}
+	return security
+}
+
+// combineSecurity requires any of the alternatives besides the existing requirements.
+// Security requirements are ORed and the schemes in each requirement are ANDed, so
+// every existing requirement is combined with every alternative.
+func combineSecurity(security openapi2.SecurityRequirements, alternatives []map[string][]string) openapi2.SecurityRequirements {
+	if len(alternatives) == 0 {
+		return security
+	}
+	if len(security) == 0 {
+		security = openapi2.SecurityRequirements{make(map[string][]string)}
+	}
+	combined := make(openapi2.SecurityRequirements, 0, len(security)*len(alternatives))
+	for _, requirement := range security {
+		for _, alt := range alternatives {
+			c := make(map[string][]string, len(requirement)+len(alt))
+			for scheme, scopes := range requirement {
+				c[scheme] = append([]string{}, scopes...)
+			}
+			for scheme, scopes := range alt {
+				c[scheme] = appendUnique(c[scheme], scopes...)
+			}
+			combined = append(combined, c)
+		}
+	}
+	return combined
+}
+
+// annotatedSecurityAlternatives parses the arguments of a security annotation as in
+// "swago:security oauth users:write | apiKey"
+func annotatedSecurityAlternatives(args []string) []map[string][]string {
+	alternatives := make([]map[string][]string, 0)
+	current := make([]string, 0)
+	appendAlternative := func() {
+		if len(current) > 0 {
+			alternatives = append(alternatives, map[string][]string{
+				current[0]: append([]string{}, current[1:]...),
+			})
+		}
+		current = make([]string, 0)
+	}
+	for _, arg := range args {
+		for i, part := range strings.Split(arg, securityAlternativeSeparator) {
+			if i > 0 {
+				appendAlternative()
+			}
+			if len(part) > 0 {
+				current = append(current, part)
+			}
+		}
+	}
+	appendAlternative()
+	return alternatives
+}
+
+// copySecurityDefinitions copies the security definitions of the criteria, the scopes used by
+// the operations are declared in the copy so every generation starts from the criteria
+func copySecurityDefinitions(securityDefinitions map[string]*openapi2.SecurityScheme) map[string]*openapi2.SecurityScheme {
+	if securityDefinitions == nil {
+		return nil
+	}
+	copied := make(map[string]*openapi2.SecurityScheme, len(securityDefinitions))
+	for name, definition := range securityDefinitions {
+		if definition == nil {
+			copied[name] = nil
+			continue
+		}
+		scheme := *definition
+		if definition.Scopes != nil {
+			scheme.Scopes = make(map[string]string, len(definition.Scopes))
+			for scope, description := range definition.Scopes {
+				scheme.Scopes[scope] = description
+			}
+		}
+		if definition.Tags != nil {
+			scheme.Tags = append(openapi3.Tags{}, definition.Tags...)
+		}
+		copied[name] = &scheme
+	}
+	return copied
+}
+
+// addSecurityScopes declares in the OAuth2 security definitions the scopes used by an operation
+func addSecurityScopes(securityDefinitions map[string]*openapi2.SecurityScheme, security *openapi2.SecurityRequirements) {
+	if security == nil {
+		return
+	}
+	for _, requirement := range *security {
+		for scheme, scopes := range requirement {
+			definition, ok := securityDefinitions[scheme]
+			if !ok || definition == nil || definition.Type != oauth2SecurityType {
+				continue
+			}
+			for _, scope := range scopes {
+				if definition.Scopes == nil {
+					definition.Scopes = make(map[string]string)
+				}
+				if _, declared := definition.Scopes[scope]; !declared {
+					definition.Scopes[scope] = ""
+				}
+			}
+		}
+	}
+}
+
+func appendUnique(values []string, newValues ...string) []string {
+	if values == nil {
+		values = make([]string, 0, len(newValues))
+	}
+	for _, nv := range newValues {
+		found := false
+		for _, v := range values {
+			if v == nv {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, nv)
+		}
+	}
+	return values
+}
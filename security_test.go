@@ -0,0 +1,182 @@
+package swago
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchSecurityMiddlewares(t *testing.T) {
+	securityMiddlewares := map[string]criteria.MiddlewareMatcher{
+		"jwt": {
+			MatchesRegExp: regexp.MustCompile(`^security\.JWT\(`),
+			ScopesRegExp:  regexp.MustCompile(`RequireScope\((.*)\)`),
+			Alternatives:  []string{"apiKey"},
+		},
+		"basic": {
+			MatchesRegExp: regexp.MustCompile(`^middleware\.BasicAuth`),
+		},
+	}
+	type params struct {
+		middlewares []string
+	}
+	type expected struct {
+		definitions  map[string]bool
+		scopes       map[string][]string
+		alternatives map[string][]string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should not match a route without security middlewares",
+			params:   params{middlewares: []string{"middleware.Logger()"}},
+			expected: expected{},
+		},
+		{
+			name:   "should match a security middleware and its alternatives",
+			params: params{middlewares: []string{"security.JWT(secret, false)"}},
+			expected: expected{
+				definitions:  map[string]bool{"jwt": true},
+				alternatives: map[string][]string{"jwt": {"apiKey"}},
+			},
+		},
+		{
+			name:   "should require the security definition of the scopes",
+			params: params{middlewares: []string{`RequireScope("users:read", "users:write")`, `RequireScope("users:read")`}},
+			expected: expected{
+				definitions:  map[string]bool{"jwt": true},
+				scopes:       map[string][]string{"jwt": {"users:read", "users:write"}},
+				alternatives: map[string][]string{"jwt": {"apiKey"}},
+			},
+		},
+		{
+			name:   "should match every security middleware",
+			params: params{middlewares: []string{"middleware.BasicAuth(validate)", "security.JWT(secret, false)"}},
+			expected: expected{
+				definitions:  map[string]bool{"jwt": true, "basic": true},
+				alternatives: map[string][]string{"jwt": {"apiKey"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := pkg.Route{Middlewares: test.params.middlewares}
+			matchSecurityMiddlewares(&r, securityMiddlewares)
+			assert.Equal(t, test.expected.definitions, r.MatchedSecurityDefinitions)
+			assert.Equal(t, test.expected.scopes, r.SecurityScopes)
+			assert.Equal(t, test.expected.alternatives, r.SecurityAlternatives)
+		})
+	}
+}
+
+func TestCombineSecurity(t *testing.T) {
+	type params struct {
+		security     openapi2.SecurityRequirements
+		alternatives []map[string][]string
+	}
+	type expected struct {
+		security openapi2.SecurityRequirements
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name: "should keep the security without alternatives",
+			params: params{
+				security: openapi2.SecurityRequirements{{"jwt": {}}},
+			},
+			expected: expected{security: openapi2.SecurityRequirements{{"jwt": {}}}},
+		},
+		{
+			name: "should require any of the alternatives",
+			params: params{
+				alternatives: []map[string][]string{{"jwt": {"users:read"}}, {"apiKey": {}}},
+			},
+			expected: expected{security: openapi2.SecurityRequirements{{"jwt": {"users:read"}}, {"apiKey": {}}}},
+		},
+		{
+			name: "should combine every requirement with every alternative",
+			params: params{
+				security:     openapi2.SecurityRequirements{{"jwt": {"users:read"}}, {"apiKey": {}}},
+				alternatives: []map[string][]string{{"jwt": {"users:write"}}, {"basic": {}}},
+			},
+			expected: expected{security: openapi2.SecurityRequirements{
+				{"jwt": {"users:read", "users:write"}},
+				{"jwt": {"users:read"}, "basic": {}},
+				{"apiKey": {}, "jwt": {"users:write"}},
+				{"apiKey": {}, "basic": {}},
+			}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			security := combineSecurity(test.params.security, test.params.alternatives)
+			assert.Equal(t, test.expected.security, security)
+		})
+	}
+}
+
+func TestAnnotatedSecurityAlternatives(t *testing.T) {
+	type params struct {
+		args []string
+	}
+	type expected struct {
+		alternatives []map[string][]string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should parse a scheme with scopes",
+			params:   params{args: []string{"oauth", "users:read", "users:write"}},
+			expected: expected{alternatives: []map[string][]string{{"oauth": {"users:read", "users:write"}}}},
+		},
+		{
+			name:     "should split the alternatives",
+			params:   params{args: []string{"oauth", "users:write", "|", "apiKey"}},
+			expected: expected{alternatives: []map[string][]string{{"oauth": {"users:write"}}, {"apiKey": {}}}},
+		},
+		{
+			name:     "should split the alternatives without spaces",
+			params:   params{args: []string{"oauth|apiKey|basic"}},
+			expected: expected{alternatives: []map[string][]string{{"oauth": {}}, {"apiKey": {}}, {"basic": {}}}},
+		},
+		{
+			name:     "should ignore empty alternatives",
+			params:   params{args: []string{"|", "apiKey", "|"}},
+			expected: expected{alternatives: []map[string][]string{{"apiKey": {}}}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alternatives := annotatedSecurityAlternatives(test.params.args)
+			assert.Equal(t, test.expected.alternatives, alternatives)
+		})
+	}
+}
+
+func TestAddSecurityScopes(t *testing.T) {
+	criteriaDefinitions := map[string]*openapi2.SecurityScheme{
+		"oauth":  {Type: "oauth2", Scopes: map[string]string{"users:read": "Read the users"}},
+		"apiKey": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+	}
+	securityDefinitions := copySecurityDefinitions(criteriaDefinitions)
+	addSecurityScopes(securityDefinitions, &openapi2.SecurityRequirements{
+		{"oauth": {"users:read", "users:write"}},
+		{"apiKey": {"ignored"}},
+	})
+	assert.Equal(t, map[string]string{"users:read": "Read the users", "users:write": ""}, securityDefinitions["oauth"].Scopes)
+	assert.Nil(t, securityDefinitions["apiKey"].Scopes)
+	assert.Equal(t, map[string]string{"users:read": "Read the users"}, criteriaDefinitions["oauth"].Scopes, "the criteria must not be modified")
+}
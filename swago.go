@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -14,7 +15,10 @@ import (
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"github.com/javiercbk/swago/encoding/swagger"
 	swagoErrors "github.com/javiercbk/swago/errors"
+	"github.com/javiercbk/swago/folder"
 	"github.com/javiercbk/swago/pkg"
 	"golang.org/x/mod/modfile"
 )
@@ -43,43 +47,123 @@ type SwaggerGenerator struct {
 	RootPath      string
 	GoPath        string
 	logger        *log.Logger
+	project       *pkg.Project
 	routes        []pkg.Route
+	examples      pkg.LiteralExamples
+	// Extensions holds the generated properties that openapi2 does not model
+	Extensions swagger.Extensions
+	// Tracer receives every step of the evaluation of the criteria when it is set
+	Tracer Tracer
+	// Diagnostics collects the problems found while documenting the project
+	Diagnostics *diagnostic.List
 }
 
 // GenerateSwaggerDoc generates the swagger documentation
 func (s *SwaggerGenerator) GenerateSwaggerDoc(projectCriterias criteria.Criteria, swagger *openapi2.Swagger) error {
+	s.Extensions.Operations = nil
+	_, err := s.FindRoutes(projectCriterias)
+	if err != nil {
+		return err
+	}
+	return s.completeSwagger(projectCriterias, swagger)
+}
+
+// FindRoutes finds the routes of the project and the request and response models of their handlers
+func (s *SwaggerGenerator) FindRoutes(projectCriterias criteria.Criteria) ([]pkg.Route, error) {
+	s.routes = make([]pkg.Route, 0)
+	s.examples = nil
+	s.Diagnostics.Reset()
+	s.reportValidationConflicts(projectCriterias)
+	if projectCriterias.Examples.TestFiles {
+		examples, err := pkg.FindTestExamples(s.RootPath, s.logger, s.Blacklist)
+		if err != nil {
+			return s.routes, err
+		}
+		s.examples = examples
+	}
+	funcRoutes := make([]criteria.FuncRoute, 0)
 	for _, r := range projectCriterias.Routes {
 		if r.StructRoute != nil {
+			found := len(s.routes)
 			s.findStructRoutes(*r.StructRoute)
+			s.trace(nil, token.Position{}, "structRoute %s.%s matched %d routes", r.StructRoute.Pkg, r.StructRoute.Name, len(s.routes)-found)
+		} else if r.FuncRoute != nil {
+			funcRoutes = append(funcRoutes, *r.FuncRoute)
 		}
 	}
+	if len(funcRoutes) > 0 {
+		found := len(s.routes)
+		s.findFuncRoutes(funcRoutes, projectCriterias.RouteGroups)
+		s.trace(nil, token.Position{}, "%d funcRoute criterias matched %d routes", len(funcRoutes), len(s.routes)-found)
+	}
 	for i := range s.routes {
-		if len(s.routes[i].HandlerType) > 0 {
-			pkgName, funcName := pkg.TypeParts(s.routes[i].HandlerType)
-			for _, rc := range projectCriterias.Request {
-				requestModel := pkg.Struct{}
-				err := s.findReqModel(pkgName, funcName, rc, &requestModel)
-				if err != nil {
-					return err
-				}
-				requestModel.CallCriteria = rc
-				s.routes[i].RequestModel = requestModel
-				// when a route criteria matches, do not process following callCriteria
-				break
+		matchSecurityMiddlewares(&s.routes[i], projectCriterias.SecurityMiddlewares)
+	}
+	for i := range s.routes {
+		r := &s.routes[i]
+		s.traceRoute(r)
+		if !criteria.MatchesHTTPMethod(r.HTTPMethod) {
+			s.report(diagnostic.Error, r, "", r.Position, "unknown http method %q, the route is not documented", r.HTTPMethod)
+		}
+		if len(r.HandlerType) == 0 {
+			s.report(diagnostic.Error, r, "", r.Position, "the handler is not a function, a method value or a conversion as in http.HandlerFunc(handler), the route is not documented")
+			continue
+		}
+		pkgName, receiver, funcName := pkg.HandlerParts(r.HandlerType)
+		handler := pkg.Function{Name: funcName, MemberOf: receiver}
+		if err := s.findFunc(r.HandlerPkgPath, &handler); err != nil {
+			if s.pkgByPath(r.HandlerPkgPath) == nil {
+				s.report(diagnostic.Warning, r, "", r.Position, "handler %s not found, package %s is not part of the project", r.HandlerType, pkgName)
+			} else {
+				s.report(diagnostic.Warning, r, "", r.Position, "handler %s not found in package %s", r.HandlerType, pkgName)
 			}
-			serviceResponses := make([]pkg.ServiceResponse, 0)
-			for _, rc := range projectCriterias.Response {
-				responses, err := s.findResModels(pkgName, funcName, rc)
-				if err != nil {
-					return err
-				}
-				serviceResponses = append(serviceResponses, responses...)
+			continue
+		}
+		r.Handler = &handler
+		s.trace(r, handler.Position(), "handler %s", r.HandlerType)
+		for _, rc := range projectCriterias.Request {
+			requestModel := pkg.Struct{}
+			err := s.findReqModel(r, handler, rc, &requestModel)
+			if err == swagoErrors.ErrNotFound {
+				// the handler has no call or model for this criteria, try the next one
+				continue
+			}
+			if err != nil {
+				return s.routes, err
 			}
-			s.routes[i].ServiceResponses = serviceResponses
+			requestModel.CallCriteria = rc
+			requestModel.Options = s.schemaOptions(projectCriterias, false)
+			r.RequestModel = requestModel
+			// when a route criteria matches, do not process following callCriteria
+			break
+		}
+		serviceResponses := make([]pkg.ServiceResponse, 0)
+		for _, rc := range projectCriterias.Response {
+			responses, err := s.findResModels(r, handler, rc)
+			if err != nil {
+				return s.routes, err
+			}
+			for j := range responses {
+				responses[j].Model.CallCriteria = rc
+				responses[j].Model.Options = s.schemaOptions(projectCriterias, true)
+			}
+			serviceResponses = append(serviceResponses, responses...)
 		}
+		r.ServiceResponses = serviceResponses
+	}
+	return s.routes, nil
+}
+
+// schemaOptions returns the options used to document the models of the project
+func (s *SwaggerGenerator) schemaOptions(projectCriterias criteria.Criteria, response bool) pkg.SchemaOptions {
+	return pkg.SchemaOptions{
+		Validations:   projectCriterias.Validations,
+		TypeOverrides: projectCriterias.TypeOverrides,
+		Nullability:   projectCriterias.Nullability,
+		Response:      response,
+		ExamplePolicy: projectCriterias.Examples,
+		Examples:      s.examples,
 	}
-	s.completeSwagger(projectCriterias, swagger)
-	return nil
 }
 
 func (s *SwaggerGenerator) loadExternalPkg(location string) error {
@@ -142,30 +226,52 @@ func folderExists(folderPath string) bool {
 	return fileStat.IsDir()
 }
 
+// documentModel documents the schema of a model, the structs of its fields are added
+// to the definitions of the swagger document
+func documentModel(model *pkg.Struct, projectCriterias criteria.Criteria, swagger *openapi2.Swagger) error {
+	model.Options.Definitions = swagger.Definitions
+	model.Options.DefinitionPrefix = projectCriterias.DefinitionPrefix
+	return model.ToSwaggerSchema()
+}
+
 func (s *SwaggerGenerator) completeSwagger(projectCriterias criteria.Criteria, swagger *openapi2.Swagger) error {
 	swagger.BasePath = projectCriterias.BasePath
 	swagger.Host = projectCriterias.Host
 	swagger.Parameters = projectCriterias.Parameters
-	swagger.SecurityDefinitions = projectCriterias.SecurityDefinitions
+	swagger.SecurityDefinitions = copySecurityDefinitions(projectCriterias.SecurityDefinitions)
 	swagger.Definitions = make(map[string]*openapi3.SchemaRef)
+	errorModel, err := s.addErrorModel(projectCriterias, swagger)
+	if err != nil {
+		return err
+	}
+	globalResponseRefs, err := s.addGlobalResponses(projectCriterias, errorModel, swagger)
+	if err != nil {
+		return err
+	}
+	usedOperationIDs := make(map[string]bool)
+	reservedOperationIDs := annotatedOperationIDs(s.routes)
 	for _, r := range s.routes {
-		if len(r.HandlerType) == 0 {
-			// ignore routes with no handler
+		if len(r.HandlerType) == 0 || !criteria.MatchesHTTPMethod(r.HTTPMethod) {
+			// ignore routes with no handler or with an unknown http method
+			continue
+		}
+		if r.Handler != nil && pkg.HasAnnotation(r.Handler.Annotations, pkg.AnnotationIgnore) {
 			continue
 		}
+		diagnosticsSince := s.Diagnostics.Len()
 
-		err := r.RequestModel.ToSwaggerSchema()
+		err := documentModel(&r.RequestModel, projectCriterias, swagger)
 		parameter := &openapi2.Parameter{
 			In:       "body",
-			Name:     r.RequestModel.Name,
+			Name:     r.RequestModel.DefinitionName(),
 			Required: true,
 			Schema:   &openapi3.SchemaRef{},
 		}
 		if len(projectCriterias.DefinitionPrefix) == 0 {
 			parameter.Schema.Value = r.RequestModel.Schema
 		} else {
-			definitionName := projectCriterias.DefinitionPrefix + r.RequestModel.Name
-			parameter.Schema.Ref = "#/definitions/" + projectCriterias.DefinitionPrefix + r.RequestModel.Name
+			definitionName := projectCriterias.DefinitionPrefix + r.RequestModel.DefinitionName()
+			parameter.Schema.Ref = "#/definitions/" + definitionName
 			swagger.Definitions[definitionName] = &openapi3.SchemaRef{
 				Value: r.RequestModel.Schema,
 			}
@@ -185,9 +291,12 @@ func (s *SwaggerGenerator) completeSwagger(projectCriterias criteria.Criteria, s
 			sResp := r.ServiceResponses[i]
 			httpStatusCode, _ := parseCode(sResp.Code)
 			httpStatusCodeStr := strconv.Itoa(httpStatusCode)
+			if httpStatusCode <= 0 {
+				s.report(diagnostic.Warning, &r, criteriaName(responseTrace, sResp.Model.CallCriteria), sResp.Position, "unknown status code %s, the response is not documented", sResp.Code)
+			}
 			if httpStatusCode > 0 {
 				if len(sResp.ModelExtractor.Name) == 0 {
-					err := sResp.Model.ToSwaggerSchema()
+					err := documentModel(&sResp.Model, projectCriterias, swagger)
 					if err != nil {
 						return err
 					}
@@ -197,15 +306,17 @@ func (s *SwaggerGenerator) completeSwagger(projectCriterias criteria.Criteria, s
 							Description: http.StatusText(httpStatusCode),
 							Schema:      &openapi3.SchemaRef{},
 						}
-						if len(projectCriterias.DefinitionPrefix) == 0 {
+						if len(projectCriterias.DefinitionPrefix) == 0 || len(sResp.Model.Name) == 0 {
+							// anonymous models are not definitions
 							resp.Schema.Value = sResp.Model.Schema
 						} else {
-							definitionName := projectCriterias.DefinitionPrefix + sResp.Model.Name
+							definitionName := projectCriterias.DefinitionPrefix + sResp.Model.DefinitionName()
 							resp.Schema.Ref = "#/definitions/" + definitionName
 							swagger.Definitions[definitionName] = &openapi3.SchemaRef{
 								Value: sResp.Model.Schema,
 							}
 						}
+						resp.Headers = responseHeaders(sResp.Headers, projectCriterias.ResponseHeaders)
 						swaggerResponses[httpStatusCodeStr] = resp
 					}
 				} else {
@@ -223,6 +334,9 @@ func (s *SwaggerGenerator) completeSwagger(projectCriterias criteria.Criteria, s
 							Value: staticModel,
 						}
 					} else {
+						resp = errorResponse(httpStatusCodeStr, globalResponseRefs, errorModel)
+					}
+					if resp == nil {
 						// when no model was found set a dummy response
 						resp = &openapi2.Response{
 							Description: http.StatusText(httpStatusCode),
@@ -233,6 +347,9 @@ func (s *SwaggerGenerator) completeSwagger(projectCriterias criteria.Criteria, s
 							},
 						}
 					}
+					if len(resp.Ref) == 0 {
+						resp.Headers = responseHeaders(sResp.Headers, projectCriterias.ResponseHeaders)
+					}
 					swaggerResponses[httpStatusCodeStr] = resp
 				}
 			}
@@ -243,46 +360,82 @@ func (s *SwaggerGenerator) completeSwagger(projectCriterias criteria.Criteria, s
 		parameters = append(parameters, urlParameters...)
 		parameters = append(parameters, additionalParams...)
 		parameters = append(parameters, parameter)
-		security := matchedSecurity(projectCriterias.SecurityDefinitions, r.MatchedSecurityDefinitions)
-		swagger.AddOperation(r.Path, r.HTTPMethod, &openapi2.Operation{
-			Consumes:   []string{r.RequestModel.CallCriteria.Consumes},
-			Produces:   []string{produces},
-			Parameters: parameters,
-			Responses:  swaggerResponses,
-			Security:   &security,
-		})
+		security := matchedSecurity(projectCriterias.SecurityDefinitions, r)
+		var summary, description string
+		if r.Handler != nil {
+			summary, description = pkg.DocSummary(r.Handler.Name, r.Handler.Doc)
+		}
+		operation := &openapi2.Operation{
+			Summary:     summary,
+			Description: description,
+			OperationID: operationID(r),
+			Consumes:    []string{r.RequestModel.CallCriteria.Consumes},
+			Produces:    []string{produces},
+			Parameters:  parameters,
+			Responses:   swaggerResponses,
+			Security:    &security,
+		}
+		tag, tagDescription := s.routeTag(r, projectCriterias)
+		if len(tag) > 0 {
+			operation.Tags = []string{tag}
+		}
+		if r.Handler != nil {
+			if pkg.IsDeprecated(r.Handler.Doc) {
+				s.deprecateOperation(operation)
+			}
+			err = s.applyAnnotations(&r, operation, projectCriterias, swagger)
+			if err != nil {
+				return err
+			}
+		}
+		s.reserveOperationID(&r, operation, usedOperationIDs, reservedOperationIDs)
+		addSecurityScopes(swagger.SecurityDefinitions, operation.Security)
+		addGlobalResponseRefs(operation, globalResponseRefs, projectCriterias)
+		addMiddlewareHeaders(r, operation.Responses, projectCriterias.ResponseHeaders)
+		if projectCriterias.Examples.Responses {
+			addResponseExamples(operation.Responses, produces, swagger)
+		}
+		for _, t := range operation.Tags {
+			if t == tag {
+				addTag(swagger, t, tagDescription)
+			} else {
+				addTag(swagger, t, "")
+			}
+		}
+		swagger.AddOperation(r.Path, r.HTTPMethod, operation)
+		s.reportSchemaDiagnostics(diagnosticsSince, r)
 	}
 	return nil
 }
 
-func (s *SwaggerGenerator) findReqModel(pkgName, funcName string, callCriteria criteria.CallCriteria, model *pkg.Struct) error {
-	err := s.findReqModelInFunc(pkgName, funcName, callCriteria, model)
-	if err != nil && err != swagoErrors.ErrNotFound {
-		return err
+func (s *SwaggerGenerator) findReqModel(r *pkg.Route, handler pkg.Function, callCriteria criteria.CallCriteria, model *pkg.Struct) error {
+	s.traceCalls(r, handler, requestTrace, callCriteria)
+	varType, err := handler.FindArgTypeCallExpression(callCriteria)
+	if err == swagoErrors.ErrNotFound {
+		s.reportUninferredRequest(r, handler, callCriteria)
 	}
-	pkgFound := s.getPkg(model.PkgName)
-	if pkgFound == nil {
-		return swagoErrors.ErrNotFound
-	}
-	err = pkgFound.FindStruct(model)
 	if err != nil {
 		return err
 	}
-	return nil
+	model.PkgName, model.Name = pkg.TypeParts(varType)
+	return s.findModel(r, requestTrace, callCriteria, handler.Position(), model)
 }
 
-func (s *SwaggerGenerator) findResModels(pkgName, funcName string, callCriteria criteria.CallCriteria) ([]pkg.ServiceResponse, error) {
-	serviceResponses, err := s.findServiceResponsesInFunc(pkgName, funcName, callCriteria)
+func (s *SwaggerGenerator) findResModels(r *pkg.Route, handler pkg.Function, callCriteria criteria.CallCriteria) ([]pkg.ServiceResponse, error) {
+	s.traceCalls(r, handler, responseTrace, callCriteria)
+	serviceResponses, err := s.findServiceResponsesInFunc(r, handler, callCriteria)
 	if err != nil && err != swagoErrors.ErrNotFound {
 		return serviceResponses, err
 	}
 	for i := range serviceResponses {
 		if len(serviceResponses[i].Model.Name) > 0 {
-			pkgFound := s.getPkg(serviceResponses[i].Model.PkgName)
-			if pkgFound == nil {
-				return serviceResponses, swagoErrors.ErrNotFound
+			err = s.findModel(r, responseTrace, callCriteria, serviceResponses[i].Position, &serviceResponses[i].Model)
+			if err == swagoErrors.ErrNotFound {
+				// findModel reported a warning, the response is documented
+				// with its status code and without a model
+				serviceResponses[i].Model = pkg.Struct{}
+				continue
 			}
-			err = pkgFound.FindStruct(&serviceResponses[i].Model)
 			if err != nil {
 				return serviceResponses, err
 			}
@@ -291,8 +444,27 @@ func (s *SwaggerGenerator) findResModels(pkgName, funcName string, callCriteria
 	return serviceResponses, nil
 }
 
+// findModel finds the struct of a request or response model
+func (s *SwaggerGenerator) findModel(r *pkg.Route, kind string, callCriteria criteria.CallCriteria, pos token.Position, model *pkg.Struct) error {
+	name := model.PkgName + "." + model.Name
+	criteriaName := criteriaName(kind, callCriteria)
+	pkgFound := s.getPkg(model.PkgName)
+	if pkgFound == nil {
+		s.report(diagnostic.Warning, r, criteriaName, pos, "%s model %s not found, package %s is not part of the project", kind, name, model.PkgName)
+		return swagoErrors.ErrNotFound
+	}
+	err := pkgFound.FindStruct(model)
+	if err == swagoErrors.ErrNotFound {
+		s.report(diagnostic.Warning, r, criteriaName, pos, "%s model %s not found, it is not a struct of package %s", kind, name, pkgFound.Name)
+	}
+	if err != nil {
+		return err
+	}
+	s.trace(r, model.Position(), "%s model %s", kind, model.QualifiedName())
+	return nil
+}
+
 func (s *SwaggerGenerator) findStructRoutes(structRoute criteria.StructRoute) []pkg.Route {
-	s.routes = make([]pkg.Route, 0)
 	for _, p := range s.Pkgs {
 		foundRoutes := p.SearchForStructRoutes(structRoute)
 		s.routes = append(s.routes, foundRoutes...)
@@ -300,33 +472,21 @@ func (s *SwaggerGenerator) findStructRoutes(structRoute criteria.StructRoute) []
 	return s.routes
 }
 
-func (s *SwaggerGenerator) findReqModelInFunc(pkgName, funcName string, rc criteria.CallCriteria, requestModel *pkg.Struct) error {
-	fun := pkg.Function{
-		Name: funcName,
-	}
-	err := s.findFunc(pkgName, funcName, &fun)
-	if err != nil {
-		return err
-	}
-	varType, err := fun.FindArgTypeCallExpression(rc)
-	if err != nil {
-		return err
+func (s *SwaggerGenerator) findFuncRoutes(funcRoutes []criteria.FuncRoute, groups []criteria.RouteGroup) []pkg.Route {
+	project := pkg.Project{
+		Pkgs:        s.Pkgs,
+		RootPath:    s.RootPath,
+		Module:      s.ModuleName,
+		Blacklist:   s.Blacklist,
+		Diagnostics: s.Diagnostics,
 	}
-	pkgName, structName := pkg.TypeParts(varType)
-	requestModel.PkgName = pkgName
-	requestModel.Name = structName
-	return nil
+	s.routes = append(s.routes, project.SearchForFuncRoutes(funcRoutes, groups)...)
+	return s.routes
 }
 
-func (s *SwaggerGenerator) findServiceResponsesInFunc(pkgName, funcName string, rc criteria.CallCriteria) ([]pkg.ServiceResponse, error) {
+func (s *SwaggerGenerator) findServiceResponsesInFunc(r *pkg.Route, fun pkg.Function, rc criteria.CallCriteria) ([]pkg.ServiceResponse, error) {
 	serviceResponses := make([]pkg.ServiceResponse, 0)
-	fun := pkg.Function{
-		Name: funcName,
-	}
-	err := s.findFunc(pkgName, funcName, &fun)
-	if err != nil {
-		return serviceResponses, err
-	}
+	var err error
 	var lastPos token.Pos = -1
 	if len(rc.ModelExtractor.Name) == 0 {
 		for {
@@ -343,7 +503,15 @@ func (s *SwaggerGenerator) findServiceResponsesInFunc(pkgName, funcName string,
 				},
 				Code:           modelResponse.Code,
 				ModelExtractor: rc.ModelExtractor,
+				Headers:        modelResponse.Headers,
+				Position:       fun.PositionOf(modelResponse.Pos),
+			}
+			responseType := modelResponse.Type
+			if modelResponse.Inline != nil {
+				sr.Model = *modelResponse.Inline
+				responseType = "inline"
 			}
+			s.trace(r, sr.Position, "response criteria %s.%s: %s response of type %s", rc.Pkg, rc.FuncName, modelResponse.Code, responseType)
 			serviceResponses = append(serviceResponses, sr)
 		}
 	} else {
@@ -357,7 +525,10 @@ func (s *SwaggerGenerator) findServiceResponsesInFunc(pkgName, funcName string,
 			sr := pkg.ServiceResponse{
 				Code:           modelResponse.Code,
 				ModelExtractor: rc.ModelExtractor,
+				Headers:        modelResponse.Headers,
+				Position:       fun.PositionOf(modelResponse.Pos),
 			}
+			s.trace(r, sr.Position, "response criteria %s.%s: %s error response", rc.Pkg, rc.FuncName, modelResponse.Code)
 			serviceResponses = append(serviceResponses, sr)
 		}
 	}
@@ -367,8 +538,9 @@ func (s *SwaggerGenerator) findServiceResponsesInFunc(pkgName, funcName string,
 	return serviceResponses, nil
 }
 
-func (s *SwaggerGenerator) findFunc(pkgName, funcName string, fun *pkg.Function) error {
-	pkgFound := s.getPkg(pkgName)
+// findFunc finds a function or a method in the package of a directory
+func (s *SwaggerGenerator) findFunc(pkgPath string, fun *pkg.Function) error {
+	pkgFound := s.pkgByPath(pkgPath)
 	if pkgFound == nil {
 		return swagoErrors.ErrNotFound
 	}
@@ -379,6 +551,19 @@ func (s *SwaggerGenerator) findFunc(pkgName, funcName string, fun *pkg.Function)
 	return nil
 }
 
+// pkgByPath returns the package of a directory of the project
+func (s *SwaggerGenerator) pkgByPath(pkgPath string) *pkg.Pkg {
+	if len(pkgPath) == 0 {
+		return nil
+	}
+	for _, p := range s.Pkgs {
+		if filepath.Clean(p.Path) == filepath.Clean(pkgPath) {
+			return p
+		}
+	}
+	return nil
+}
+
 func (s *SwaggerGenerator) getPkg(name string) *pkg.Pkg {
 	for _, p := range s.Pkgs {
 		if p.Name == name {
@@ -392,10 +577,11 @@ func (s *SwaggerGenerator) getPkg(name string) *pkg.Pkg {
 func NewSwaggerGeneratorWithBlacklist(rootPath, goPath string, vendorFolders []string, logger *log.Logger, blacklist []*regexp.Regexp) (*SwaggerGenerator, error) {
 	var err error
 	generator := &SwaggerGenerator{
-		RootPath:  rootPath,
-		GoPath:    goPath,
-		Blacklist: blacklist,
-		logger:    logger,
+		RootPath:    rootPath,
+		GoPath:      goPath,
+		Blacklist:   blacklist,
+		logger:      logger,
+		Diagnostics: &diagnostic.List{},
 	}
 	goModFilePath := path.Join(rootPath, modFileName)
 	logger.Printf("looking for module declaration in file %s\n", goModFilePath)
@@ -416,25 +602,71 @@ func NewSwaggerGeneratorWithBlacklist(rootPath, goPath string, vendorFolders []s
 		if err != nil {
 			return generator, err
 		}
-		generator.ModuleName = module.Syntax.Name
+		if module.Module != nil {
+			generator.ModuleName = module.Module.Mod.Path
+		}
 		generator.module = module
 	}
 	generator.Pkgs, err = pkg.AnalizeProjectWithBlacklist(rootPath, logger, blacklist)
 	if err != nil {
 		return generator, err
 	}
-	project := pkg.Project{
-		Pkgs:      generator.Pkgs,
-		RootPath:  rootPath,
-		Blacklist: blacklist,
+	generator.project = &pkg.Project{
+		Pkgs:        generator.Pkgs,
+		RootPath:    rootPath,
+		Module:      generator.ModuleName,
+		Blacklist:   blacklist,
+		Diagnostics: generator.Diagnostics,
 	}
 	for i := range generator.Pkgs {
-		generator.Pkgs[i].Project = &project
+		generator.Pkgs[i].Project = generator.project
 	}
 	generator.VendorFolders = vendorFolders
 	return generator, nil
 }
 
+// Reanalyze analyzes again the packages of some directories of the project, the packages
+// of a new directory are added and the packages of a directory without go files are removed
+func (s *SwaggerGenerator) Reanalyze(dirs []string) error {
+	for _, dir := range dirs {
+		goFiles, err := folder.ListGoFiles(dir, s.Blacklist)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		pkgs := make([]*pkg.Pkg, 0, len(s.Pkgs))
+		found := false
+		for _, p := range s.Pkgs {
+			if p.Path != dir {
+				pkgs = append(pkgs, p)
+				continue
+			}
+			if len(goFiles) == 0 {
+				s.logger.Printf("removing package %s in path %s\n", p.Name, dir)
+				continue
+			}
+			found = true
+			err = p.Analyze()
+			if err != nil {
+				return err
+			}
+			pkgs = append(pkgs, p)
+		}
+		if !found && len(goFiles) > 0 {
+			dirPkgs, err := pkg.AnalyzeDirWithBlacklist(dir, s.logger, s.Blacklist)
+			if err != nil {
+				return err
+			}
+			for _, p := range dirPkgs {
+				p.Project = s.project
+			}
+			pkgs = append(pkgs, dirPkgs...)
+		}
+		s.Pkgs = pkgs
+	}
+	s.project.Pkgs = s.Pkgs
+	return nil
+}
+
 // NewSwaggerGenerator creates a swagger generator that scans a whole project
 func NewSwaggerGenerator(rootPath, goPath string, vendorFolders []string, logger *log.Logger) (*SwaggerGenerator, error) {
 	return NewSwaggerGeneratorWithBlacklist(rootPath, goPath, vendorFolders, logger, defaultBlacklist)
@@ -455,22 +687,6 @@ func additionalParameters(parameters map[string]*openapi2.Parameter, matched map
 	return params
 }
 
-func matchedSecurity(securityDefinitions map[string]*openapi2.SecurityScheme, matched map[string]bool) openapi2.SecurityRequirements {
-	security := make(openapi2.SecurityRequirements, 0, len(matched))
-	for key, val := range matched {
-		if val {
-			_, ok := securityDefinitions[key]
-			if ok {
-				// TODO: add the necesary array iteme here
-				s := make(map[string][]string)
-				s[key] = make([]string, 0)
-				security = append(security, s)
-			}
-		}
-	}
-	return security
-}
-
 func extractNamedPathVarParameters(path string, r *regexp.Regexp) []*openapi2.Parameter {
 	foundPathParameters := make([]*openapi2.Parameter, 0)
 	found := r.FindAllStringSubmatch(path, -1)
@@ -487,6 +703,13 @@ func extractNamedPathVarParameters(path string, r *regexp.Regexp) []*openapi2.Pa
 	return foundPathParameters
 }
 
+// StatusCode returns the http status code of a response code found in a handler, as in
+// http.StatusOK that is 200. It returns 0 for unknown codes
+func StatusCode(code string) int {
+	statusCode, _ := parseCode(code)
+	return statusCode
+}
+
 func parseCode(code string) (int, bool) {
 	// FIXME: this function should be configurable
 	codeInt, err := strconv.Atoi(code)
@@ -0,0 +1,206 @@
+package swago
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"github.com/javiercbk/swago/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+const findRoutesCriteria = `
+routes:
+  - funcRoute:
+      pkg: router
+      funcName: POST
+      pathIndex: 0
+      handlerIndex: 1
+request:
+  - pkg: api
+    funcName: Bind
+    modelExtractor:
+      paramIndex: 1
+  - pkg: api
+    funcName: BindForm
+    modelExtractor:
+      paramIndex: 1
+response:
+  - pkg: api
+    funcName: JSON
+    modelExtractor:
+      paramIndex: 2
+    codeIndex: 1
+`
+
+var findRoutesProject = map[string]string{
+	"go.mod": "module example.com/api\n",
+	"main.go": `package main
+
+import (
+	"example.com/api/handler"
+	"example.com/api/router"
+)
+
+func main() {
+	router.POST("/users", handler.CreateUser)
+	router.POST("/forms", handler.CreateForm)
+}
+`,
+	"router/router.go": `package router
+
+import "net/http"
+
+func POST(path string, handler http.HandlerFunc) {}
+`,
+	"handler/handler.go": `package handler
+
+import (
+	"net/http"
+
+	"example.com/api/api"
+	"example.com/external/errors"
+)
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Form struct {
+	Email string ` + "`json:\"email\"`" + `
+}
+
+func CreateUser(w http.ResponseWriter, r *http.Request) {
+	user := User{}
+	api.Bind(r, &user)
+	if len(user.Name) == 0 {
+		failure := errors.Failure{}
+		api.JSON(w, 400, &failure)
+		return
+	}
+	api.JSON(w, 201, &user)
+}
+
+func CreateForm(w http.ResponseWriter, r *http.Request) {
+	form := Form{}
+	api.BindForm(r, &form)
+	api.JSON(w, 201, &form)
+}
+`,
+	"api/api.go": `package api
+
+import "net/http"
+
+func Bind(r *http.Request, v interface{}) error { return nil }
+
+func BindForm(r *http.Request, v interface{}) error { return nil }
+
+func JSON(w http.ResponseWriter, code int, v interface{}) {}
+`,
+}
+
+func TestFindRoutes(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	root := writeTestProject(t, findRoutesProject)
+	defer os.RemoveAll(root)
+	c := criteria.Criteria{}
+	if err := criteria.NewCriteriaDecoder(logger).ParseCriteriaFromYAML(strings.NewReader(findRoutesCriteria), &c); err != nil {
+		t.Fatal(err)
+	}
+	sg, err := NewSwaggerGeneratorWithBlacklist(root, root, nil, logger, []*regexp.Regexp{regexp.MustCompile(`_test\.go$`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := openapi2.Swagger{}
+	if err := sg.GenerateSwaggerDoc(c, &doc); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("should try the next request criteria when a criteria matches no call", func(t *testing.T) {
+		operation := doc.Paths["/forms"].Post
+		if assert.NotNil(t, operation) && assert.Len(t, operation.Parameters, 1) {
+			assert.Equal(t, "Form", operation.Parameters[0].Name)
+		}
+	})
+	t.Run("should document a response without a model when its model is not found", func(t *testing.T) {
+		operation := doc.Paths["/users"].Post
+		if assert.NotNil(t, operation) {
+			assert.Len(t, operation.Responses, 2)
+			if assert.NotNil(t, operation.Responses["400"]) && assert.NotNil(t, operation.Responses["400"].Schema) {
+				assert.Empty(t, operation.Responses["400"].Schema.Value.Properties, "the response is documented as an object without properties")
+			}
+			assert.NotNil(t, operation.Responses["201"])
+		}
+		messages := make([]string, 0)
+		for _, d := range sg.Diagnostics.Diagnostics() {
+			if d.Route == "POST /users" && d.Severity == diagnostic.Warning {
+				messages = append(messages, d.Message)
+			}
+		}
+		assert.Equal(t, []string{"response model errors.Failure not found, package errors is not part of the project"}, messages)
+	})
+}
+
+func writeTestProject(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "swago")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filePath, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReanalyze(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	root := writeTestProject(t, findRoutesProject)
+	defer os.RemoveAll(root)
+	sg, err := NewSwaggerGeneratorWithBlacklist(root, root, nil, logger, []*regexp.Regexp{regexp.MustCompile(`_test\.go$`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlerDir := filepath.Join(root, "handler")
+	adminDir := filepath.Join(root, "admin")
+	routerDir := filepath.Join(root, "router")
+	updated := findRoutesProject["handler/handler.go"] + "\ntype Address struct {\n\tStreet string\n}\n"
+	if err := ioutil.WriteFile(filepath.Join(handlerDir, "handler.go"), []byte(updated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(adminDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(adminDir, "admin.go"), []byte("package admin\n\ntype Admin struct {\n\tName string\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(routerDir); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, sg.Reanalyze([]string{handlerDir, adminDir, routerDir}))
+	paths := make([]string, 0, len(sg.Pkgs))
+	for _, p := range sg.Pkgs {
+		paths = append(paths, p.Path)
+	}
+	assert.ElementsMatch(t, []string{root, filepath.Join(root, "api"), handlerDir, adminDir}, paths, "the package of the new directory must be added and the one of the removed directory removed")
+	address := pkg.Struct{PkgName: "handler", Name: "Address"}
+	if assert.NotNil(t, sg.getPkg("handler")) {
+		assert.Nil(t, sg.getPkg("handler").FindStruct(&address), "the modified package must be analyzed again")
+	}
+	admin := pkg.Struct{PkgName: "admin", Name: "Admin"}
+	if assert.NotNil(t, sg.getPkg("admin")) {
+		assert.Nil(t, sg.getPkg("admin").FindStruct(&admin))
+	}
+	assert.Nil(t, sg.getPkg("router"))
+}
@@ -0,0 +1,145 @@
+package swago
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"github.com/javiercbk/swago/pkg"
+)
+
+// routeTag returns the tag of a route according to the tag strategy and the tag description
+func (s *SwaggerGenerator) routeTag(r pkg.Route, projectCriterias criteria.Criteria) (string, string) {
+	handlerPkg, _ := pkg.TypeParts(r.HandlerType)
+	switch projectCriterias.Tags.Strategy {
+	case criteria.TagByReceiver:
+		if r.Handler != nil && len(r.Handler.MemberOf) > 0 {
+			receiver := strings.TrimPrefix(r.Handler.MemberOf, "*")
+			receiverStruct := pkg.Struct{
+				Name: receiver,
+			}
+			description := ""
+			pkgFound := s.pkgByPath(r.HandlerPkgPath)
+			if pkgFound != nil && pkgFound.FindStruct(&receiverStruct) == nil {
+				description = pkg.DocText(receiver, receiverStruct.Doc)
+			}
+			return receiver, description
+		}
+		// functions fallback to their package
+		return s.packageTag(handlerPkg, r.HandlerPkgPath)
+	case criteria.TagByPath:
+		isPathVar := func(segment string) bool {
+			return r.NamedPathVarExtractor != nil && r.NamedPathVarExtractor.MatchString(segment)
+		}
+		return pathTag(strings.TrimPrefix(r.Path, projectCriterias.BasePath), isPathVar), ""
+	case criteria.TagByNone:
+		return "", ""
+	default:
+		return s.packageTag(handlerPkg, r.HandlerPkgPath)
+	}
+}
+
+func (s *SwaggerGenerator) packageTag(pkgName, pkgPath string) (string, string) {
+	pkgFound := s.pkgByPath(pkgPath)
+	if pkgFound == nil {
+		return pkgName, ""
+	}
+	return pkgName, pkgFound.Doc
+}
+
+// pathTag returns the first path segment that is not a path variable
+func pathTag(path string, isPathVar func(string) bool) string {
+	for _, segment := range strings.Split(path, "/") {
+		if len(segment) > 0 && !isPathVar(segment) && !strings.HasPrefix(segment, ":") {
+			return segment
+		}
+	}
+	return ""
+}
+
+// addTag adds a tag to the swagger document unless it was already added
+func addTag(swaggerDoc *openapi2.Swagger, name, description string) {
+	if len(name) == 0 {
+		return
+	}
+	tag := swaggerDoc.Tags.Get(name)
+	if tag == nil {
+		swaggerDoc.Tags = append(swaggerDoc.Tags, &openapi3.Tag{
+			Name:        name,
+			Description: description,
+		})
+		sort.Slice(swaggerDoc.Tags, func(i, j int) bool {
+			return swaggerDoc.Tags[i].Name < swaggerDoc.Tags[j].Name
+		})
+	} else if len(tag.Description) == 0 {
+		tag.Description = description
+	}
+}
+
+// operationID returns the operation id derived from the name of the handler
+func operationID(r pkg.Route) string {
+	if r.Handler != nil {
+		return r.Handler.OperationName()
+	}
+	return pkg.OperationName(pkg.HandlerParts(r.HandlerType))
+}
+
+// annotatedOperationIDs returns the operation ids set by the annotations of the handlers,
+// they are reserved before any operation id is made unique
+func annotatedOperationIDs(routes []pkg.Route) map[string]bool {
+	ids := make(map[string]bool)
+	for _, r := range routes {
+		if r.Handler == nil || pkg.HasAnnotation(r.Handler.Annotations, pkg.AnnotationIgnore) {
+			continue
+		}
+		if id, ok := annotatedOperationID(r.Handler); ok {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+// annotatedOperationID returns the operation id of the last operationId annotation of a handler
+func annotatedOperationID(handler *pkg.Function) (string, bool) {
+	annotations := pkg.FindAnnotations(handler.Annotations, pkg.AnnotationOperationID)
+	for i := len(annotations) - 1; i >= 0; i-- {
+		if len(annotations[i].Args) > 0 {
+			return annotations[i].Args[0], true
+		}
+	}
+	return "", false
+}
+
+// reserveOperationID makes the id of an operation unique once the annotations were applied,
+// the ids generated from the handlers never take an annotated id and an annotated id that
+// is already used is reported
+func (s *SwaggerGenerator) reserveOperationID(r *pkg.Route, operation *openapi2.Operation, usedIDs, annotatedIDs map[string]bool) {
+	id := operation.OperationID
+	if len(id) == 0 {
+		return
+	}
+	annotated := false
+	if r.Handler != nil {
+		annotatedID, ok := annotatedOperationID(r.Handler)
+		annotated = ok && annotatedID == id
+	}
+	uniqueID := id
+	for i := 2; usedIDs[uniqueID] || (annotatedIDs[uniqueID] && !(annotated && uniqueID == id)); i++ {
+		uniqueID = id + strconv.Itoa(i)
+	}
+	usedIDs[uniqueID] = true
+	if uniqueID == id {
+		return
+	}
+	operation.OperationID = uniqueID
+	if !annotated {
+		return
+	}
+	annotations := pkg.FindAnnotations(r.Handler.Annotations, pkg.AnnotationOperationID)
+	pos := r.Handler.PositionOf(annotations[len(annotations)-1].Pos)
+	s.report(diagnostic.Warning, r, "", pos, "operation id %s is already used, it is documented as %s", id, uniqueID)
+}
@@ -0,0 +1,175 @@
+package swago
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/diagnostic"
+	"github.com/javiercbk/swago/pkg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathTag(t *testing.T) {
+	pathVarRegExp := regexp.MustCompile("^{[^}]+}$")
+	type params struct {
+		path string
+	}
+	type expected struct {
+		tag string
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name:     "should use the first segment",
+			params:   params{path: "/users/{id}/roles"},
+			expected: expected{tag: "users"},
+		},
+		{
+			name:     "should skip the path variables",
+			params:   params{path: "/{tenant}/:org/users"},
+			expected: expected{tag: "users"},
+		},
+		{
+			name:   "should not tag the root path",
+			params: params{path: "/"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tag := pathTag(test.params.path, pathVarRegExp.MatchString)
+			assert.Equal(t, test.expected.tag, tag)
+		})
+	}
+}
+
+func TestAddTag(t *testing.T) {
+	swaggerDoc := &openapi2.Swagger{}
+	addTag(swaggerDoc, "users", "")
+	addTag(swaggerDoc, "auth", "Authentication")
+	addTag(swaggerDoc, "users", "Users")
+	addTag(swaggerDoc, "users", "Ignored")
+	addTag(swaggerDoc, "", "No name")
+	assert.Equal(t, openapi3.Tags{
+		{Name: "auth", Description: "Authentication"},
+		{Name: "users", Description: "Users"},
+	}, swaggerDoc.Tags)
+}
+
+func TestReserveOperationID(t *testing.T) {
+	type route struct {
+		handlerType string
+		handler     *pkg.Function
+	}
+	type params struct {
+		routes []route
+	}
+	type expected struct {
+		ids         []string
+		diagnostics []string
+	}
+	handlerFile := func(pkgName string) *pkg.File {
+		return &pkg.File{Pkg: &pkg.Pkg{Name: pkgName}}
+	}
+	annotatedID := func(pkgName, name, id string) *pkg.Function {
+		return &pkg.Function{
+			File:        handlerFile(pkgName),
+			Name:        name,
+			Annotations: []pkg.Annotation{{Name: pkg.AnnotationOperationID, Args: []string{id}}},
+		}
+	}
+	tests := []struct {
+		name     string
+		params   params
+		expected expected
+	}{
+		{
+			name: "should join the package, the receiver and the name of the handler",
+			params: params{routes: []route{
+				{handlerType: "users.List", handler: &pkg.Function{File: handlerFile("users"), Name: "List"}},
+				{handlerType: "handler.UserHandler.getUser", handler: &pkg.Function{File: handlerFile("handler"), Name: "getUser", MemberOf: "*UserHandler"}},
+				{handlerType: "handler.Crud[models.User].list"},
+			}},
+			expected: expected{ids: []string{"usersList", "handlerUserHandlerGetUser", "handlerCrudList"}},
+		},
+		{
+			name: "should add a suffix to repeated ids",
+			params: params{routes: []route{
+				{handlerType: "users.List", handler: &pkg.Function{File: handlerFile("users"), Name: "List"}},
+				{handlerType: "users.List", handler: &pkg.Function{File: handlerFile("users"), Name: "List"}},
+			}},
+			expected: expected{ids: []string{"usersList", "usersList2"}},
+		},
+		{
+			name: "should not reserve the id replaced by an annotation",
+			params: params{routes: []route{
+				{handlerType: "users.Get", handler: annotatedID("users", "Get", "findUser")},
+				{handlerType: "users.Get", handler: &pkg.Function{File: handlerFile("users"), Name: "Get"}},
+			}},
+			expected: expected{ids: []string{"findUser", "usersGet"}},
+		},
+		{
+			name: "should reserve the annotated ids before the generated ones",
+			params: params{routes: []route{
+				{handlerType: "users.Get", handler: &pkg.Function{File: handlerFile("users"), Name: "Get"}},
+				{handlerType: "users.Find", handler: annotatedID("users", "Find", "usersGet")},
+			}},
+			expected: expected{ids: []string{"usersGet2", "usersGet"}},
+		},
+		{
+			name: "should not give a suffixed id that is annotated",
+			params: params{routes: []route{
+				{handlerType: "users.Get", handler: &pkg.Function{File: handlerFile("users"), Name: "Get"}},
+				{handlerType: "users.Get", handler: &pkg.Function{File: handlerFile("users"), Name: "Get"}},
+				{handlerType: "users.Find", handler: annotatedID("users", "Find", "usersGet2")},
+			}},
+			expected: expected{ids: []string{"usersGet", "usersGet3", "usersGet2"}},
+		},
+		{
+			name: "should report an annotated id that is already used",
+			params: params{routes: []route{
+				{handlerType: "users.Get", handler: annotatedID("users", "Get", "getUser")},
+				{handlerType: "users.Find", handler: annotatedID("users", "Find", "getUser")},
+			}},
+			expected: expected{
+				ids:         []string{"getUser", "getUser2"},
+				diagnostics: []string{"warning: operation id getUser is already used, it is documented as getUser2 (route GET /users)"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &SwaggerGenerator{Diagnostics: &diagnostic.List{}}
+			usedIDs := make(map[string]bool)
+			routes := make([]pkg.Route, 0, len(test.params.routes))
+			for _, tr := range test.params.routes {
+				routes = append(routes, pkg.Route{Path: "/users", HTTPMethod: "GET", HandlerType: tr.handlerType, Handler: tr.handler})
+			}
+			annotatedIDs := annotatedOperationIDs(routes)
+			ids := make([]string, 0, len(routes))
+			for i := range routes {
+				r := &routes[i]
+				operation := &openapi2.Operation{OperationID: operationID(*r)}
+				if r.Handler != nil {
+					assert.NoError(t, s.applyAnnotations(r, operation, criteria.Criteria{}, &openapi2.Swagger{}))
+				}
+				s.reserveOperationID(r, operation, usedIDs, annotatedIDs)
+				ids = append(ids, operation.OperationID)
+			}
+			assert.Equal(t, test.expected.ids, ids)
+			messages := make([]string, 0)
+			for _, d := range s.Diagnostics.Diagnostics() {
+				messages = append(messages, d.String())
+			}
+			if test.expected.diagnostics == nil {
+				test.expected.diagnostics = []string{}
+			}
+			assert.Equal(t, test.expected.diagnostics, messages)
+		})
+	}
+}
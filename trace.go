@@ -0,0 +1,100 @@
+package swago
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+
+	"github.com/javiercbk/swago/criteria"
+	"github.com/javiercbk/swago/pkg"
+)
+
+const (
+	requestTrace  = "request"
+	responseTrace = "response"
+)
+
+// TraceStep is a step of the evaluation of the criteria
+type TraceStep struct {
+	// Route is the method and the path of the route evaluated, it is empty for the steps
+	// that are not about a single route
+	Route    string
+	Position token.Position
+	Message  string
+}
+
+// Tracer receives every step of the evaluation of the criteria
+type Tracer func(step TraceStep)
+
+func (s *SwaggerGenerator) trace(r *pkg.Route, pos token.Position, format string, args ...interface{}) {
+	if s.Tracer == nil {
+		return
+	}
+	step := TraceStep{
+		Position: pos,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if r != nil {
+		step.Route = r.Endpoint()
+	}
+	s.Tracer(step)
+}
+
+// traceRoute traces the criteria that matched a route and the middlewares found
+func (s *SwaggerGenerator) traceRoute(r *pkg.Route) {
+	if s.Tracer == nil {
+		return
+	}
+	s.trace(r, r.Position, "matched %s", r.Criteria)
+	for _, m := range r.Middlewares {
+		s.trace(r, r.Position, "middleware %s", m)
+	}
+	securityDefinitions := make([]string, 0, len(r.MatchedSecurityDefinitions))
+	for name := range r.MatchedSecurityDefinitions {
+		securityDefinitions = append(securityDefinitions, name)
+	}
+	sort.Strings(securityDefinitions)
+	for _, name := range securityDefinitions {
+		s.trace(r, r.Position, "security %s matched a middleware", name)
+	}
+}
+
+// traceCalls traces the calls of a handler considered by a request or response criteria
+func (s *SwaggerGenerator) traceCalls(r *pkg.Route, handler pkg.Function, kind string, callCriteria criteria.CallCriteria) {
+	if s.Tracer == nil {
+		return
+	}
+	name := callCriteria.Pkg + "." + callCriteria.FuncName
+	matched := false
+	for _, call := range handler.TraceCalls(callCriteria) {
+		if !call.Matched {
+			if len(call.Call) > 0 {
+				s.trace(r, call.Position, "%s criteria %s: skipped call to %s", kind, name, call.Call)
+			}
+			continue
+		}
+		if kind == responseTrace {
+			// the responses found are traced with their status code and type
+			matched = true
+			continue
+		}
+		if matched {
+			s.trace(r, call.Position, "%s criteria %s: call ignored, only the first one is used", kind, name)
+			continue
+		}
+		matched = call.Used
+		switch {
+		case len(call.Arg) == 0:
+			s.trace(r, call.Position, "%s criteria %s: call has no argument %d", kind, name, callCriteria.ModelExtractor.ParamIndex)
+		case len(call.Variable) == 0:
+			s.trace(r, call.Position, "%s criteria %s: argument %s is not a variable", kind, name, call.Arg)
+		case len(call.VariableType) == 0:
+			s.trace(r, call.Position, "%s criteria %s: the type of %s can not be inferred, only arguments and variables assigned a composite literal are", kind, name, call.Variable)
+		default:
+			s.trace(r, call.Position, "%s criteria %s: argument %s has type %s", kind, name, call.Arg, call.VariableType)
+		}
+	}
+	if !matched {
+		s.trace(r, handler.Position(), "%s criteria %s: no call matched in %s", kind, name, handler.Name)
+	}
+}
@@ -0,0 +1,42 @@
+package swago
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/javiercbk/swago/criteria"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceSchemaDiagnostics(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	project := make(map[string]string)
+	for name, src := range findRoutesProject {
+		project[name] = src
+	}
+	project["handler/handler.go"] = strings.Replace(findRoutesProject["handler/handler.go"], "type Form struct {\n", "type Form struct {\n\tOther Missing\n", 1)
+	root := writeTestProject(t, project)
+	defer os.RemoveAll(root)
+	c := criteria.Criteria{}
+	if err := criteria.NewCriteriaDecoder(logger).ParseCriteriaFromYAML(strings.NewReader(findRoutesCriteria), &c); err != nil {
+		t.Fatal(err)
+	}
+	sg, err := NewSwaggerGeneratorWithBlacklist(root, root, nil, logger, []*regexp.Regexp{regexp.MustCompile(`_test\.go$`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := make(map[string][]string)
+	sg.Tracer = func(step TraceStep) {
+		steps[step.Route] = append(steps[step.Route], step.Message)
+	}
+	if err := sg.GenerateSwaggerDoc(c, &openapi2.Swagger{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, steps["POST /forms"], "type handler.Missing of field handler.Form.Other not found, it is documented as an object")
+	assert.NotContains(t, steps["POST /users"], "type handler.Missing of field handler.Form.Other not found, it is documented as an object")
+}